@@ -0,0 +1,49 @@
+package extraction
+
+import "strings"
+
+// minBoilerplatePages is the fewest crawled pages needed before repeated
+// blocks are treated as boilerplate; with fewer, shared lines are as likely
+// to be coincidence
+const minBoilerplatePages = 3
+
+// stripSharedBoilerplate removes lines that appear on more than half of the
+// crawled pages, such as site navigation, headers and footers, from every
+// page's content. Pages that failed to load are ignored.
+func stripSharedBoilerplate(pages []SubPageResult) {
+	counts := make(map[string]int)
+	loaded := 0
+	for _, page := range pages {
+		if page.Error != "" || page.Content == "" {
+			continue
+		}
+		loaded++
+		// Count each line once per page
+		seen := make(map[string]bool)
+		for _, line := range strings.Split(page.Content, "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !seen[line] {
+				seen[line] = true
+				counts[line]++
+			}
+		}
+	}
+
+	if loaded < minBoilerplatePages {
+		return
+	}
+
+	for i := range pages {
+		if pages[i].Error != "" || pages[i].Content == "" {
+			continue
+		}
+		var kept []string
+		for _, line := range strings.Split(pages[i].Content, "\n") {
+			if counts[strings.TrimSpace(line)]*2 > loaded {
+				continue
+			}
+			kept = append(kept, line)
+		}
+		pages[i].Content = CleanText(strings.Join(kept, "\n"))
+	}
+}
@@ -0,0 +1,47 @@
+package extraction
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripSharedBoilerplate(t *testing.T) {
+	nav := "Home\nProducts\nAbout us\nContact"
+	footer := "© 2024 Example Corp. All rights reserved."
+
+	pages := []SubPageResult{
+		{URL: "https://example.com/a", Content: nav + "\n\nPricing starts at $10 per month.\n\n" + footer},
+		{URL: "https://example.com/b", Content: nav + "\n\nOur team is based in Berlin.\n\n" + footer},
+		{URL: "https://example.com/c", Content: nav + "\n\nEmail support@example.com for help.\n\n" + footer},
+		{URL: "https://example.com/d", Error: "timeout"},
+	}
+
+	stripSharedBoilerplate(pages)
+
+	want := []string{
+		"Pricing starts at $10 per month.",
+		"Our team is based in Berlin.",
+		"Email support@example.com for help.",
+	}
+	for i, w := range want {
+		if pages[i].Content != w {
+			t.Errorf("page %d: expected %q, got %q", i, w, pages[i].Content)
+		}
+		if strings.Contains(pages[i].Content, "Products") {
+			t.Errorf("page %d: expected the nav block to be removed", i)
+		}
+	}
+}
+
+func TestStripSharedBoilerplate_TooFewPages(t *testing.T) {
+	pages := []SubPageResult{
+		{Content: "Home\n\nFirst page"},
+		{Content: "Home\n\nSecond page"},
+	}
+
+	stripSharedBoilerplate(pages)
+
+	if pages[0].Content != "Home\n\nFirst page" {
+		t.Errorf("expected content to be left alone with only two pages, got %q", pages[0].Content)
+	}
+}
@@ -0,0 +1,239 @@
+package extraction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/chromedp"
+	"github.com/liliang-cn/mcp-websearch-server/utils"
+)
+
+// sharedBrowser keeps one headless Chrome running so extractions can open
+// tabs on it instead of launching a new browser each time.
+type sharedBrowser struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	launch func() (context.Context, context.CancelFunc, error)
+}
+
+func newSharedBrowser() *sharedBrowser {
+	return &sharedBrowser{launch: browserLauncher(defaultChromeConfig())}
+}
+
+// RemoteChromeEnv names the environment variable that, set to the DevTools
+// WebSocket URL of a running Chrome such as ws://chrome:9222, makes
+// browsers be dialed there instead of launched locally
+const RemoteChromeEnv = "CHROME_REMOTE_URL"
+
+// chromeConfig says where a browser comes from: the running Chrome at
+// remoteURL, or a local one launched behind proxyServer if set. A remote
+// Chrome keeps its own proxy settings.
+type chromeConfig struct {
+	remoteURL   string
+	proxyServer string
+}
+
+// defaultChromeConfig dials the Chrome named by RemoteChromeEnv, if any
+func defaultChromeConfig() chromeConfig {
+	return chromeConfig{remoteURL: os.Getenv(RemoteChromeEnv)}
+}
+
+// allocator returns the chromedp allocator for c
+func (c chromeConfig) allocator(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.remoteURL != "" {
+		return chromedp.NewRemoteAllocator(ctx, c.remoteURL)
+	}
+	return chromedp.NewExecAllocator(ctx, allocatorOptions(c.proxyServer)...)
+}
+
+// allocatorOptions returns Chrome's default flags, routing its traffic
+// through proxyServer when set
+func allocatorOptions(proxyServer string) []chromedp.ExecAllocatorOption {
+	opts := append([]chromedp.ExecAllocatorOption(nil), chromedp.DefaultExecAllocatorOptions[:]...)
+	if proxyServer != "" {
+		opts = append(opts, chromedp.ProxyServer(proxyServer))
+	}
+	return opts
+}
+
+// browserLauncher returns a function that starts or connects to the
+// browser c describes and returns its browser context
+func browserLauncher(c chromeConfig) func() (context.Context, context.CancelFunc, error) {
+	return func() (context.Context, context.CancelFunc, error) {
+		allocCtx, cancelAlloc := c.allocator(context.Background())
+		browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+
+		// Running with no actions launches the browser
+		if err := chromedp.Run(browserCtx); err != nil {
+			cancelBrowser()
+			cancelAlloc()
+			return nil, nil, fmt.Errorf("failed to launch browser: %w", err)
+		}
+
+		return browserCtx, func() {
+			cancelBrowser()
+			cancelAlloc()
+		}, nil
+	}
+}
+
+// newBrowserContext opens the browser c describes for a single call, once
+// the browser concurrency cap allows another tab
+func newBrowserContext(ctx context.Context, c chromeConfig) (context.Context, context.CancelFunc) {
+	release, err := browserTabs.acquire(ctx)
+	if err != nil {
+		// ctx is done, so the caller's first action fails with its error
+		release = func() {}
+	}
+
+	if c == (chromeConfig{}) {
+		tabCtx, cancelTab := chromedp.NewContext(ctx)
+		return tabCtx, func() {
+			cancelTab()
+			release()
+		}
+	}
+	allocCtx, cancelAlloc := c.allocator(ctx)
+	tabCtx, cancelTab := chromedp.NewContext(allocCtx)
+	return tabCtx, func() {
+		cancelTab()
+		cancelAlloc()
+		release()
+	}
+}
+
+// NewBrowserContext returns a chromedp context for a single call, in a new
+// tab of the running Chrome at remoteURL if set, else in a freshly
+// launched local Chrome. Cancel it to close the tab or browser.
+func NewBrowserContext(ctx context.Context, remoteURL string) (context.Context, context.CancelFunc) {
+	return newBrowserContext(ctx, chromeConfig{remoteURL: remoteURL})
+}
+
+// NewProxiedBrowserContext is NewBrowserContext with a locally launched
+// Chrome's traffic sent through proxyServer, a URL already checked with
+// utils.ParseProxyURL. A remote Chrome keeps its own proxy settings.
+func NewProxiedBrowserContext(ctx context.Context, remoteURL, proxyServer string) (context.Context, context.CancelFunc) {
+	return newBrowserContext(ctx, chromeConfig{remoteURL: remoteURL, proxyServer: proxyServer})
+}
+
+// parseProxyServer validates proxyURL for Chrome's --proxy-server flag
+func parseProxyServer(proxyURL string) (string, error) {
+	u, err := utils.ParseProxyURL(proxyURL)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// proxyTransport returns an HTTP transport that sends requests through
+// proxyServer, a URL already checked by parseProxyServer, for downloads
+// made alongside the browser
+func proxyTransport(proxyServer string) *http.Transport {
+	u, _ := url.Parse(proxyServer)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(u)
+	return transport
+}
+
+// start launches the browser if it isn't already running
+func (b *sharedBrowser) start() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ctx != nil && b.ctx.Err() == nil {
+		return nil
+	}
+
+	ctx, cancel, err := b.launch()
+	if err != nil {
+		return err
+	}
+	b.ctx, b.cancel = ctx, cancel
+	return nil
+}
+
+// restart replaces a crashed or closed browser with a fresh one. A browser
+// that was never started is left alone, since each extraction then
+// launches its own.
+func (b *sharedBrowser) restart() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.cancel == nil {
+		return nil
+	}
+	b.cancel()
+	b.ctx, b.cancel = nil, nil
+
+	ctx, cancel, err := b.launch()
+	if err != nil {
+		return err
+	}
+	b.ctx, b.cancel = ctx, cancel
+	return nil
+}
+
+// browserGone reports whether err means the browser or its tab died, as
+// opposed to the page failing to load or ctx running out
+func browserGone(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, chromedp.ErrChannelClosed) || errors.Is(err, chromedp.ErrInvalidContext) || errors.Is(err, context.Canceled) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "target crashed") || strings.Contains(msg, "target closed")
+}
+
+// ready reports whether the browser is running
+func (b *sharedBrowser) ready() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.ctx != nil && b.ctx.Err() == nil
+}
+
+// newTab opens a tab on the running browser once the browser concurrency
+// cap allows, or returns ok=false if the browser hasn't been started or ctx
+// ended while waiting. The tab is closed when ctx is done or the returned
+// cancel func is called.
+func (b *sharedBrowser) newTab(ctx context.Context) (tabCtx context.Context, cancel context.CancelFunc, ok bool) {
+	b.mu.Lock()
+	browserCtx := b.ctx
+	b.mu.Unlock()
+
+	if browserCtx == nil || browserCtx.Err() != nil {
+		return nil, nil, false
+	}
+
+	release, err := browserTabs.acquire(ctx)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	tabCtx, cancelTab := chromedp.NewContext(browserCtx)
+	stop := context.AfterFunc(ctx, cancelTab)
+	return tabCtx, func() {
+		stop()
+		cancelTab()
+		release()
+	}, true
+}
+
+// close shuts the browser down. It is safe to call more than once.
+func (b *sharedBrowser) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.cancel != nil {
+		b.cancel()
+	}
+	b.ctx, b.cancel = nil, nil
+}
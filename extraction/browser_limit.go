@@ -0,0 +1,80 @@
+package extraction
+
+import (
+	"context"
+	"sync"
+)
+
+// tabLimiter caps how many browser tabs are open at once
+type tabLimiter struct {
+	mu sync.Mutex
+	// max is the cap; zero or less means no cap
+	max int
+	// open counts the tabs holding a slot, even while there is no cap, so
+	// that a cap set later accounts for them
+	open int
+	// changed is closed, and replaced, whenever a slot frees up or the cap
+	// changes, waking the waiters to try again
+	changed chan struct{}
+}
+
+func newTabLimiter() *tabLimiter {
+	return &tabLimiter{changed: make(chan struct{})}
+}
+
+// browserTabs bounds the tabs opened by every extractor, deep reader and
+// browser-based search engine in the process
+var browserTabs = newTabLimiter()
+
+// SetMaxBrowserConcurrency caps how many chromedp tabs, across every
+// extractor, DeepReader and browser-based search engine in the process,
+// are open at once. Opening a tab over the cap waits for another to close
+// or for its context to end. Zero or a negative n removes the cap, which is
+// the default. Lowering the cap doesn't close tabs already open.
+func SetMaxBrowserConcurrency(n int) {
+	browserTabs.setMax(n)
+}
+
+func (l *tabLimiter) setMax(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.max = n
+	l.notify()
+}
+
+// acquire waits for a free slot and returns the func that gives it back,
+// which is safe to call more than once. It fails with ctx's error if ctx
+// ends first.
+func (l *tabLimiter) acquire(ctx context.Context) (release func(), err error) {
+	for {
+		l.mu.Lock()
+		if l.max <= 0 || l.open < l.max {
+			l.open++
+			l.mu.Unlock()
+
+			var once sync.Once
+			return func() { once.Do(l.release) }, nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (l *tabLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.open--
+	l.notify()
+}
+
+// notify wakes the waiters; l.mu must be held
+func (l *tabLimiter) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
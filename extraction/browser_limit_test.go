@@ -0,0 +1,86 @@
+package extraction
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTabLimiter_WaitsForFreeSlot(t *testing.T) {
+	l := newTabLimiter()
+	l.setMax(1)
+
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	acquired := make(chan func())
+	go func() {
+		r, err := l.acquire(context.Background())
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		acquired <- r
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("expected the second acquire to wait while the cap is reached")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	// Releasing twice must not free a second slot
+	release()
+
+	select {
+	case r := <-acquired:
+		r()
+	case <-time.After(time.Second):
+		t.Fatal("expected the second acquire once the first slot was released")
+	}
+	if l.open != 0 {
+		t.Errorf("expected no open tabs, got %d", l.open)
+	}
+}
+
+func TestTabLimiter_ContextEnds(t *testing.T) {
+	l := newTabLimiter()
+	l.setMax(1)
+	release, _ := l.acquire(context.Background())
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if l.open != 1 {
+		t.Errorf("expected the failed acquire to hold no slot, got %d open", l.open)
+	}
+}
+
+func TestTabLimiter_RaisingCapWakesWaiters(t *testing.T) {
+	l := newTabLimiter()
+	l.setMax(1)
+	release, _ := l.acquire(context.Background())
+	defer release()
+
+	acquired := make(chan struct{})
+	go func() {
+		r, err := l.acquire(context.Background())
+		if err == nil {
+			defer r()
+		}
+		close(acquired)
+	}()
+
+	l.setMax(0)
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("expected removing the cap to let the waiter through")
+	}
+}
@@ -3,6 +3,7 @@ package extraction
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -11,30 +12,95 @@ import (
 
 type ChromedpExtractor struct {
 	timeout time.Duration
+	reflow  bool
+	// pool, when set, supplies the tabs instead of a browser per call
+	pool *BrowserPool
+	// chrome is where the extractor's browsers come from; proxyErr records
+	// an invalid proxy and fails every extraction
+	chrome   chromeConfig
+	proxyErr error
+	// client sends PageInfo's HEAD requests
+	client *http.Client
+	// viewportWidth and viewportHeight size the tab, zero for Chrome's
+	// default
+	viewportWidth  int
+	viewportHeight int
+	// waitSelector is an element to wait for before reading the page
+	waitSelector string
 }
 
-func NewChromedpExtractor() *ChromedpExtractor {
-	return &ChromedpExtractor{
-		timeout: 30 * time.Second,
+// ChromedpOption configures a ChromedpExtractor
+type ChromedpOption func(*ChromedpExtractor)
+
+// WithReflow joins lines that the page's styling split mid-sentence back
+// into paragraphs, see ReflowText. It is off by default.
+func WithReflow(enabled bool) ChromedpOption {
+	return func(e *ChromedpExtractor) {
+		e.reflow = enabled
 	}
 }
 
-func (e *ChromedpExtractor) ExtractContent(ctx context.Context, url string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, e.timeout)
-	defer cancel()
+// WithChromedpPool loads pages on tabs from pool instead of launching a
+// browser per call. The caller owns the pool and closes it.
+func WithChromedpPool(pool *BrowserPool) ChromedpOption {
+	return func(e *ChromedpExtractor) {
+		e.pool = pool
+	}
+}
 
-	allocCtx, cancel := chromedp.NewContext(ctx)
-	defer cancel()
+// WithChromedpProxyServer starts the extractor's browsers behind the proxy
+// at proxyURL and sends its HEAD requests through it; see WithProxyServer
+func WithChromedpProxyServer(proxyURL string) ChromedpOption {
+	return func(e *ChromedpExtractor) {
+		e.chrome.proxyServer, e.proxyErr = parseProxyServer(proxyURL)
+		if e.proxyErr == nil {
+			e.client = &http.Client{Timeout: headClient.Timeout, Transport: proxyTransport(e.chrome.proxyServer)}
+		}
+	}
+}
 
-	var content string
-	var title string
-	var bodyText string
+// WithChromedpRemoteChrome loads pages in the running Chrome at wsURL; see
+// WithRemoteChrome
+func WithChromedpRemoteChrome(wsURL string) ChromedpOption {
+	return func(e *ChromedpExtractor) {
+		e.chrome.remoteURL = wsURL
+	}
+}
 
-	err := chromedp.Run(allocCtx,
-		chromedp.Navigate(url),
-		chromedp.WaitReady("body"),
-		chromedp.Title(&title),
-		chromedp.Evaluate(`
+// WithViewport sizes the tab's viewport in CSS pixels, which sets the
+// width of screenshots and of pages with responsive layouts
+func WithViewport(width, height int) ChromedpOption {
+	return func(e *ChromedpExtractor) {
+		if width > 0 && height > 0 {
+			e.viewportWidth, e.viewportHeight = width, height
+		}
+	}
+}
+
+// WithWaitSelector waits for an element matching selector to be visible
+// before the page is read or captured, so single-page apps can finish
+// rendering
+func WithWaitSelector(selector string) ChromedpOption {
+	return func(e *ChromedpExtractor) {
+		e.waitSelector = selector
+	}
+}
+
+func NewChromedpExtractor(opts ...ChromedpOption) *ChromedpExtractor {
+	e := &ChromedpExtractor{
+		timeout: 30 * time.Second,
+		chrome:  defaultChromeConfig(),
+		client:  headClient,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// mainContentScript returns the text of the page's main content area,
+// falling back to the whole body
+const mainContentScript = `
 			(function() {
 				// Remove script and style elements
 				var scripts = document.querySelectorAll('script, style, noscript');
@@ -49,47 +115,179 @@ func (e *ChromedpExtractor) ExtractContent(ctx context.Context, url string) (str
 				// Fallback to body text
 				return document.body.innerText;
 			})()
-		`, &bodyText),
-	)
+		`
+
+// mainContentHTMLScript returns the HTML of the same main content area as
+// mainContentScript, falling back to the whole body
+const mainContentHTMLScript = `
+			(function() {
+				var scripts = document.querySelectorAll('script, style, noscript');
+				scripts.forEach(function(el) { el.remove(); });
+
+				var mainContent = document.querySelector('main, article, .content, #content, .post, .entry-content');
+				return (mainContent || document.body).outerHTML;
+			})()
+		`
+
+// bodyTextScript returns the whole body's text after removing navigation,
+// headers, footers and other boilerplate
+const bodyTextScript = `
+			(function() {
+				var boilerplate = document.querySelectorAll(
+					'script, style, noscript, nav, header, footer, aside, form, iframe, ' +
+					'[role="navigation"], [role="banner"], [role="contentinfo"], [aria-hidden="true"]');
+				boilerplate.forEach(function(el) { el.remove(); });
+				return document.body.innerText;
+			})()
+		`
+
+// newTab opens a tab from the pool if there is one, otherwise it launches
+// a fresh browser for this call. release closes the tab; broken reports
+// that the browser crashed.
+func (e *ChromedpExtractor) newTab(ctx context.Context) (tabCtx context.Context, release func(broken bool), err error) {
+	if e.proxyErr != nil {
+		return nil, nil, e.proxyErr
+	}
+	if e.pool != nil {
+		return e.pool.proxiedTab(ctx, e.chrome.proxyServer)
+	}
+	tabCtx, cancel := newBrowserContext(ctx, e.chrome)
+	return tabCtx, func(bool) { cancel() }, nil
+}
+
+// loadActions navigates to url in the configured viewport and waits until
+// the page is ready to read
+func (e *ChromedpExtractor) loadActions(url string) []chromedp.Action {
+	var actions []chromedp.Action
+	if e.viewportWidth > 0 {
+		actions = append(actions, chromedp.EmulateViewport(int64(e.viewportWidth), int64(e.viewportHeight)))
+	}
+	actions = append(actions, chromedp.Navigate(url), chromedp.WaitReady("body"))
+	if e.waitSelector != "" {
+		actions = append(actions, chromedp.WaitVisible(e.waitSelector))
+	}
+	return actions
+}
+
+func (e *ChromedpExtractor) ExtractContent(ctx context.Context, url string) (string, error) {
+	page, err := e.ExtractPage(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	return page.Content, nil
+}
+
+// ExtractPage extracts the same content as ExtractContent, along with the
+// main document's HTTP status, post-redirect URL and Last-Modified header
+func (e *ChromedpExtractor) ExtractPage(ctx context.Context, url string) (*Page, error) {
+	return e.extractText(ctx, url, mainContentScript)
+}
 
+// ExtractBodyText extracts the text of the whole page body with
+// boilerplate such as navigation and footers removed. It catches pages
+// whose content isn't in a recognizable main-content element.
+func (e *ChromedpExtractor) ExtractBodyText(ctx context.Context, url string) (string, error) {
+	page, err := e.extractText(ctx, url, bodyTextScript)
 	if err != nil {
-		return "", fmt.Errorf("failed to extract content from %s: %w", url, err)
+		return "", err
+	}
+	return page.Content, nil
+}
+
+// extractText loads url and returns the page with the text produced by
+// script as its content, prefixed with the page title
+func (e *ChromedpExtractor) extractText(ctx context.Context, url, script string) (*Page, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	allocCtx, release, err := e.newTab(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a tab for %s: %w", url, err)
+	}
+
+	page := &Page{URL: url, FinalURL: url}
+	var bodyText string
+
+	resp, err := chromedp.RunResponse(allocCtx, e.loadActions(url)...)
+	if err == nil {
+		if resp != nil {
+			page.StatusCode = int(resp.Status)
+			page.FinalURL = resp.URL
+			page.LastModified = lastModifiedHeader(responseHeader(resp.Headers, "Last-Modified"))
+		}
+		err = chromedp.Run(allocCtx,
+			chromedp.Title(&page.Title),
+			chromedp.Evaluate(script, &bodyText),
+		)
+	}
+	release(browserGone(ctx, err))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract content from %s: %w", url, err)
 	}
 
 	bodyText = CleanText(bodyText)
+	if e.reflow {
+		bodyText = ReflowText(bodyText)
+	}
 
-	if title != "" {
-		content = fmt.Sprintf("# %s\n\n%s", title, bodyText)
+	if page.Title != "" {
+		page.Content = fmt.Sprintf("# %s\n\n%s", page.Title, bodyText)
 	} else {
-		content = bodyText
+		page.Content = bodyText
 	}
+	page.ContentHash = ContentHash(page.Content)
 
-	return content, nil
+	return page, nil
 }
 
-func (e *ChromedpExtractor) CaptureScreenshot(ctx context.Context, url string, fullPage bool) ([]byte, error) {
+// ExtractMarkdown extracts the same main content as ExtractContent, but as
+// Markdown that keeps its headings, emphasis, lists and links, prefixed
+// with the page title. Relative links are resolved against the page's URL.
+func (e *ChromedpExtractor) ExtractMarkdown(ctx context.Context, url string) (string, error) {
 	ctx, cancel := context.WithTimeout(ctx, e.timeout)
 	defer cancel()
 
-	allocCtx, cancel := chromedp.NewContext(ctx)
+	allocCtx, release, err := e.newTab(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to open a tab for %s: %w", url, err)
+	}
+
+	var title, location, contentHTML string
+	err = chromedp.Run(allocCtx, append(e.loadActions(url),
+		chromedp.Title(&title),
+		chromedp.Location(&location),
+		chromedp.Evaluate(mainContentHTMLScript, &contentHTML),
+	)...)
+	release(browserGone(ctx, err))
+
+	if err != nil {
+		return "", fmt.Errorf("failed to extract content from %s: %w", url, err)
+	}
+
+	return markdownFromHTML(contentHTML, location, title)
+}
+
+// CaptureScreenshot loads url and returns a PNG of the viewport, or of the
+// whole page when fullPage is set
+func (e *ChromedpExtractor) CaptureScreenshot(ctx context.Context, url string, fullPage bool) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
 	defer cancel()
 
+	allocCtx, release, err := e.newTab(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a tab for %s: %w", url, err)
+	}
+
 	var buf []byte
-	var err error
 
+	// FullScreenshot only encodes PNG at quality 100
+	capture := chromedp.CaptureScreenshot(&buf)
 	if fullPage {
-		err = chromedp.Run(allocCtx,
-			chromedp.Navigate(url),
-			chromedp.WaitReady("body"),
-			chromedp.FullScreenshot(&buf, 90),
-		)
-	} else {
-		err = chromedp.Run(allocCtx,
-			chromedp.Navigate(url),
-			chromedp.WaitReady("body"),
-			chromedp.CaptureScreenshot(&buf),
-		)
+		capture = chromedp.FullScreenshot(&buf, 100)
 	}
+	err = chromedp.Run(allocCtx, append(e.loadActions(url), capture)...)
+	release(browserGone(ctx, err))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to capture screenshot from %s: %w", url, err)
@@ -72,3 +72,55 @@ func TestCleanText_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestHybridExtractor_Warmup(t *testing.T) {
+	extractor := NewHybridExtractor()
+
+	launches := 0
+	extractor.browser.launch = func() (context.Context, context.CancelFunc, error) {
+		launches++
+		ctx, cancel := context.WithCancel(context.Background())
+		return ctx, cancel, nil
+	}
+
+	if extractor.browser.ready() {
+		t.Fatal("browser should not be ready before Warmup")
+	}
+
+	if err := extractor.Warmup(context.Background()); err != nil {
+		t.Fatalf("unexpected warmup error: %v", err)
+	}
+
+	if !extractor.browser.ready() {
+		t.Error("expected a ready browser context after Warmup")
+	}
+
+	// A second warmup reuses the running browser
+	extractor.Warmup(context.Background())
+	if launches != 1 {
+		t.Errorf("expected browser to be launched once, got %d", launches)
+	}
+
+	extractor.Close()
+	if extractor.browser.ready() {
+		t.Error("expected browser to be shut down after Close")
+	}
+}
+
+func TestChromedpExtractor_LoadActions(t *testing.T) {
+	if n := len(NewChromedpExtractor().loadActions("https://example.com")); n != 2 {
+		t.Errorf("expected navigate and wait by default, got %d actions", n)
+	}
+
+	e := NewChromedpExtractor(WithViewport(1280, 800), WithWaitSelector("#app"))
+	if e.viewportWidth != 1280 || e.viewportHeight != 800 || e.waitSelector != "#app" {
+		t.Errorf("unexpected options: %+v", e)
+	}
+	if n := len(e.loadActions("https://example.com")); n != 4 {
+		t.Errorf("expected viewport, navigate and both waits, got %d actions", n)
+	}
+
+	if e := NewChromedpExtractor(WithViewport(0, 800)); e.viewportWidth != 0 {
+		t.Error("expected an incomplete viewport to be ignored")
+	}
+}
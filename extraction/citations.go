@@ -0,0 +1,76 @@
+package extraction
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Citation is a link from a page's main content to a source it cites
+type Citation struct {
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+// boilerplateSelector matches page furniture whose links aren't citations
+const boilerplateSelector = `nav, header, footer, aside, form, script, style, noscript,
+	[role=navigation], [role=banner], [role=contentinfo], [role=complementary],
+	.sidebar, .nav, .navbar, .menu, .breadcrumb, .breadcrumbs, .share, .social,
+	.related, .comments, .ad, .ads, .advert, .advertisement`
+
+// mainContentSelector matches the element holding a page's main content, in
+// order of preference
+var mainContentSelector = []string{"article", "main", "[role=main]", ".entry-content", ".post", "#content", ".content"}
+
+// parseCitations returns the links in an HTML document's main content,
+// leaving out navigation, sidebars and ads. Relative URLs are resolved
+// against pageURL, links to the page itself are skipped, and each URL is
+// listed once.
+func parseCitations(pageURL, htmlContent string) ([]Citation, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	base, _ := url.Parse(pageURL)
+
+	doc.Find(boilerplateSelector).Remove()
+
+	content := doc.Find("body")
+	for _, sel := range mainContentSelector {
+		if found := doc.Find(sel).First(); found.Length() > 0 {
+			content = found
+			break
+		}
+	}
+
+	var citations []Citation
+	seen := make(map[string]bool)
+	content.Find("a[href]").Each(func(i int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		u, err := url.Parse(resolveURL(base, strings.TrimSpace(href)))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return
+		}
+		u.Fragment = ""
+
+		// In-page anchors such as footnote markers point back at the page
+		if base != nil && u.Host == base.Host && u.Path == base.Path && u.RawQuery == base.RawQuery {
+			return
+		}
+
+		link := u.String()
+		if seen[link] {
+			return
+		}
+		seen[link] = true
+
+		citations = append(citations, Citation{
+			Text: strings.Join(strings.Fields(a.Text()), " "),
+			URL:  link,
+		})
+	})
+
+	return citations, nil
+}
@@ -0,0 +1,40 @@
+package extraction
+
+import "testing"
+
+const citationFixture = `<html><body>
+<header><a href="/">Home</a></header>
+<nav><a href="/news">News</a> <a href="/about">About</a></nav>
+<article>
+  <h1>Sea levels are rising faster</h1>
+  <p>According to <a href="https://www.nasa.gov/sea-level">NASA data</a>, the rate has doubled.
+  An earlier <a href="/2019/sea-level-report">report</a> found the same trend<a href="#fn1">[1]</a>.</p>
+  <p>See the <a href="https://www.nasa.gov/sea-level#chart">chart</a> and
+  <a href="https://doi.org/10.1000/xyz">the underlying paper</a>.</p>
+  <p><a href="mailto:editor@example.com">Email the editor</a></p>
+</article>
+<aside class="sidebar"><a href="https://ads.example.net/offer">Sponsored</a></aside>
+<footer><a href="/privacy">Privacy</a></footer>
+</body></html>`
+
+func TestParseCitations(t *testing.T) {
+	citations, err := parseCitations("https://example.com/2024/sea-levels", citationFixture)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Citation{
+		{Text: "NASA data", URL: "https://www.nasa.gov/sea-level"},
+		{Text: "report", URL: "https://example.com/2019/sea-level-report"},
+		{Text: "the underlying paper", URL: "https://doi.org/10.1000/xyz"},
+	}
+
+	if len(citations) != len(want) {
+		t.Fatalf("expected %d citations, got %d: %+v", len(want), len(citations), citations)
+	}
+	for i, c := range citations {
+		if c != want[i] {
+			t.Errorf("citation %d: expected %+v, got %+v", i, want[i], c)
+		}
+	}
+}
@@ -2,9 +2,9 @@ package extraction
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
-	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -27,6 +27,7 @@ type SubPageResult struct {
 	Content  string `json:"content"`
 	LinkText string `json:"link_text"`
 	Error    string `json:"error,omitempty"`
+	Depth    int    `json:"depth,omitempty"`
 }
 
 // DeepReadResult represents the complete deep read output
@@ -46,15 +47,34 @@ type DeepReader struct {
 	sameDomain   bool
 	contentLimit int
 	concurrency  int
+	// maxLinkTextLength caps link text, both when collected from the page
+	// and when ranking links by text length
+	maxLinkTextLength int
+	// depth is how many levels of links are followed, maxPages caps the
+	// sub-pages crawled across all levels
+	depth    int
+	maxPages int
+	// stripBoilerplate removes navigation and other text repeated across
+	// most sub-pages
+	stripBoilerplate bool
+	// fetchPage reads a page's text and links; replaced in tests
+	fetchPage func(ctx context.Context, url string) (*fetchedPage, error)
+	// robots is consulted before each sub-page is crawled; nil when
+	// robots.txt is ignored
+	robots *robotsCache
 }
 
 // DeepReaderOption configures the DeepReader
 type DeepReaderOption func(*DeepReader)
 
-// WithMaxLinks sets the maximum number of links to crawl
+// MaxLinksLimit is the most links WithMaxLinks accepts
+const MaxLinksLimit = 20
+
+// WithMaxLinks sets the maximum number of links to crawl, up to
+// MaxLinksLimit
 func WithMaxLinks(n int) DeepReaderOption {
 	return func(d *DeepReader) {
-		if n > 0 && n <= 20 {
+		if n > 0 && n <= MaxLinksLimit {
 			d.maxLinks = n
 		}
 	}
@@ -76,6 +96,58 @@ func WithContentLimit(limit int) DeepReaderOption {
 	}
 }
 
+// WithMaxLinkTextLength sets how many characters of each link's text are
+// kept. Longer texts are truncated, so links past the cap tie when ranked
+// by text length.
+func WithMaxLinkTextLength(n int) DeepReaderOption {
+	return func(d *DeepReader) {
+		if n > 0 {
+			d.maxLinkTextLength = n
+		}
+	}
+}
+
+// WithDepth sets how many levels of sub-pages are crawled, from 1 (the
+// default, links on the main page only) up to 3
+func WithDepth(n int) DeepReaderOption {
+	return func(d *DeepReader) {
+		if n > 0 && n <= 3 {
+			d.depth = n
+		}
+	}
+}
+
+// WithMaxPages caps the total number of sub-pages crawled across all
+// levels
+func WithMaxPages(n int) DeepReaderOption {
+	return func(d *DeepReader) {
+		if n > 0 {
+			d.maxPages = n
+		}
+	}
+}
+
+// WithStripBoilerplate sets whether text repeated across most crawled
+// sub-pages, such as site navigation and footers, is removed from each
+// sub-page's content
+func WithStripBoilerplate(strip bool) DeepReaderOption {
+	return func(d *DeepReader) {
+		d.stripBoilerplate = strip
+	}
+}
+
+// WithRespectRobots sets whether sub-pages disallowed by their host's
+// robots.txt are skipped. It defaults to true.
+func WithRespectRobots(respect bool) DeepReaderOption {
+	return func(d *DeepReader) {
+		if respect {
+			d.robots = newRobotsCache()
+		} else {
+			d.robots = nil
+		}
+	}
+}
+
 // WithTimeout sets the timeout for page operations
 func WithTimeout(t time.Duration) DeepReaderOption {
 	return func(d *DeepReader) {
@@ -93,7 +165,13 @@ func NewDeepReader(opts ...DeepReaderOption) *DeepReader {
 		sameDomain:   true,
 		contentLimit: 2000,
 		concurrency:  3,
+
+		maxLinkTextLength: 100,
+		depth:             1,
+		maxPages:          30,
+		robots:            newRobotsCache(),
 	}
+	d.fetchPage = d.fetchWithBrowser
 	for _, opt := range opts {
 		opt(d)
 	}
@@ -105,19 +183,70 @@ func (d *DeepReader) DeepRead(ctx context.Context, targetURL string) (*DeepReadR
 	ctx, cancel := context.WithTimeout(ctx, d.timeout)
 	defer cancel()
 
-	allocCtx, cancel := chromedp.NewContext(ctx)
+	main, err := d.fetchPage(ctx, targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read main page %s: %w", targetURL, err)
+	}
+
+	// Parse and filter links
+	filteredLinks := d.filterLinks(targetURL, main.links)
+
+	result := &DeepReadResult{
+		MainURL:     targetURL,
+		MainTitle:   main.title,
+		MainContent: d.limitContent(main.content),
+		TotalLinks:  len(main.links),
+	}
+
+	// Crawl sub-pages with concurrency control
+	if len(filteredLinks) > 0 {
+		var subPages []SubPageResult
+		if d.depth > 1 {
+			subPages = d.crawlRecursive(ctx, targetURL, filteredLinks)
+		} else {
+			subPages = d.crawlSubPages(ctx, filteredLinks)
+		}
+		if d.stripBoilerplate {
+			stripSharedBoilerplate(subPages)
+		}
+		result.SubPages = subPages
+		result.CrawledLinks = len(subPages)
+	}
+
+	return result, nil
+}
+
+// fetchedPage is a page's title, main text and links as read by the browser
+type fetchedPage struct {
+	title   string
+	content string
+	links   []LinkInfo
+}
+
+// limitContent cleans content and truncates it to the content limit
+func (d *DeepReader) limitContent(content string) string {
+	content = CleanText(content)
+	if len(content) > d.contentLimit {
+		content = content[:d.contentLimit] + "..."
+	}
+	return content
+}
+
+// fetchWithBrowser loads a page in chromedp and returns its main text and
+// links
+func (d *DeepReader) fetchWithBrowser(ctx context.Context, targetURL string) (*fetchedPage, error) {
+	allocCtx, cancel := newBrowserContext(ctx, defaultChromeConfig())
 	defer cancel()
 
-	var mainContent string
-	var mainTitle string
+	var title string
 	var linksJSON string
 
 	// Extract main page content and links
 	err := chromedp.Run(allocCtx,
 		chromedp.Navigate(targetURL),
 		chromedp.WaitReady("body"),
-		chromedp.Title(&mainTitle),
-		chromedp.Evaluate(`
+		chromedp.Title(&title),
+		chromedp.Evaluate(fmt.Sprintf(`
 			(function() {
 				// Remove script and style elements
 				var scripts = document.querySelectorAll('script, style, noscript');
@@ -127,91 +256,71 @@ func (d *DeepReader) DeepRead(ctx context.Context, targetURL string) (*DeepReadR
 				var mainEl = document.querySelector('main, article, .content, #content, .post, .entry-content');
 				var content = mainEl ? mainEl.innerText : document.body.innerText;
 
+				var textOf = function(el) {
+					return (el.innerText || el.getAttribute('aria-label') || '').trim().slice(0, %d);
+				};
+
 				// Get links
 				var links = Array.from(document.querySelectorAll('a[href]')).map(function(el) {
-					return {
-						url: el.href,
-						text: (el.innerText || el.getAttribute('aria-label') || '').trim().slice(0, 100),
-						type: 'link'
-					};
-				}).filter(function(l) { return l.url && l.text; });
+					return { url: el.href, text: textOf(el), type: 'link' };
+				});
+
+				// Get buttons and other script-driven navigation, taking the
+				// URL from data-href, formaction or a location assignment in
+				// onclick when there is one
+				var buttonURL = function(el) {
+					var target = el.getAttribute('data-href') || el.getAttribute('formaction');
+					if (!target) {
+						var m = /location(?:\.href)?\s*=\s*['"]([^'"]+)['"]/.exec(el.getAttribute('onclick') || '');
+						target = m ? m[1] : '';
+					}
+					try {
+						return target ? new URL(target, location.href).href : '';
+					} catch (e) {
+						return '';
+					}
+				};
+				var buttons = Array.from(document.querySelectorAll('button, [role="button"], [onclick]')).filter(function(el) {
+					return !el.matches('a[href]');
+				}).map(function(el) {
+					return { url: buttonURL(el), text: textOf(el), type: 'button' };
+				});
+
+				links = links.concat(buttons).filter(function(l) { return l.url && l.text; });
 
 				return JSON.stringify({ content: content, links: links });
 			})()
-		`, &linksJSON),
+		`, d.maxLinkTextLength), &linksJSON),
 	)
-
 	if err != nil {
-		return nil, fmt.Errorf("failed to read main page %s: %w", targetURL, err)
+		return nil, err
 	}
 
-	mainContent = d.parseContentFromJSON(linksJSON)
-	mainContent = CleanText(mainContent)
-	if len(mainContent) > d.contentLimit {
-		mainContent = mainContent[:d.contentLimit] + "..."
-	}
-
-	// Parse and filter links
-	allLinks := d.parseLinksFromJSON(linksJSON)
-	filteredLinks := d.filterLinks(targetURL, allLinks)
-
-	result := &DeepReadResult{
-		MainURL:     targetURL,
-		MainTitle:   mainTitle,
-		MainContent: mainContent,
-		TotalLinks:  len(allLinks),
-	}
-
-	// Crawl sub-pages with concurrency control
-	if len(filteredLinks) > 0 {
-		subPages := d.crawlSubPages(ctx, filteredLinks)
-		result.SubPages = subPages
-		result.CrawledLinks = len(subPages)
+	data, err := parsePageJSON(linksJSON)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	return &fetchedPage{
+		title:   title,
+		content: data.Content,
+		links:   data.Links,
+	}, nil
 }
 
-// parseContentFromJSON extracts content from the JSON response
-func (d *DeepReader) parseContentFromJSON(jsonStr string) string {
-	// Simple extraction - find content field
-	idx := strings.Index(jsonStr, `"content":"`)
-	if idx == -1 {
-		return ""
-	}
-	start := idx + len(`"content":"`)
-	end := strings.Index(jsonStr[start:], `","links"`)
-	if end == -1 {
-		return ""
-	}
-	return jsonStr[start : start+end]
+// pageData is the main text and links the browser script reports
+type pageData struct {
+	Content string     `json:"content"`
+	Links   []LinkInfo `json:"links"`
 }
 
-// parseLinksFromJSON extracts links from the JSON response
-func (d *DeepReader) parseLinksFromJSON(jsonStr string) []LinkInfo {
-	var links []LinkInfo
-
-	// Find the links array
-	idx := strings.Index(jsonStr, `"links":[`)
-	if idx == -1 {
-		return links
+// parsePageJSON decodes the browser script's JSON report of a page
+func parsePageJSON(jsonStr string) (*pageData, error) {
+	var data pageData
+	if err := json.Unmarshal([]byte(jsonStr), &data); err != nil {
+		return nil, fmt.Errorf("failed to decode page data: %w", err)
 	}
-
-	// Simple JSON parsing for link objects
-	linkPattern := regexp.MustCompile(`\{"url":"([^"]+)","text":"([^"]+)","type":"([^"]+)"\}`)
-	matches := linkPattern.FindAllStringSubmatch(jsonStr, -1)
-
-	for _, match := range matches {
-		if len(match) >= 4 {
-			links = append(links, LinkInfo{
-				URL:  match[1],
-				Text: match[2],
-				Type: match[3],
-			})
-		}
-	}
-
-	return links
+	return &data, nil
 }
 
 // filterLinks applies smart filtering to select relevant links
@@ -246,7 +355,8 @@ func (d *DeepReader) filterLinks(baseURL string, links []LinkInfo) []LinkInfo {
 		linkLower := strings.ToLower(linkURL)
 		textLower := strings.ToLower(link.Text)
 
-		// Skip empty or invalid URLs
+		// Skip empty or invalid URLs, including buttons that don't
+		// navigate anywhere we can follow
 		if linkURL == "" || linkURL == "#" ||
 			strings.HasPrefix(linkURL, "javascript:") ||
 			strings.HasPrefix(linkURL, "mailto:") ||
@@ -312,12 +422,14 @@ func (d *DeepReader) filterLinks(baseURL string, links []LinkInfo) []LinkInfo {
 			continue
 		}
 
+		link.Text = truncateRunes(link.Text, d.maxLinkTextLength)
+
 		seen[linkURL] = true
 		filtered = append(filtered, link)
 	}
 
 	// Sort by text length (longer anchor text usually means more important)
-	sort.Slice(filtered, func(i, j int) bool {
+	sort.SliceStable(filtered, func(i, j int) bool {
 		return len(filtered[i].Text) > len(filtered[j].Text)
 	})
 
@@ -329,6 +441,18 @@ func (d *DeepReader) filterLinks(baseURL string, links []LinkInfo) []LinkInfo {
 	return filtered
 }
 
+// truncateRunes shortens s to at most n characters
+func truncateRunes(s string, n int) string {
+	if n <= 0 || len(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
 // crawlSubPages crawls multiple sub-pages concurrently
 func (d *DeepReader) crawlSubPages(ctx context.Context, links []LinkInfo) []SubPageResult {
 	var wg sync.WaitGroup
@@ -345,6 +469,11 @@ func (d *DeepReader) crawlSubPages(ctx context.Context, links []LinkInfo) []SubP
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
+			if !d.robotsAllowed(ctx, link.URL) {
+				results[idx] = SubPageResult{URL: link.URL, LinkText: link.Text, Error: robotsSkipped}
+				return
+			}
+
 			subCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
 			defer cancel()
 
@@ -387,6 +516,87 @@ func (d *DeepReader) crawlSubPages(ctx context.Context, links []LinkInfo) []SubP
 	return validResults
 }
 
+// crawlRecursive crawls links level by level up to the configured depth,
+// reading each page once and stopping once maxPages pages are crawled
+func (d *DeepReader) crawlRecursive(ctx context.Context, mainURL string, links []LinkInfo) []SubPageResult {
+	visited := map[string]bool{pageKey(mainURL): true}
+	var results []SubPageResult
+
+	level := links
+	for depth := 1; depth <= d.depth && len(level) > 0; depth++ {
+		// Claim this level's pages up front so no page is read twice
+		var batch []LinkInfo
+		for _, link := range level {
+			key := pageKey(link.URL)
+			if visited[key] || len(results)+len(batch) >= d.maxPages {
+				continue
+			}
+			visited[key] = true
+			batch = append(batch, link)
+		}
+
+		pages := make([]SubPageResult, len(batch))
+		found := make([][]LinkInfo, len(batch))
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, d.concurrency)
+
+		for i, link := range batch {
+			wg.Add(1)
+			go func(idx int, link LinkInfo) {
+				defer wg.Done()
+
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				if !d.robotsAllowed(ctx, link.URL) {
+					pages[idx] = SubPageResult{URL: link.URL, LinkText: link.Text, Error: robotsSkipped, Depth: depth}
+					return
+				}
+
+				subCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+				defer cancel()
+
+				page, err := d.fetchPage(subCtx, link.URL)
+				if err != nil {
+					pages[idx] = SubPageResult{URL: link.URL, LinkText: link.Text, Error: err.Error(), Depth: depth}
+					return
+				}
+
+				pages[idx] = SubPageResult{
+					URL:      link.URL,
+					Title:    page.title,
+					Content:  d.limitContent(page.content),
+					LinkText: link.Text,
+					Depth:    depth,
+				}
+				found[idx] = d.filterLinks(link.URL, page.links)
+			}(i, link)
+		}
+		wg.Wait()
+
+		results = append(results, pages...)
+
+		level = nil
+		for _, links := range found {
+			level = append(level, links...)
+		}
+	}
+
+	return results
+}
+
+// robotsAllowed reports whether the page may be crawled under its host's
+// robots.txt
+func (d *DeepReader) robotsAllowed(ctx context.Context, pageURL string) bool {
+	return d.robots == nil || d.robots.allowed(ctx, pageURL)
+}
+
+// pageKey identifies a page for the visited set, ignoring fragments
+func pageKey(rawURL string) string {
+	key, _, _ := strings.Cut(rawURL, "#")
+	return strings.TrimSuffix(key, "/")
+}
+
 // ToMarkdown formats the deep read result as markdown
 func (r *DeepReadResult) ToMarkdown() string {
 	var sb strings.Builder
@@ -1,6 +1,9 @@
 package extraction
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"testing"
 )
 
@@ -137,6 +140,28 @@ func TestDeepReader_FilterLinks_CrossDomain(t *testing.T) {
 	}
 }
 
+func TestDeepReader_FilterLinks_Buttons(t *testing.T) {
+	reader := NewDeepReader()
+
+	links := []LinkInfo{
+		{URL: "https://example.com/articles?page=2", Text: "Next page", Type: "button"},
+		{URL: "", Text: "Toggle menu", Type: "button"},
+		{URL: "javascript:void(0)", Text: "Show comments", Type: "button"},
+		{URL: "https://example.com/guide", Text: "Read the guide", Type: "link"},
+	}
+
+	filtered := reader.filterLinks("https://example.com/articles", links)
+
+	if len(filtered) != 2 {
+		t.Fatalf("expected only the button with a URL and the link, got %+v", filtered)
+	}
+	for _, l := range filtered {
+		if l.Type == "button" && l.URL != "https://example.com/articles?page=2" {
+			t.Errorf("expected only the navigable button to be kept, got %+v", l)
+		}
+	}
+}
+
 func TestDeepReader_MaxLinks(t *testing.T) {
 	maxLinks := 3
 	reader := NewDeepReader(WithMaxLinks(maxLinks))
@@ -250,12 +275,14 @@ func TestDeepReadResult_ToMarkdown(t *testing.T) {
 	}
 }
 
-func TestDeepReader_ParseLinksFromJSON(t *testing.T) {
-	reader := NewDeepReader()
-
+func TestParsePageJSON(t *testing.T) {
 	jsonStr := `{"content":"Some content","links":[{"url":"https://example.com/page1","text":"Page One","type":"link"},{"url":"https://example.com/page2","text":"Page Two","type":"link"}]}`
 
-	links := reader.parseLinksFromJSON(jsonStr)
+	data, err := parsePageJSON(jsonStr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	links := data.Links
 
 	if len(links) != 2 {
 		t.Errorf("expected 2 links, got %d", len(links))
@@ -267,6 +294,46 @@ func TestDeepReader_ParseLinksFromJSON(t *testing.T) {
 	if links[0].Text != "Page One" {
 		t.Errorf("expected Text 'Page One', got %q", links[0].Text)
 	}
+	if data.Content != "Some content" {
+		t.Errorf("expected content 'Some content', got %q", data.Content)
+	}
+}
+
+func TestParsePageJSON_TrickyLinkText(t *testing.T) {
+	// As produced by JSON.stringify, which escapes quotes and may use
+	// \u escapes; fields can also come in any order
+	jsonStr := `{"content":"Line one\n\"Quoted\", then more","links":[` +
+		`{"url":"https://example.com/faq","text":"What's new?","type":"link"},` +
+		`{"url":"https://example.com/q","text":"Say \"hi\", then leave","type":"link"},` +
+		`{"type":"link","text":"Caf\u00e9 \u2014 menu","url":"https://example.com/cafe"},` +
+		`{"url":"https://example.com/a,b","text":"a, b, {c}","type":"button"}]}`
+
+	data, err := parsePageJSON(jsonStr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []LinkInfo{
+		{URL: "https://example.com/faq", Text: "What's new?", Type: "link"},
+		{URL: "https://example.com/q", Text: `Say "hi", then leave`, Type: "link"},
+		{URL: "https://example.com/cafe", Text: "Café — menu", Type: "link"},
+		{URL: "https://example.com/a,b", Text: "a, b, {c}", Type: "button"},
+	}
+	if len(data.Links) != len(want) {
+		t.Fatalf("expected %d links, got %d: %+v", len(want), len(data.Links), data.Links)
+	}
+	for i := range want {
+		if data.Links[i] != want[i] {
+			t.Errorf("link %d: expected %+v, got %+v", i, want[i], data.Links[i])
+		}
+	}
+	if data.Content != "Line one\n\"Quoted\", then more" {
+		t.Errorf("expected the content unescaped, got %q", data.Content)
+	}
+
+	if _, err := parsePageJSON(`{"content":"cut off`); err == nil {
+		t.Error("expected malformed JSON to fail")
+	}
 }
 
 func contains(s, substr string) bool {
@@ -282,3 +349,100 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestDeepReader_MaxLinkTextLength(t *testing.T) {
+	links := []LinkInfo{
+		{URL: "https://example.com/short", Text: "A reasonably long link title"},
+		{URL: "https://example.com/long", Text: "An even longer link title that goes on and on"},
+	}
+
+	// By default the longer text ranks first
+	filtered := NewDeepReader().filterLinks("https://example.com", links)
+	if filtered[0].URL != "https://example.com/long" {
+		t.Fatalf("expected longest link text first, got %s", filtered[0].URL)
+	}
+
+	// With a small cap both texts are truncated, tie, and keep page order
+	reader := NewDeepReader(WithMaxLinkTextLength(10))
+	filtered = reader.filterLinks("https://example.com", links)
+
+	for _, l := range filtered {
+		if len(l.Text) != 10 {
+			t.Errorf("expected link text truncated to 10 chars, got %q", l.Text)
+		}
+	}
+	if filtered[0].URL != "https://example.com/short" {
+		t.Errorf("expected truncation to change the order, got %s first", filtered[0].URL)
+	}
+}
+
+func TestDeepReader_RecursiveCrawl(t *testing.T) {
+	// A small site where pages link back to each other
+	graph := map[string][]string{
+		"/":  {"/a", "/b", "/c"},
+		"/a": {"/", "/b", "/d", "/e"},
+		"/b": {"/a", "/f"},
+		"/c": {"/g", "/c#top"},
+		"/d": {"/h"},
+	}
+
+	var mu sync.Mutex
+	fetched := map[string]int{}
+
+	reader := NewDeepReader(WithDepth(2), WithMaxPages(5), WithRespectRobots(false))
+	reader.fetchPage = func(ctx context.Context, url string) (*fetchedPage, error) {
+		mu.Lock()
+		fetched[url]++
+		mu.Unlock()
+
+		path := url[len("https://example.com"):]
+		page := &fetchedPage{title: "Page " + path, content: "Content of " + path}
+		for _, target := range graph[path] {
+			page.links = append(page.links, LinkInfo{
+				URL:  "https://example.com" + target,
+				Text: fmt.Sprintf("Article about %s", target),
+			})
+		}
+		return page, nil
+	}
+
+	result, err := reader.DeepRead(context.Background(), "https://example.com/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.SubPages) != 5 {
+		t.Fatalf("expected the crawl to stop at 5 pages, got %d", len(result.SubPages))
+	}
+
+	for url, n := range fetched {
+		if n != 1 {
+			t.Errorf("expected %s to be fetched once, got %d", url, n)
+		}
+	}
+	if fetched["https://example.com/h"] != 0 {
+		t.Error("expected pages beyond depth 2 not to be crawled")
+	}
+
+	for i, page := range result.SubPages {
+		want := 1
+		if i >= 3 {
+			want = 2
+		}
+		if page.Depth != want {
+			t.Errorf("expected %s at depth %d, got %d", page.URL, want, page.Depth)
+		}
+	}
+}
+
+func TestDeepReader_DepthBounds(t *testing.T) {
+	if d := NewDeepReader().depth; d != 1 {
+		t.Errorf("expected default depth 1, got %d", d)
+	}
+	if d := NewDeepReader(WithDepth(10)).depth; d != 1 {
+		t.Errorf("expected out-of-range depth to be ignored, got %d", d)
+	}
+	if d := NewDeepReader(WithDepth(3)).depth; d != 3 {
+		t.Errorf("expected depth 3, got %d", d)
+	}
+}
@@ -0,0 +1,15 @@
+package extraction
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// ContentHash returns a hex SHA-256 of the extracted content, so callers
+// can tell whether a page changed since they last read it without keeping
+// the full text. Leading and trailing whitespace is ignored.
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(content)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,17 @@
+package extraction
+
+import "testing"
+
+func TestContentHash(t *testing.T) {
+	content := "# Release notes\n\nVersion 1.2 fixes the parser."
+
+	if ContentHash(content) != ContentHash(content) {
+		t.Error("expected identical content to hash identically")
+	}
+	if ContentHash(content) == ContentHash("# Release notes\n\nVersion 1.3 fixes the parser.") {
+		t.Error("expected a single-character change to change the hash")
+	}
+	if len(ContentHash(content)) != 64 {
+		t.Errorf("expected a hex SHA-256, got %q", ContentHash(content))
+	}
+}
@@ -0,0 +1,325 @@
+package extraction
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// ErrJavaScriptRequired is returned by HTTPExtractor for pages that are
+// rendered client-side, whose HTML holds no content without a browser
+var ErrJavaScriptRequired = errors.New("page requires JavaScript to render")
+
+// ErrUnsupportedContentType is matched by errors.Is when HTTPExtractor is
+// given a response that isn't HTML, such as a PDF or an image
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// UnsupportedContentTypeError reports the page and the Content-Type it was
+// served with
+type UnsupportedContentTypeError struct {
+	URL         string
+	ContentType string
+}
+
+func (e *UnsupportedContentTypeError) Error() string {
+	return fmt.Sprintf("%v: %s is %s", ErrUnsupportedContentType, e.URL, e.ContentType)
+}
+
+func (e *UnsupportedContentTypeError) Unwrap() error {
+	return ErrUnsupportedContentType
+}
+
+// defaultMaxHTTPPageSize caps how much of a page HTTPExtractor reads
+const defaultMaxHTTPPageSize = 5 << 20
+
+// HTTPExtractor extracts page content with a plain HTTP request and goquery,
+// without a browser. It suits static pages; client-rendered pages fail with
+// ErrJavaScriptRequired.
+type HTTPExtractor struct {
+	client *http.Client
+	// maxPageSize caps how many bytes of a page are read
+	maxPageSize int64
+	// proxyErr records an invalid proxy and fails every request
+	proxyErr error
+}
+
+// HTTPOption configures an HTTPExtractor
+type HTTPOption func(*HTTPExtractor)
+
+// WithHTTPMaxPageSize caps how many bytes of a page are read, so a huge
+// response can't exhaust memory. Larger pages are cut off at the limit and
+// extracted as far as they got, with a warning logged. Zero or a negative
+// n keeps the 5MB default.
+func WithHTTPMaxPageSize(n int64) HTTPOption {
+	return func(e *HTTPExtractor) {
+		if n > 0 {
+			e.maxPageSize = n
+		}
+	}
+}
+
+// WithHTTPProxy sends the extractor's requests through the proxy at
+// proxyURL, which may use the http, https or socks5 scheme. An invalid URL
+// makes every extraction fail rather than connect directly.
+func WithHTTPProxy(proxyURL string) HTTPOption {
+	return func(e *HTTPExtractor) {
+		var proxyServer string
+		proxyServer, e.proxyErr = parseProxyServer(proxyURL)
+		if e.proxyErr == nil {
+			e.client.Transport = proxyTransport(proxyServer)
+		}
+	}
+}
+
+func NewHTTPExtractor(opts ...HTTPOption) *HTTPExtractor {
+	e := &HTTPExtractor{
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		maxPageSize: defaultMaxHTTPPageSize,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// ExtractContent fetches url and returns the text of its main content
+// element, found the same way as ChromedpExtractor, prefixed with the page
+// title
+func (e *HTTPExtractor) ExtractContent(ctx context.Context, url string) (string, error) {
+	page, err := e.ExtractPage(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	return page.Content, nil
+}
+
+// ExtractPage extracts the same content as ExtractContent, along with the
+// response's status, post-redirect URL and Last-Modified header
+func (e *HTTPExtractor) ExtractPage(ctx context.Context, url string) (*Page, error) {
+	doc, info, err := e.fetchDocument(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	if requiresJS(doc) {
+		return nil, fmt.Errorf("failed to extract content from %s: %w", url, ErrJavaScriptRequired)
+	}
+
+	title := strings.TrimSpace(doc.Find("title").First().Text())
+	content := contentFromDocument(doc)
+	return &Page{
+		URL:          url,
+		FinalURL:     info.FinalURL,
+		StatusCode:   info.StatusCode,
+		Title:        title,
+		Content:      content,
+		ContentHash:  ContentHash(content),
+		LastModified: info.LastModified,
+	}, nil
+}
+
+// ExtractMarkdown fetches url and returns its main content element as
+// Markdown that keeps its headings, emphasis, lists and links, prefixed
+// with the page title. Relative links are resolved against the URL the page
+// was served from.
+func (e *HTTPExtractor) ExtractMarkdown(ctx context.Context, url string) (string, error) {
+	doc, info, err := e.fetchDocument(ctx, url)
+	if err != nil {
+		return "", err
+	}
+
+	if requiresJS(doc) {
+		return "", fmt.Errorf("failed to extract content from %s: %w", url, ErrJavaScriptRequired)
+	}
+
+	title, content := mainContent(doc)
+	contentHTML, err := goquery.OuterHtml(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract content from %s: %w", url, err)
+	}
+	return markdownFromHTML(contentHTML, info.FinalURL, title)
+}
+
+// ExtractStructuredData fetches url and returns its JSON-LD, OpenGraph and
+// Twitter card metadata, such as title, image, author and published date
+func (e *HTTPExtractor) ExtractStructuredData(ctx context.Context, url string) (*PageMetadata, error) {
+	doc, info, err := e.fetchDocument(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return documentMetadata(info.FinalURL, doc), nil
+}
+
+// fetchDocument fetches url and parses it, returning the document and the
+// response's status, post-redirect URL and Last-Modified header. Responses
+// that aren't HTML fail with an *UnsupportedContentTypeError, and the body
+// is decoded from the charset given by the Content-Type header or the
+// page's meta tags.
+func (e *HTTPExtractor) fetchDocument(ctx context.Context, url string) (*goquery.Document, *PageInfo, error) {
+	if e.proxyErr != nil {
+		return nil, nil, e.proxyErr
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, nil, fmt.Errorf("failed to fetch %s: status %d", url, resp.StatusCode)
+	}
+
+	doc, err := parseLimitedHTML(resp.Body, e.maxPageSize, url, resp.Header.Get("Content-Type"))
+	if err != nil {
+		var typeErr *UnsupportedContentTypeError
+		if errors.As(err, &typeErr) {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("failed to parse HTML from %s: %w", url, err)
+	}
+	return doc, &PageInfo{
+		StatusCode:   resp.StatusCode,
+		FinalURL:     resp.Request.URL.String(),
+		LastModified: lastModifiedHeader(resp.Header.Get("Last-Modified")),
+	}, nil
+}
+
+// parseLimitedHTML parses at most limit bytes of body. A larger page is
+// cut off at the limit and parsed as far as it got, and a warning logged.
+// contentType is the response's Content-Type; when it is empty the type is
+// sniffed from the body.
+func parseLimitedHTML(body io.Reader, limit int64, url, contentType string) (*goquery.Document, error) {
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		log.Printf("warning: %s exceeds %d bytes, extracting the truncated page", url, limit)
+		data = data[:limit]
+	}
+
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	if !isHTMLType(contentType) {
+		return nil, &UnsupportedContentTypeError{URL: url, ContentType: contentType}
+	}
+
+	decoded, err := charset.NewReader(bytes.NewReader(data), contentType)
+	if err != nil {
+		return nil, err
+	}
+	return goquery.NewDocumentFromReader(decoded)
+}
+
+// isHTMLType reports whether a Content-Type is HTML or XHTML
+func isHTMLType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "text/html" || mediaType == "application/xhtml+xml")
+}
+
+// PageInfo reports the page's status, final URL and Last-Modified header
+func (e *HTTPExtractor) PageInfo(ctx context.Context, url string) (*PageInfo, error) {
+	if e.proxyErr != nil {
+		return nil, e.proxyErr
+	}
+	return FetchPageInfo(ctx, e.client, url)
+}
+
+// contentFromDocument returns the cleaned text of the document's main
+// content element, or of the body when there is none, prefixed with the
+// title
+func contentFromDocument(doc *goquery.Document) string {
+	title, content := mainContent(doc)
+
+	var text strings.Builder
+	for _, node := range content.Nodes {
+		writeBlockText(&text, node)
+	}
+	bodyText := CleanText(text.String())
+
+	if title != "" {
+		return fmt.Sprintf("# %s\n\n%s", title, bodyText)
+	}
+	return bodyText
+}
+
+// mainContent returns the document's title and its main content element,
+// or the body when there is none, after removing scripts, navigation and
+// footers
+func mainContent(doc *goquery.Document) (title string, content *goquery.Selection) {
+	title = strings.TrimSpace(doc.Find("title").First().Text())
+
+	doc.Find("script, style, noscript, nav, footer").Remove()
+
+	content = doc.Find("main, article, .content, #content, .post, .entry-content").First()
+	if content.Length() == 0 {
+		content = doc.Find("body")
+	}
+	return title, content
+}
+
+// blockElements are the elements that start a new line in rendered text
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "figcaption": true, "figure": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "main": true, "ol": true, "p": true,
+	"pre": true, "section": true, "table": true, "tr": true, "ul": true,
+}
+
+// writeBlockText writes n's text, approximating the browser's innerText by
+// breaking lines around block elements and collapsing other whitespace
+func writeBlockText(w *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		if text := strings.Join(strings.Fields(n.Data), " "); text != "" {
+			if startsWithSpace(n.Data) && w.Len() > 0 && !strings.HasSuffix(w.String(), " ") && !strings.HasSuffix(w.String(), "\n") {
+				w.WriteByte(' ')
+			}
+			w.WriteString(text)
+			if endsWithSpace(n.Data) {
+				w.WriteByte(' ')
+			}
+		}
+		return
+	case html.ElementNode:
+		if blockElements[n.Data] {
+			w.WriteString("\n")
+			defer w.WriteString("\n")
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeBlockText(w, c)
+	}
+}
+
+func startsWithSpace(s string) bool {
+	return s != "" && strings.TrimLeft(s, " \t\r\n") != s
+}
+
+func endsWithSpace(s string) bool {
+	return s != "" && strings.TrimRight(s, " \t\r\n") != s
+}
@@ -0,0 +1,281 @@
+package extraction
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPExtractor_ExtractContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/article":
+			w.Write([]byte(`<html><head><title>Static Article</title><style>body{}</style></head><body>
+				<nav><a href="/">Home</a> <a href="/blog">Blog</a></nav>
+				<article>
+					<h1>Why static pages are fast</h1>
+					<p>Static pages need <em>no</em> rendering.</p>
+					<p>They can be cached anywhere.</p>
+					<script>track()</script>
+				</article>
+				<footer>Copyright 2024</footer>
+			</body></html>`))
+		case "/plain":
+			w.Write([]byte(`<html><body><nav>Menu</nav><h2>Plain page</h2><p>Only body text.</p></body></html>`))
+		case "/spa":
+			w.Write([]byte(`<html><body><div id="__next"></div><script id="__NEXT_DATA__" type="application/json">{}</script></body></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	extractor := NewHTTPExtractor()
+
+	content, err := extractor.ExtractContent(context.Background(), server.URL+"/article")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "# Static Article\n\nWhy static pages are fast\n\nStatic pages need no rendering.\n\nThey can be cached anywhere."
+	if content != want {
+		t.Errorf("unexpected content:\n%q\nwant:\n%q", content, want)
+	}
+
+	content, err = extractor.ExtractContent(context.Background(), server.URL+"/plain")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content != "Plain page\n\nOnly body text." {
+		t.Errorf("expected body text without the nav, got %q", content)
+	}
+
+	if _, err := extractor.ExtractContent(context.Background(), server.URL+"/spa"); !errors.Is(err, ErrJavaScriptRequired) {
+		t.Errorf("expected ErrJavaScriptRequired for a client-rendered page, got %v", err)
+	}
+
+	if _, err := extractor.ExtractContent(context.Background(), server.URL+"/missing"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected a status error, got %v", err)
+	}
+}
+
+func TestHTTPExtractor_ExtractStructuredData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><head>
+			<title>Fallback title</title>
+			<meta property="og:title" content="Go 1.24 released">
+			<meta property="og:type" content="article">
+			<meta property="og:image" content="/img/gopher.png">
+			<meta name="twitter:card" content="summary_large_image">
+			<meta name="twitter:description" content="What's new in Go 1.24">
+			<script type="application/ld+json">
+			{"@type": "BlogPosting", "datePublished": "2025-02-11T09:00:00Z", "author": {"@type": "Person", "name": "The Go Team"}}
+			</script>
+		</head><body><p>Release notes.</p></body></html>`))
+	}))
+	defer server.Close()
+
+	meta, err := NewHTTPExtractor().ExtractStructuredData(context.Background(), server.URL+"/blog/go1.24")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if meta.Title != "Go 1.24 released" || meta.Type != "article" {
+		t.Errorf("expected OpenGraph title and type, got %q / %q", meta.Title, meta.Type)
+	}
+	if meta.Description != "What's new in Go 1.24" {
+		t.Errorf("expected the Twitter card description as fallback, got %q", meta.Description)
+	}
+	if meta.Image != server.URL+"/img/gopher.png" {
+		t.Errorf("expected the image resolved against the page, got %q", meta.Image)
+	}
+	if meta.Author == nil || meta.Author.Name != "The Go Team" {
+		t.Errorf("expected the JSON-LD author, got %+v", meta.Author)
+	}
+	if want := time.Date(2025, 2, 11, 9, 0, 0, 0, time.UTC); !meta.PublishedAt.Equal(want) {
+		t.Errorf("expected published date %v, got %v", want, meta.PublishedAt)
+	}
+	if meta.Twitter["card"] != "summary_large_image" || meta.OpenGraph["type"] != "article" {
+		t.Errorf("expected raw tags keyed without prefix, got %v / %v", meta.Twitter, meta.OpenGraph)
+	}
+	if len(meta.JSONLD) != 1 || meta.JSONLD[0]["@type"] != "BlogPosting" {
+		t.Errorf("expected the JSON-LD object, got %v", meta.JSONLD)
+	}
+
+	if _, err := NewHTTPExtractor().ExtractStructuredData(context.Background(), server.URL+"%zz"); err == nil {
+		t.Error("expected an invalid URL to fail")
+	}
+}
+
+func TestHTTPExtractor_MaxPageSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><article><p>Kept paragraph.</p>`))
+		w.Write([]byte(strings.Repeat("<p>Padding past the limit.</p>", 1000)))
+		w.Write([]byte(`<p>Dropped paragraph.</p></article></body></html>`))
+	}))
+	defer server.Close()
+
+	if e := NewHTTPExtractor(); e.maxPageSize != defaultMaxHTTPPageSize {
+		t.Errorf("expected the default limit %d, got %d", defaultMaxHTTPPageSize, e.maxPageSize)
+	}
+
+	content, err := NewHTTPExtractor(WithHTTPMaxPageSize(1024)).ExtractContent(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("expected the truncated page to be extracted, got %v", err)
+	}
+	if !strings.HasPrefix(content, "Kept paragraph.") || strings.Contains(content, "Dropped paragraph.") {
+		t.Errorf("expected only the first 1KB of the page, got %q", content)
+	}
+}
+
+func TestHTTPExtractor_UnsupportedContentType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/report.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte("%PDF-1.4\n"))
+		case "/sniffed":
+			w.Header()["Content-Type"] = nil
+			w.Write([]byte("\x89PNG\r\n\x1a\n"))
+		case "/xhtml":
+			w.Header().Set("Content-Type", "application/xhtml+xml; charset=utf-8")
+			w.Write([]byte(`<html><body><article><p>XHTML page.</p></article></body></html>`))
+		}
+	}))
+	defer server.Close()
+
+	e := NewHTTPExtractor()
+	for _, path := range []string{"/report.pdf", "/sniffed"} {
+		_, err := e.ExtractContent(context.Background(), server.URL+path)
+		var typeErr *UnsupportedContentTypeError
+		if !errors.As(err, &typeErr) || !errors.Is(err, ErrUnsupportedContentType) {
+			t.Errorf("%s: expected an *UnsupportedContentTypeError, got %v", path, err)
+		} else if typeErr.URL != server.URL+path {
+			t.Errorf("%s: expected the error to name the URL, got %q", path, typeErr.URL)
+		}
+	}
+
+	content, err := e.ExtractContent(context.Background(), server.URL+"/xhtml")
+	if err != nil || !strings.Contains(content, "XHTML page.") {
+		t.Errorf("expected XHTML to be extracted, got %q (%v)", content, err)
+	}
+}
+
+func TestHTTPExtractor_Charset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/header":
+			w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+			w.Write([]byte("<html><body><article><p>Caf\xe9 cr\xe8me</p></article></body></html>"))
+		case "/meta":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html><head><meta charset=\"windows-1252\"></head><body><article><p>Na\xefve \x93quotes\x94</p></article></body></html>"))
+		}
+	}))
+	defer server.Close()
+
+	e := NewHTTPExtractor()
+	tests := map[string]string{
+		"/header": "Café crème",
+		"/meta":   "Naïve “quotes”",
+	}
+	for path, want := range tests {
+		content, err := e.ExtractContent(context.Background(), server.URL+path)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", path, err)
+		}
+		if !strings.Contains(content, want) {
+			t.Errorf("%s: expected %q, got %q", path, want, content)
+		}
+	}
+}
+
+func TestHTTPExtractor_ExtractPage(t *testing.T) {
+	modified := time.Date(2024, 3, 15, 8, 30, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusFound)
+		case "/new":
+			w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+			w.WriteHeader(http.StatusNonAuthoritativeInfo)
+			w.Write([]byte(`<html><head><title>Moved</title></head><body><article><p>Moved page.</p></article></body></html>`))
+		}
+	}))
+	defer server.Close()
+
+	page, err := NewHTTPExtractor().ExtractPage(context.Background(), server.URL+"/old")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.StatusCode != http.StatusNonAuthoritativeInfo || page.FinalURL != server.URL+"/new" {
+		t.Errorf("expected the redirected response's status and URL, got %d %q", page.StatusCode, page.FinalURL)
+	}
+	if !page.LastModified.Equal(modified) {
+		t.Errorf("expected LastModified %v, got %v", modified, page.LastModified)
+	}
+	if page.Title != "Moved" || !strings.Contains(page.Content, "Moved page.") || page.ContentHash != ContentHash(page.Content) {
+		t.Errorf("unexpected page: %+v", page)
+	}
+}
+
+func TestHTTPExtractor_ExtractMarkdown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/guide":
+			w.Write([]byte(`<html><head><title>Go Guide</title></head><body>
+				<nav><a href="/">Home</a></nav>
+				<article>
+					<h2>Getting started</h2>
+					<p>Install <strong>Go</strong> and read the <a href="/docs/tour">tour</a>.</p>
+					<ul><li>Write code<ul><li>Run tests</li></ul></li><li>Ship it</li></ul>
+					<script>track()</script>
+				</article>
+				<footer>Copyright 2024</footer>
+			</body></html>`))
+		case "/spa":
+			w.Write([]byte(`<html><body><div id="__next"></div><script id="__NEXT_DATA__" type="application/json">{}</script></body></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	extractor := NewHTTPExtractor()
+
+	content, err := extractor.ExtractMarkdown(context.Background(), server.URL+"/guide")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"# Go Guide\n\n",
+		"## Getting started",
+		"**Go**",
+		"[tour](" + server.URL + "/docs/tour)",
+		"- Write code\n\n  - Run tests",
+		"- Ship it",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in:\n%s", want, content)
+		}
+	}
+	for _, unwanted := range []string{"Home", "Copyright", "track()"} {
+		if strings.Contains(content, unwanted) {
+			t.Errorf("expected %q to be removed, got:\n%s", unwanted, content)
+		}
+	}
+
+	if _, err := extractor.ExtractMarkdown(context.Background(), server.URL+"/spa"); !errors.Is(err, ErrJavaScriptRequired) {
+		t.Errorf("expected ErrJavaScriptRequired for a client-rendered page, got %v", err)
+	}
+}
+
+func TestCleanMarkdown(t *testing.T) {
+	got := cleanMarkdown("\n\n# Title\n   \n\n\n- item\n  - nested\n\n    code\n\n")
+	if want := "# Title\n\n- item\n  - nested\n\n    code"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -2,12 +2,18 @@ package extraction
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/JohannesKaufmann/html-to-markdown/v2"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
 	"github.com/go-shiori/go-readability"
 )
@@ -15,37 +21,221 @@ import (
 // HybridExtractor uses chromedp for rendering and go-readability for content extraction
 type HybridExtractor struct {
 	timeout time.Duration
+	browser *sharedBrowser
+	// concurrency bounds the pages ExtractMultipleOrdered renders at once
+	concurrency int
+	// renderPage loads a page in the browser; replaced in tests
+	renderPage func(ctx context.Context, targetURL string) (*renderedPage, error)
+	// pool, when set, supplies the tabs instead of the shared browser
+	pool *BrowserPool
+	// chrome is where the extractor's own browsers come from; proxyErr
+	// records an invalid proxy and fails every extraction
+	chrome   chromeConfig
+	proxyErr error
+	// client downloads PDFs, which are read directly instead of rendered,
+	// and sends PageInfo's HEAD requests
+	client *http.Client
 }
 
-func NewHybridExtractor() *HybridExtractor {
-	return &HybridExtractor{
-		timeout: 30 * time.Second,
+// HybridOption configures a HybridExtractor
+type HybridOption func(*HybridExtractor)
+
+// WithConcurrency sets how many pages ExtractMultipleOrdered extracts at
+// once. The default is 3.
+func WithConcurrency(n int) HybridOption {
+	return func(e *HybridExtractor) {
+		if n > 0 {
+			e.concurrency = n
+		}
+	}
+}
+
+// WithBrowserPool renders pages on tabs from pool instead of the
+// extractor's own browser. The pool is not closed by Close; the caller owns
+// it and may share it between extractors.
+func WithBrowserPool(pool *BrowserPool) HybridOption {
+	return func(e *HybridExtractor) {
+		e.pool = pool
+	}
+}
+
+// WithProxyServer starts the extractor's browsers behind the proxy at
+// proxyURL, which may use the http, https or socks5 scheme, and downloads
+// PDFs and sends HEAD requests through it too. An invalid URL makes every
+// extraction fail rather than connect directly. A BrowserPool given with
+// WithBrowserPool must be created with WithPoolProxyServer(proxyURL), or
+// extractions fail with ErrPoolProxyMismatch.
+func WithProxyServer(proxyURL string) HybridOption {
+	return func(e *HybridExtractor) {
+		e.chrome.proxyServer, e.proxyErr = parseProxyServer(proxyURL)
+		e.browser.launch = browserLauncher(e.chrome)
+		if e.proxyErr == nil {
+			e.client.Transport = proxyTransport(e.chrome.proxyServer)
+		}
+	}
+}
+
+// WithRemoteChrome renders pages in the running Chrome whose DevTools
+// endpoint is at wsURL, such as ws://chrome:9222, instead of launching a
+// local one. The default is the URL in the RemoteChromeEnv environment
+// variable, if set.
+func WithRemoteChrome(wsURL string) HybridOption {
+	return func(e *HybridExtractor) {
+		e.chrome.remoteURL = wsURL
+		e.browser.launch = browserLauncher(e.chrome)
+	}
+}
+
+func NewHybridExtractor(opts ...HybridOption) *HybridExtractor {
+	e := &HybridExtractor{
+		timeout:     30 * time.Second,
+		browser:     newSharedBrowser(),
+		chrome:      defaultChromeConfig(),
+		concurrency: 3,
+		client:      &http.Client{Timeout: 30 * time.Second},
 	}
+	e.renderPage = e.renderHTML
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Warmup launches the browser ahead of time so later extractions open tabs
+// on it instead of paying Chrome's start-up cost
+func (e *HybridExtractor) Warmup(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if e.proxyErr != nil {
+		return e.proxyErr
+	}
+	return e.browser.start()
+}
+
+// Close shuts down the browser started by Warmup
+func (e *HybridExtractor) Close() error {
+	e.browser.close()
+	return nil
+}
+
+// newTab opens a tab from the pool if there is one, else on the warm
+// browser, otherwise it launches a fresh browser for this call. release
+// closes the tab; broken reports that the browser crashed.
+func (e *HybridExtractor) newTab(ctx context.Context) (tabCtx context.Context, release func(broken bool), err error) {
+	if e.proxyErr != nil {
+		return nil, nil, e.proxyErr
+	}
+	if e.pool != nil {
+		return e.pool.proxiedTab(ctx, e.chrome.proxyServer)
+	}
+	if tabCtx, cancel, ok := e.browser.newTab(ctx); ok {
+		return tabCtx, func(bool) { cancel() }, nil
+	}
+	tabCtx, cancel := newBrowserContext(ctx, e.chrome)
+	return tabCtx, func(bool) { cancel() }, nil
+}
+
+// Page is the result of extracting a single URL
+type Page struct {
+	URL        string
+	FinalURL   string
+	StatusCode int
+	Title      string
+	Content    string
+	// Citations are the links in the page's main content
+	Citations []Citation
+	// ContentHash is ContentHash(Content), for change detection
+	ContentHash string
+	// PublishedAt is the publication date found in the page's metadata,
+	// or zero
+	PublishedAt time.Time
+	// LastModified is the response's Last-Modified header, or zero
+	LastModified time.Time
 }
 
 // ExtractContent extracts the main content from a webpage using Readability and Markdown conversion
 func (e *HybridExtractor) ExtractContent(ctx context.Context, targetURL string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, e.timeout)
-	defer cancel()
-
-	allocCtx, cancel := chromedp.NewContext(ctx)
-	defer cancel()
+	page, err := e.ExtractPage(ctx, targetURL)
+	if err != nil {
+		return "", err
+	}
+	return page.Content, nil
+}
 
-	var htmlContent string
-	var pageTitle string
+// ExtractPage extracts the main content like ExtractContent, along with the
+// main document's HTTP status and post-redirect URL. PDFs, recognized by a
+// .pdf path or the type the browser reports, are downloaded and their text
+// extracted instead.
+func (e *HybridExtractor) ExtractPage(ctx context.Context, targetURL string) (*Page, error) {
+	if isPDFURL(targetURL) {
+		page, err := e.extractPDF(ctx, targetURL)
+		if !errors.Is(err, errNotPDF) {
+			return page, err
+		}
+	}
 
 	// 1. Fetch rendered HTML via chromedp
-	err := chromedp.Run(allocCtx,
-		chromedp.Navigate(targetURL),
-		chromedp.WaitReady("body"),
-		chromedp.Title(&pageTitle),
-		chromedp.OuterHTML("html", &htmlContent),
-	)
+	rendered, err := e.renderWithRecovery(ctx, targetURL)
+	if err != nil {
+		return nil, err
+	}
 
+	if isPDFType(rendered.mimeType) {
+		return e.extractPDF(ctx, targetURL)
+	}
+
+	content, err := contentFromHTML(targetURL, rendered.html, rendered.title)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch rendered HTML from %s: %w", targetURL, err)
+		return nil, err
 	}
 
+	citations, _ := parseCitations(rendered.finalURL, rendered.html)
+
+	return &Page{
+		URL:          targetURL,
+		FinalURL:     rendered.finalURL,
+		StatusCode:   rendered.status,
+		Title:        rendered.title,
+		Content:      content,
+		Citations:    citations,
+		ContentHash:  ContentHash(content),
+		PublishedAt:  publishedFromHTML(rendered.html),
+		LastModified: rendered.lastModified,
+	}, nil
+}
+
+// extractPDF downloads the PDF at targetURL and returns its text as the
+// page content, titled from the PDF's metadata
+func (e *HybridExtractor) extractPDF(ctx context.Context, targetURL string) (*Page, error) {
+	if e.proxyErr != nil {
+		return nil, e.proxyErr
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	doc, err := fetchPDF(ctx, e.client, targetURL)
+	if err != nil {
+		return nil, err
+	}
+
+	content := pdfContent(doc)
+	return &Page{
+		URL:          targetURL,
+		FinalURL:     doc.finalURL,
+		StatusCode:   doc.status,
+		Title:        doc.title,
+		Content:      content,
+		ContentHash:  ContentHash(content),
+		LastModified: doc.lastModified,
+	}, nil
+}
+
+// contentFromHTML runs Readability over rendered HTML and converts the
+// article to Markdown, prefixed with the title. When Readability fails or
+// finds too little text, the scoring pass in readableContent gets a turn.
+func contentFromHTML(targetURL, htmlContent, pageTitle string) (string, error) {
 	// 2. Use Readability to extract main content
 	parsedURL, err := url.Parse(targetURL)
 	if err != nil {
@@ -53,6 +243,11 @@ func (e *HybridExtractor) ExtractContent(ctx context.Context, targetURL string)
 	}
 
 	article, err := readability.FromReader(strings.NewReader(htmlContent), parsedURL)
+	if err != nil || utf8.RuneCountInString(strings.TrimSpace(article.TextContent)) < readableMinText {
+		if content, ok := scoredContent(targetURL, htmlContent, pageTitle); ok {
+			return content, nil
+		}
+	}
 	if err != nil {
 		// Fallback to title only if readability fails
 		if pageTitle != "" {
@@ -84,6 +279,109 @@ func (e *HybridExtractor) ExtractContent(ctx context.Context, targetURL string)
 	return result.String(), nil
 }
 
+// scoredContent converts the content readableContent picks to Markdown,
+// prefixed with the title. It returns ok=false when no container stands out
+// or the conversion fails.
+func scoredContent(targetURL, htmlContent, pageTitle string) (string, bool) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return "", false
+	}
+	if pageTitle == "" {
+		pageTitle = strings.TrimSpace(doc.Find("title").First().Text())
+	}
+
+	content, ok := readableContent(doc)
+	if !ok {
+		return "", false
+	}
+	articleHTML, err := goquery.OuterHtml(content)
+	if err != nil {
+		return "", false
+	}
+	markdown, err := markdownFromHTML(articleHTML, targetURL, pageTitle)
+	if err != nil {
+		return "", false
+	}
+	return markdown, true
+}
+
+// renderWithRecovery renders targetURL, and if the browser crashed or went
+// away, restarts it and tries once more on the fresh browser
+func (e *HybridExtractor) renderWithRecovery(ctx context.Context, targetURL string) (*renderedPage, error) {
+	page, err := e.renderPage(ctx, targetURL)
+	if !browserGone(ctx, err) {
+		return page, err
+	}
+
+	// A pooled browser is relaunched when its tab is released
+	if e.pool == nil {
+		if restartErr := e.browser.restart(); restartErr != nil {
+			return nil, fmt.Errorf("%w (restarting the browser failed: %v)", err, restartErr)
+		}
+	}
+	return e.renderPage(ctx, targetURL)
+}
+
+// renderedPage is the browser's view of a loaded page
+type renderedPage struct {
+	html         string
+	title        string
+	status       int
+	finalURL     string
+	mimeType     string
+	lastModified time.Time
+}
+
+// renderHTML loads targetURL in the browser and returns the rendered HTML,
+// page title and the main document's response status and URL. A PDF
+// response is returned as soon as its MIME type is known, unrendered.
+func (e *HybridExtractor) renderHTML(ctx context.Context, targetURL string) (*renderedPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	allocCtx, release, err := e.newTab(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a tab for %s: %w", targetURL, err)
+	}
+
+	page := &renderedPage{finalURL: targetURL}
+
+	resp, err := chromedp.RunResponse(allocCtx, chromedp.Navigate(targetURL))
+	if err == nil && resp != nil {
+		page.status = int(resp.Status)
+		page.finalURL = resp.URL
+		page.mimeType = resp.MimeType
+		page.lastModified = lastModifiedHeader(responseHeader(resp.Headers, "Last-Modified"))
+	}
+	// A PDF is downloaded and read directly, so there is nothing to render
+	if err == nil && !isPDFType(page.mimeType) {
+		err = chromedp.Run(allocCtx,
+			chromedp.WaitReady("body"),
+			chromedp.Title(&page.title),
+			chromedp.OuterHTML("html", &page.html),
+		)
+	}
+	release(browserGone(ctx, err))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rendered HTML from %s: %w", targetURL, err)
+	}
+
+	return page, nil
+}
+
+// responseHeader returns the named header of a DevTools response, whose
+// header names keep the server's case
+func responseHeader(headers network.Headers, name string) string {
+	for key, value := range headers {
+		if s, ok := value.(string); ok && strings.EqualFold(key, name) {
+			return s
+		}
+	}
+	return ""
+}
+
 // ExtractSummary extracts a summary-friendly version of the content
 func (e *HybridExtractor) ExtractSummary(ctx context.Context, url string, maxLength int) (string, error) {
 	content, err := e.ExtractContent(ctx, url)
@@ -91,18 +389,23 @@ func (e *HybridExtractor) ExtractSummary(ctx context.Context, url string, maxLen
 		return "", err
 	}
 
-	// Truncate if necessary
-	if len(content) > maxLength {
-		truncated := content[:maxLength]
-		lastPeriod := strings.LastIndex(truncated, ". ")
-		if lastPeriod > maxLength/2 {
-			content = truncated[:lastPeriod+1]
-		} else {
-			content = truncated + "..."
-		}
+	return truncateAtSentence(content, maxLength), nil
+}
+
+// truncateAtSentence cuts content to at most maxLength characters,
+// preferring to cut at the end of a sentence
+func truncateAtSentence(content string, maxLength int) string {
+	runes := []rune(content)
+	if len(runes) <= maxLength {
+		return content
 	}
 
-	return content, nil
+	truncated := string(runes[:maxLength])
+	lastPeriod := strings.LastIndex(truncated, ". ")
+	if lastPeriod > len(truncated)/2 {
+		return truncated[:lastPeriod+1]
+	}
+	return truncated + "..."
 }
 
 // ExtractMultiple extracts content from multiple URLs concurrently
@@ -124,3 +427,45 @@ func (e *HybridExtractor) ExtractMultiple(ctx context.Context, urls []string) ma
 
 	return results
 }
+
+// ExtractResult is the outcome of extracting one URL with
+// ExtractMultipleOrdered
+type ExtractResult struct {
+	URL     string
+	Content string
+	Err     error
+}
+
+// ExtractMultipleOrdered extracts content from multiple URLs concurrently,
+// returning one result per URL in the order the URLs were given
+func (e *HybridExtractor) ExtractMultipleOrdered(ctx context.Context, urls []string) []ExtractResult {
+	return extractOrdered(ctx, urls, e.concurrency, e.ExtractContent)
+}
+
+// extractOrdered runs extract over urls with at most concurrency calls in
+// flight, storing each result at its URL's index
+func extractOrdered(ctx context.Context, urls []string, concurrency int, extract func(context.Context, string) (string, error)) []ExtractResult {
+	results := make([]ExtractResult, len(urls))
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for i, targetURL := range urls {
+		wg.Add(1)
+		go func(idx int, targetURL string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			content, err := extract(ctx, targetURL)
+			results[idx] = ExtractResult{URL: targetURL, Content: content, Err: err}
+		}(i, targetURL)
+	}
+
+	wg.Wait()
+	return results
+}
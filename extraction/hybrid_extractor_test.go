@@ -0,0 +1,192 @@
+package extraction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+func TestExtractOrdered(t *testing.T) {
+	urls := []string{"https://a.example.com", "https://b.example.com", "https://c.example.com", "https://d.example.com"}
+
+	var inFlight, peak int32
+	extract := func(ctx context.Context, url string) (string, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+
+		// Earlier URLs finish last
+		switch url {
+		case urls[0]:
+			time.Sleep(30 * time.Millisecond)
+		case urls[1]:
+			time.Sleep(15 * time.Millisecond)
+		case urls[2]:
+			return "", errors.New("extraction failed")
+		}
+		return "content of " + url, nil
+	}
+
+	results := extractOrdered(context.Background(), urls, 2, extract)
+
+	if len(results) != len(urls) {
+		t.Fatalf("expected %d results, got %d", len(urls), len(results))
+	}
+	for i, r := range results {
+		if r.URL != urls[i] {
+			t.Errorf("result %d: expected %s, got %s", i, urls[i], r.URL)
+		}
+	}
+	if results[0].Content != "content of "+urls[0] {
+		t.Errorf("expected content to stay with its URL, got %q", results[0].Content)
+	}
+	if results[2].Err == nil || results[2].Content != "" {
+		t.Errorf("expected the failed URL to carry its error, got %+v", results[2])
+	}
+	if peak > 2 {
+		t.Errorf("expected at most 2 extractions at once, saw %d", peak)
+	}
+}
+
+func TestNewHybridExtractor_Concurrency(t *testing.T) {
+	if c := NewHybridExtractor().concurrency; c != 3 {
+		t.Errorf("expected default concurrency 3, got %d", c)
+	}
+	if c := NewHybridExtractor(WithConcurrency(5)).concurrency; c != 5 {
+		t.Errorf("expected concurrency 5, got %d", c)
+	}
+}
+
+func TestHybridExtractor_RecoversFromCrashedBrowser(t *testing.T) {
+	extractor := NewHybridExtractor()
+
+	var browsers []context.CancelFunc
+	extractor.browser.launch = func() (context.Context, context.CancelFunc, error) {
+		ctx, cancel := context.WithCancel(context.Background())
+		browsers = append(browsers, cancel)
+		return ctx, cancel, nil
+	}
+	if err := extractor.Warmup(context.Background()); err != nil {
+		t.Fatalf("unexpected warmup error: %v", err)
+	}
+	defer extractor.Close()
+
+	renders := 0
+	extractor.renderPage = func(ctx context.Context, targetURL string) (*renderedPage, error) {
+		renders++
+		if renders == 1 {
+			// The browser dies mid-extraction
+			browsers[0]()
+			return nil, fmt.Errorf("failed to fetch rendered HTML from %s: %w", targetURL, chromedp.ErrChannelClosed)
+		}
+		if !extractor.browser.ready() {
+			return nil, errors.New("expected a running browser on retry")
+		}
+		return &renderedPage{
+			html:     "<html><head><title>Recovered</title></head><body><article><p>" + strings.Repeat("The page rendered after the browser was restarted. ", 10) + "</p></article></body></html>",
+			title:    "Recovered",
+			status:   200,
+			finalURL: targetURL,
+		}, nil
+	}
+
+	page, err := extractor.ExtractPage(context.Background(), "https://example.com/")
+	if err != nil {
+		t.Fatalf("expected the extraction to succeed after recovery, got %v", err)
+	}
+	if !strings.Contains(page.Content, "browser was restarted") {
+		t.Errorf("unexpected content: %q", page.Content)
+	}
+	if len(browsers) != 2 {
+		t.Errorf("expected the browser to be relaunched once, got %d launches", len(browsers))
+	}
+	if renders != 2 {
+		t.Errorf("expected one retry, got %d renders", renders)
+	}
+
+	// Ordinary page failures are not retried
+	renders = 0
+	extractor.renderPage = func(ctx context.Context, targetURL string) (*renderedPage, error) {
+		renders++
+		return nil, errors.New("net::ERR_NAME_NOT_RESOLVED")
+	}
+	if _, err := extractor.ExtractPage(context.Background(), "https://missing.example/"); err == nil || renders != 1 {
+		t.Errorf("expected a single failed attempt, got %d renders and error %v", renders, err)
+	}
+}
+
+func TestHybridExtractor_ExtractPagePublishedAt(t *testing.T) {
+	extractor := NewHybridExtractor()
+	extractor.renderPage = func(ctx context.Context, targetURL string) (*renderedPage, error) {
+		return &renderedPage{
+			html:     `<html><head><meta property="article:published_time" content="2024-05-01T12:00:00Z"></head><body><article><p>` + strings.Repeat("A dated news article body. ", 10) + `</p></article></body></html>`,
+			status:   200,
+			finalURL: targetURL,
+		}, nil
+	}
+
+	page, err := extractor.ExtractPage(context.Background(), "https://example.com/news")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC); !page.PublishedAt.Equal(want) {
+		t.Errorf("expected published date %v, got %v", want, page.PublishedAt)
+	}
+}
+
+func TestPublishedFromHTML_Fallbacks(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want time.Time
+	}{
+		{"json-ld", `<script type="application/ld+json">{"@type": "NewsArticle", "datePublished": "2024-02-03"}</script>`, time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC)},
+		{"time element", `<article><time datetime="2023-11-20T08:15:00Z">Nov 20</time></article>`, time.Date(2023, 11, 20, 8, 15, 0, 0, time.UTC)},
+		{"none", `<p>No date here</p>`, time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := publishedFromHTML(tt.html); !got.Equal(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestResponseHeader(t *testing.T) {
+	headers := network.Headers{"last-modified": "Fri, 15 Mar 2024 08:30:00 GMT", "Content-Type": "text/html"}
+	if got := responseHeader(headers, "Last-Modified"); got != "Fri, 15 Mar 2024 08:30:00 GMT" {
+		t.Errorf("expected the header regardless of case, got %q", got)
+	}
+	if got := responseHeader(headers, "ETag"); got != "" {
+		t.Errorf("expected no value for a missing header, got %q", got)
+	}
+	if got := lastModifiedHeader(responseHeader(headers, "Last-Modified")); !got.Equal(time.Date(2024, 3, 15, 8, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected Last-Modified time %v", got)
+	}
+}
+
+func TestTruncateAtSentence(t *testing.T) {
+	if got := truncateAtSentence("Short.", 100); got != "Short." {
+		t.Errorf("expected short content unchanged, got %q", got)
+	}
+	if got := truncateAtSentence("First sentence here. Second one runs on", 30); got != "First sentence here." {
+		t.Errorf("expected a cut at the sentence end, got %q", got)
+	}
+
+	// The limit counts characters, and a cut never splits one
+	got := truncateAtSentence(strings.Repeat("é", 10), 5)
+	if got != strings.Repeat("é", 5)+"..." {
+		t.Errorf("expected 5 whole characters, got %q", got)
+	}
+}
@@ -0,0 +1,46 @@
+package extraction
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// jsShellTextLength is the most visible text a page can have and still be
+// treated as an empty shell waiting for JavaScript to render it
+const jsShellTextLength = 200
+
+// jsShellScripts is how many <script> tags mark a text-less page as
+// script-rendered even without a known framework marker
+const jsShellScripts = 3
+
+// jsFrameworkMarkers match the mount points and data blobs that
+// client-side frameworks leave in server-sent HTML
+const jsFrameworkMarkers = `script#__NEXT_DATA__, #__next, #__nuxt, #___gatsby,
+	[ng-app], [ng-version], app-root, [data-reactroot], [data-v-app],
+	div#app, div#root`
+
+// requiresJS reports whether a page is likely rendered client-side, so
+// fetching its HTML yields little content and it should be loaded in a
+// browser instead. A page qualifies when it has almost no visible text
+// but carries a framework marker (Next.js, Nuxt, Angular, React, Vue), a
+// noscript warning, or a pile of scripts.
+func requiresJS(doc *goquery.Document) bool {
+	body := doc.Find("body").Clone()
+	body.Find("script, style, noscript, template").Remove()
+	text := strings.Join(strings.Fields(body.Text()), " ")
+	if len(text) > jsShellTextLength {
+		return false
+	}
+
+	if doc.Find(jsFrameworkMarkers).Length() > 0 {
+		return true
+	}
+
+	noscript := strings.ToLower(doc.Find("noscript").Text())
+	if strings.Contains(noscript, "javascript") {
+		return true
+	}
+
+	return doc.Find("script").Length() >= jsShellScripts
+}
@@ -0,0 +1,70 @@
+package extraction
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func parseFixture(t *testing.T, html string) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	return doc
+}
+
+func TestRequiresJS(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want bool
+	}{
+		{
+			name: "next.js shell",
+			html: `<html><head><script src="/_next/static/chunks/main.js" defer></script></head>
+				<body><div id="__next"></div>
+				<script id="__NEXT_DATA__" type="application/json">{"props":{"pageProps":{}},"page":"/"}</script>
+				</body></html>`,
+			want: true,
+		},
+		{
+			name: "angular shell",
+			html: `<html><body ng-app="shop">
+				<app-root></app-root>
+				<noscript>Please enable JavaScript to continue using this application.</noscript>
+				<script src="runtime.js"></script><script src="main.js"></script>
+				</body></html>`,
+			want: true,
+		},
+		{
+			name: "script-only page",
+			html: `<html><body><div class="loading">Loading…</div>
+				<script src="a.js"></script><script src="b.js"></script><script src="c.js"></script>
+				</body></html>`,
+			want: true,
+		},
+		{
+			name: "server-rendered next.js page",
+			html: `<html><body><div id="__next"><article><h1>Server rendering</h1><p>` +
+				strings.Repeat("This page was rendered on the server and has plenty of text. ", 10) +
+				`</p></article></div><script id="__NEXT_DATA__" type="application/json">{}</script></body></html>`,
+			want: false,
+		},
+		{
+			name: "short static page",
+			html: `<html><body><h1>Contact</h1><p>Email us at hello@example.com.</p></body></html>`,
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requiresJS(parseFixture(t, tt.html)); got != tt.want {
+				t.Errorf("requiresJS() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
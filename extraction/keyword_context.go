@@ -0,0 +1,109 @@
+package extraction
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// passageSeparator joins non-adjacent passages returned by ExtractContext
+const passageSeparator = "\n\n[...]\n\n"
+
+// ExtractContext extracts the page's content and returns only the passages
+// within window characters of an occurrence of a query term, joined by a
+// separator. It returns an empty string if no term occurs in the page.
+func (e *HybridExtractor) ExtractContext(ctx context.Context, url, query string, window int) (string, error) {
+	content, err := e.ExtractContent(ctx, url)
+	if err != nil {
+		return "", err
+	}
+
+	return keywordContext(content, query, window), nil
+}
+
+// keywordContext returns the passages of content around query terms,
+// merging passages that overlap and widening each to whole words
+func keywordContext(content, query string, window int) string {
+	var terms []string
+	for _, word := range strings.Fields(query) {
+		word = strings.Trim(word, `"'.,;:!?()[]{}`)
+		if len([]rune(word)) > 2 {
+			terms = append(terms, regexp.QuoteMeta(word))
+		}
+	}
+	if len(terms) == 0 {
+		return ""
+	}
+
+	pattern := regexp.MustCompile(`(?i)` + strings.Join(terms, "|"))
+	matches := pattern.FindAllStringIndex(content, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+
+	// Build and merge [start, end) spans around each match
+	var spans [][2]int
+	for _, m := range matches {
+		start := wordStart(content, runesBefore(content, m[0], window))
+		end := wordEnd(content, runesAfter(content, m[1], window))
+
+		if n := len(spans); n > 0 && start <= spans[n-1][1] {
+			spans[n-1][1] = max(spans[n-1][1], end)
+			continue
+		}
+		spans = append(spans, [2]int{start, end})
+	}
+
+	passages := make([]string, 0, len(spans))
+	for _, span := range spans {
+		passages = append(passages, strings.TrimSpace(content[span[0]:span[1]]))
+	}
+
+	return strings.Join(passages, passageSeparator)
+}
+
+// runesBefore returns the byte offset n characters before i, or 0
+func runesBefore(s string, i, n int) int {
+	for ; n > 0 && i > 0; n-- {
+		_, size := utf8.DecodeLastRuneInString(s[:i])
+		i -= size
+	}
+	return i
+}
+
+// runesAfter returns the byte offset n characters after i, or len(s)
+func runesAfter(s string, i, n int) int {
+	for ; n > 0 && i < len(s); n-- {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return i
+}
+
+// wordStart moves i back to the start of the word it falls in, stepping by
+// rune so a multibyte character is never split
+func wordStart(s string, i int) int {
+	for i > 0 {
+		r, size := utf8.DecodeLastRuneInString(s[:i])
+		if unicode.IsSpace(r) {
+			break
+		}
+		i -= size
+	}
+	return i
+}
+
+// wordEnd moves i forward to the end of the word it falls in, stepping by
+// rune so a multibyte character is never split
+func wordEnd(s string, i int) int {
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if unicode.IsSpace(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
@@ -0,0 +1,95 @@
+package extraction
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestKeywordContext(t *testing.T) {
+	content := strings.Join([]string{
+		"The history of the city goes back centuries and includes many wars.",
+		strings.Repeat("Filler sentence about unrelated topics. ", 10),
+		"The population of the city reached two million in 1990.",
+		strings.Repeat("More unrelated filler text here. ", 10),
+		"Tourism is now the main industry, with the Population still growing.",
+	}, " ")
+
+	passages := keywordContext(content, "population", 30)
+
+	if strings.Contains(passages, "history") || strings.Contains(passages, "Filler") {
+		t.Errorf("expected unrelated text to be dropped, got %q", passages)
+	}
+
+	parts := strings.Split(passages, passageSeparator)
+	if len(parts) != 2 {
+		t.Fatalf("expected 2 passages, got %d: %q", len(parts), passages)
+	}
+
+	if !strings.Contains(parts[0], "population of the city reached two million") {
+		t.Errorf("expected first passage around the keyword, got %q", parts[0])
+	}
+	if !strings.Contains(parts[1], "Population still growing") {
+		t.Errorf("expected case-insensitive match in second passage, got %q", parts[1])
+	}
+
+	// Passages start and end on word boundaries
+	for _, p := range parts {
+		if strings.HasPrefix(p, "ller") || strings.HasSuffix(p, "fil") {
+			t.Errorf("passage cut mid-word: %q", p)
+		}
+	}
+
+	if len(passages) >= len(content)/2 {
+		t.Errorf("expected passages to be much shorter than the content")
+	}
+}
+
+func TestKeywordContext_MergesOverlaps(t *testing.T) {
+	content := "alpha beta gamma delta alpha epsilon"
+	passages := keywordContext(content, "alpha delta", 10)
+
+	if strings.Contains(passages, passageSeparator) {
+		t.Errorf("expected overlapping passages to merge, got %q", passages)
+	}
+	if passages != content {
+		t.Errorf("expected whole content, got %q", passages)
+	}
+}
+
+func TestKeywordContext_NoMatch(t *testing.T) {
+	if got := keywordContext("nothing relevant here", "population", 20); got != "" {
+		t.Errorf("expected empty result, got %q", got)
+	}
+	if got := keywordContext("short words", "a of", 20); got != "" {
+		t.Errorf("expected empty result for stop-word-only query, got %q", got)
+	}
+}
+
+func TestKeywordContext_Multibyte(t *testing.T) {
+	// à and Ġ end in the byte 0xA0, which a byte-wise check took for a
+	// no-break space
+	content := "Il était déjà là à Ġdańsk quand la population a doublé, voilà Ġdańsk à nouveau."
+	for window := 1; window < 40; window++ {
+		got := keywordContext(content, "population", window)
+		if !utf8.ValidString(got) {
+			t.Fatalf("window %d: expected valid UTF-8, got %q", window, got)
+		}
+		for _, word := range strings.Fields(got) {
+			if !strings.Contains(" "+content+" ", " "+word+" ") {
+				t.Errorf("window %d: expected whole words, got %q in %q", window, word, got)
+			}
+		}
+	}
+}
+
+func TestKeywordContext_WindowInCharacters(t *testing.T) {
+	// Each Cyrillic letter is two bytes; the window still counts letters
+	content := "жжжжж ббббб population ввввв ггггг"
+	if got := keywordContext(content, "population", 6); got != "ббббб population ввввв" {
+		t.Errorf("expected a window of 6 characters, got %q", got)
+	}
+	if got := keywordContext(content, "population", 11); got != content {
+		t.Errorf("expected a window of 11 characters to reach the outer words, got %q", got)
+	}
+}
@@ -0,0 +1,49 @@
+package extraction
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// FetchLastModified issues a HEAD request for url and returns the parsed
+// Last-Modified header. A zero time and nil error are returned when the
+// server doesn't send the header.
+func FetchLastModified(ctx context.Context, client *http.Client, url string) (time.Time, error) {
+	info, err := FetchPageInfo(ctx, client, url)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.LastModified, nil
+}
+
+// LastModified returns the page's Last-Modified header, if any
+func (e *HybridExtractor) LastModified(ctx context.Context, url string) (time.Time, error) {
+	info, err := e.PageInfo(ctx, url)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.LastModified, nil
+}
+
+// LastModified returns the page's Last-Modified header, if any
+func (e *ChromedpExtractor) LastModified(ctx context.Context, url string) (time.Time, error) {
+	info, err := e.PageInfo(ctx, url)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.LastModified, nil
+}
+
+// lastModifiedHeader parses a Last-Modified header value, returning zero
+// when it is missing or malformed
+func lastModifiedHeader(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,39 @@
+package extraction
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchLastModified(t *testing.T) {
+	modified := time.Date(2024, 3, 15, 8, 30, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("expected HEAD request, got %s", r.Method)
+		}
+		if r.URL.Path == "/dated" {
+			w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		}
+	}))
+	defer server.Close()
+
+	got, err := FetchLastModified(context.Background(), server.Client(), server.URL+"/dated")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Equal(modified) {
+		t.Errorf("expected %v, got %v", modified, got)
+	}
+
+	got, err = FetchLastModified(context.Background(), server.Client(), server.URL+"/undated")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.IsZero() {
+		t.Errorf("expected zero time without header, got %v", got)
+	}
+}
@@ -0,0 +1,42 @@
+package extraction
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+)
+
+// markdownFromHTML converts an HTML fragment to Markdown, resolving
+// relative links and images against pageURL, prefixed with the title
+func markdownFromHTML(fragment, pageURL, title string) (string, error) {
+	markdown, err := htmltomarkdown.ConvertString(fragment, converter.WithDomain(pageURL))
+	if err != nil {
+		return "", fmt.Errorf("failed to convert %s to Markdown: %w", pageURL, err)
+	}
+
+	markdown = cleanMarkdown(markdown)
+	if title != "" {
+		return fmt.Sprintf("# %s\n\n%s", title, markdown), nil
+	}
+	return markdown, nil
+}
+
+// cleanMarkdown empties whitespace-only lines and collapses runs of blank
+// lines. Unlike CleanText it keeps each line's indentation, which nests
+// lists and code blocks.
+func cleanMarkdown(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[i] = ""
+		}
+	}
+
+	result := strings.Trim(strings.Join(lines, "\n"), "\n")
+	for strings.Contains(result, "\n\n\n") {
+		result = strings.ReplaceAll(result, "\n\n\n", "\n\n")
+	}
+	return result
+}
@@ -0,0 +1,371 @@
+package extraction
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// AuthorInfo identifies who wrote a page and, where known, the outlet or
+// organization they wrote it for
+type AuthorInfo struct {
+	Name        string `json:"name"`
+	Affiliation string `json:"affiliation,omitempty"`
+	ProfileURL  string `json:"profile_url,omitempty"`
+}
+
+// PageMetadata is descriptive information about a page, as opposed to its
+// content
+type PageMetadata struct {
+	Title       string      `json:"title"`
+	Description string      `json:"description,omitempty"`
+	SiteName    string      `json:"site_name,omitempty"`
+	Author      *AuthorInfo `json:"author,omitempty"`
+	// Image is the preview image, resolved to an absolute URL
+	Image string `json:"image,omitempty"`
+	// Type is the page's og:type or JSON-LD @type, such as "article"
+	Type        string    `json:"type,omitempty"`
+	PublishedAt time.Time `json:"published_at,omitzero"`
+	// OpenGraph and Twitter hold every og:* and twitter:* meta tag, keyed
+	// without the prefix
+	OpenGraph map[string]string `json:"open_graph,omitempty"`
+	Twitter   map[string]string `json:"twitter,omitempty"`
+	// JSONLD holds the objects from the page's JSON-LD blocks, with @graph
+	// arrays flattened
+	JSONLD []map[string]any `json:"json_ld,omitempty"`
+}
+
+// ExtractMetadata returns the page's title, description, site name and
+// author byline
+func (e *HybridExtractor) ExtractMetadata(ctx context.Context, targetURL string) (*PageMetadata, error) {
+	rendered, err := e.renderHTML(ctx, targetURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMetadata(rendered.finalURL, rendered.html)
+}
+
+// parseMetadata reads metadata from an HTML document. Relative URLs are
+// resolved against pageURL.
+func parseMetadata(pageURL, htmlContent string) (*PageMetadata, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	return documentMetadata(pageURL, doc), nil
+}
+
+// documentMetadata reads metadata from a parsed document, preferring
+// OpenGraph, then Twitter card tags, then JSON-LD and plain HTML
+func documentMetadata(pageURL string, doc *goquery.Document) *PageMetadata {
+	base, _ := url.Parse(pageURL)
+	jsonLD := jsonLDDocuments(doc)
+
+	meta := &PageMetadata{
+		Title:       firstNonEmpty(metaContent(doc, "og:title"), metaContent(doc, "twitter:title"), strings.TrimSpace(doc.Find("title").First().Text())),
+		Description: firstNonEmpty(metaContent(doc, "description"), metaContent(doc, "og:description"), metaContent(doc, "twitter:description")),
+		SiteName:    metaContent(doc, "og:site_name"),
+		Image:       resolveURL(base, firstNonEmpty(metaContent(doc, "og:image"), metaContent(doc, "twitter:image"), jsonLDString(jsonLD, "image"))),
+		Type:        firstNonEmpty(metaContent(doc, "og:type"), jsonLDString(jsonLD, "@type")),
+		OpenGraph:   prefixedMeta(doc, "og:"),
+		Twitter:     prefixedMeta(doc, "twitter:"),
+		JSONLD:      jsonLD,
+	}
+	meta.PublishedAt = documentPublished(doc, jsonLD)
+
+	meta.Author = jsonLDAuthor(doc)
+	if meta.Author == nil {
+		meta.Author = bylineAuthor(doc)
+	}
+	if meta.Author != nil {
+		if meta.Author.Affiliation == "" {
+			meta.Author.Affiliation = meta.SiteName
+		}
+		meta.Author.ProfileURL = resolveURL(base, meta.Author.ProfileURL)
+	}
+
+	return meta
+}
+
+// prefixedMeta returns the <meta> tags whose name or property starts with
+// prefix, keyed without it. The first tag wins for repeated keys.
+func prefixedMeta(doc *goquery.Document, prefix string) map[string]string {
+	tags := map[string]string{}
+	doc.Find("meta[content]").Each(func(i int, s *goquery.Selection) {
+		key := firstNonEmpty(attrValue(s, "property"), attrValue(s, "name"))
+		if !strings.HasPrefix(key, prefix) {
+			return
+		}
+		key = strings.TrimPrefix(key, prefix)
+		if _, ok := tags[key]; !ok {
+			tags[key] = strings.TrimSpace(attrValue(s, "content"))
+		}
+	})
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+func attrValue(s *goquery.Selection, name string) string {
+	v, _ := s.Attr(name)
+	return strings.TrimSpace(v)
+}
+
+// jsonLDDocuments returns the objects of every parseable JSON-LD block
+func jsonLDDocuments(doc *goquery.Document) []map[string]any {
+	var objs []map[string]any
+	doc.Find(`script[type="application/ld+json"]`).Each(func(i int, s *goquery.Selection) {
+		var data any
+		if err := json.Unmarshal([]byte(s.Text()), &data); err == nil {
+			objs = append(objs, jsonLDObjects(data)...)
+		}
+	})
+	return objs
+}
+
+// jsonLDString returns the first string value of key across objs. Lists
+// and objects such as ImageObject yield their first string or url.
+func jsonLDString(objs []map[string]any, key string) string {
+	for _, obj := range objs {
+		if v := jsonLDText(obj[key]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func jsonLDText(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []any:
+		for _, item := range v {
+			if s := jsonLDText(item); s != "" {
+				return s
+			}
+		}
+	case map[string]any:
+		return jsonLDText(v["url"])
+	}
+	return ""
+}
+
+// documentPublished returns the page's publication date from the
+// article:published_time tag, JSON-LD datePublished or the first <time>
+// element, in that order. It is zero when none is found.
+func documentPublished(doc *goquery.Document, jsonLD []map[string]any) time.Time {
+	return parsePublished(firstNonEmpty(
+		metaContent(doc, "article:published_time"),
+		jsonLDString(jsonLD, "datePublished"),
+		attrValue(doc.Find("time[datetime]").First(), "datetime"),
+	))
+}
+
+// publishedFromHTML is documentPublished for an unparsed page
+func publishedFromHTML(htmlContent string) time.Time {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return time.Time{}
+	}
+	return documentPublished(doc, jsonLDDocuments(doc))
+}
+
+// publishedLayouts are the date formats seen in published-time metadata
+var publishedLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parsePublished parses a published date, returning the zero time when it
+// is missing or in an unknown format
+func parsePublished(value string) time.Time {
+	for _, layout := range publishedLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// metaContent returns the content of a <meta> tag by name or property
+func metaContent(doc *goquery.Document, name string) string {
+	sel := fmt.Sprintf(`meta[name=%q], meta[property=%q]`, name, name)
+	content, _ := doc.Find(sel).First().Attr("content")
+	return strings.TrimSpace(content)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func resolveURL(base *url.URL, ref string) string {
+	if ref == "" || base == nil {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+// jsonLDAuthor returns the first author found in the page's JSON-LD blocks.
+// The publisher's name is used as the affiliation when the author has none.
+func jsonLDAuthor(doc *goquery.Document) *AuthorInfo {
+	var author *AuthorInfo
+	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(i int, s *goquery.Selection) bool {
+		var data any
+		if err := json.Unmarshal([]byte(s.Text()), &data); err != nil {
+			return true
+		}
+		for _, obj := range jsonLDObjects(data) {
+			a := parseJSONLDAuthor(obj["author"])
+			if a == nil {
+				continue
+			}
+			if a.Affiliation == "" {
+				a.Affiliation = jsonLDName(obj["publisher"])
+			}
+			author = a
+			return false
+		}
+		return true
+	})
+	return author
+}
+
+// jsonLDObjects flattens a JSON-LD value, including @graph arrays, into its
+// objects
+func jsonLDObjects(v any) []map[string]any {
+	switch v := v.(type) {
+	case []any:
+		var objs []map[string]any
+		for _, item := range v {
+			objs = append(objs, jsonLDObjects(item)...)
+		}
+		return objs
+	case map[string]any:
+		objs := []map[string]any{v}
+		if graph, ok := v["@graph"]; ok {
+			objs = append(objs, jsonLDObjects(graph)...)
+		}
+		return objs
+	}
+	return nil
+}
+
+// parseJSONLDAuthor reads an author given as a name, a Person object or a
+// list of either, taking the first
+func parseJSONLDAuthor(v any) *AuthorInfo {
+	switch v := v.(type) {
+	case string:
+		if name := strings.TrimSpace(v); name != "" {
+			return &AuthorInfo{Name: name}
+		}
+	case []any:
+		for _, item := range v {
+			if a := parseJSONLDAuthor(item); a != nil {
+				return a
+			}
+		}
+	case map[string]any:
+		name := jsonLDName(v)
+		if name == "" {
+			return nil
+		}
+		a := &AuthorInfo{
+			Name:        name,
+			Affiliation: firstNonEmpty(jsonLDName(v["affiliation"]), jsonLDName(v["worksFor"])),
+		}
+		if profile, ok := v["url"].(string); ok {
+			a.ProfileURL = profile
+		} else if sameAs, ok := v["sameAs"].(string); ok {
+			a.ProfileURL = sameAs
+		}
+		return a
+	}
+	return nil
+}
+
+// jsonLDName returns the name of a JSON-LD value given as a string, an
+// object with a name or a list of either
+func jsonLDName(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case map[string]any:
+		name, _ := v["name"].(string)
+		return strings.TrimSpace(name)
+	case []any:
+		for _, item := range v {
+			if name := jsonLDName(item); name != "" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+var bylinePrefix = regexp.MustCompile(`(?i)^\s*(by|written by|author:)\s+`)
+
+// bylineAuthor reads the author from rel="author" links, common byline
+// elements or the author meta tag. Bylines such as "By Jane Doe, Reuters"
+// are split into name and affiliation.
+func bylineAuthor(doc *goquery.Document) *AuthorInfo {
+	if link := doc.Find(`a[rel~="author"]`).First(); link.Length() > 0 {
+		if name := strings.TrimSpace(link.Text()); name != "" {
+			a := &AuthorInfo{Name: name}
+			a.ProfileURL, _ = link.Attr("href")
+			// The surrounding byline may name the outlet, as in
+			// "By <a>Jane Doe</a>, Reuters"
+			if byline := splitByline(bylineText(link.Parent())); byline != nil && byline.Name == name {
+				a.Affiliation = byline.Affiliation
+			}
+			return a
+		}
+	}
+
+	byline := doc.Find(`[itemprop="author"], .byline, .author`).First()
+	if byline.Length() > 0 {
+		if a := splitByline(bylineText(byline)); a != nil {
+			a.ProfileURL, _ = byline.Find("a[href]").First().Attr("href")
+			return a
+		}
+	}
+
+	return splitByline(metaContent(doc, "author"))
+}
+
+func bylineText(s *goquery.Selection) string {
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
+
+// splitByline turns "By Jane Doe, Reuters" into its name and affiliation
+func splitByline(text string) *AuthorInfo {
+	text = strings.TrimSpace(bylinePrefix.ReplaceAllString(text, ""))
+	if text == "" {
+		return nil
+	}
+
+	name, affiliation, _ := strings.Cut(text, ",")
+	return &AuthorInfo{
+		Name:        strings.TrimSpace(name),
+		Affiliation: strings.TrimSpace(affiliation),
+	}
+}
@@ -0,0 +1,114 @@
+package extraction
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseMetadata_JSONLDAuthor(t *testing.T) {
+	html := `<html><head>
+		<title>Markets rally</title>
+		<meta name="description" content="Stocks rose on Tuesday.">
+		<script type="application/ld+json">
+		{
+			"@context": "https://schema.org",
+			"@graph": [
+				{"@type": "WebSite", "name": "Example News"},
+				{
+					"@type": "NewsArticle",
+					"headline": "Markets rally",
+					"author": [{"@type": "Person", "name": "Jane Doe", "url": "/authors/jane-doe"}],
+					"publisher": {"@type": "Organization", "name": "Reuters"}
+				}
+			]
+		}
+		</script>
+	</head><body></body></html>`
+
+	meta, err := parseMetadata("https://news.example.com/markets/story", html)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if meta.Title != "Markets rally" || meta.Description != "Stocks rose on Tuesday." {
+		t.Errorf("unexpected title/description: %+v", meta)
+	}
+
+	expected := AuthorInfo{
+		Name:        "Jane Doe",
+		Affiliation: "Reuters",
+		ProfileURL:  "https://news.example.com/authors/jane-doe",
+	}
+	if meta.Author == nil || *meta.Author != expected {
+		t.Errorf("expected author %+v, got %+v", expected, meta.Author)
+	}
+}
+
+func TestParseMetadata_JSONLDAuthorAffiliation(t *testing.T) {
+	html := `<script type="application/ld+json">
+		{"@type": "ScholarlyArticle", "author": {"name": "Dr. Ada Lovelace", "affiliation": {"name": "University of London"}}}
+	</script>`
+
+	meta, err := parseMetadata("https://example.org/paper", html)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if meta.Author == nil || meta.Author.Name != "Dr. Ada Lovelace" || meta.Author.Affiliation != "University of London" {
+		t.Errorf("unexpected author: %+v", meta.Author)
+	}
+}
+
+func TestParseMetadata_HTMLByline(t *testing.T) {
+	html := `<html><body>
+		<article>
+			<h1>Go 1.24 released</h1>
+			<p class="byline">By <a rel="author" href="../people/john-smith">John Smith</a>, The Gopher Times</p>
+			<p>Body text.</p>
+		</article>
+	</body></html>`
+
+	meta, err := parseMetadata("https://example.com/news/2025/go-124", html)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := AuthorInfo{
+		Name:        "John Smith",
+		Affiliation: "The Gopher Times",
+		ProfileURL:  "https://example.com/news/people/john-smith",
+	}
+	if meta.Author == nil || *meta.Author != expected {
+		t.Errorf("expected author %+v, got %+v", expected, meta.Author)
+	}
+}
+
+func TestParseMetadata_PlainBylineAndMeta(t *testing.T) {
+	meta, _ := parseMetadata("https://example.com/a", `<div class="byline">Written by Sam Lee, Example Daily</div>`)
+	if meta.Author == nil || meta.Author.Name != "Sam Lee" || meta.Author.Affiliation != "Example Daily" {
+		t.Errorf("unexpected author from byline: %+v", meta.Author)
+	}
+
+	meta, _ = parseMetadata("https://example.com/a", `<head><meta name="author" content="Kim Park"><meta property="og:site_name" content="Kim's Blog"></head>`)
+	if meta.Author == nil || meta.Author.Name != "Kim Park" || meta.Author.Affiliation != "Kim's Blog" {
+		t.Errorf("unexpected author from meta tag: %+v", meta.Author)
+	}
+
+	meta, _ = parseMetadata("https://example.com/a", `<p>No author here</p>`)
+	if meta.Author != nil {
+		t.Errorf("expected no author, got %+v", meta.Author)
+	}
+}
+
+func TestParsePublished(t *testing.T) {
+	tests := map[string]time.Time{
+		"2024-03-15T08:30:00+02:00": time.Date(2024, 3, 15, 6, 30, 0, 0, time.UTC),
+		"2024-03-15":                time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+		"March 15th":                {},
+		"":                          {},
+	}
+	for value, want := range tests {
+		if got := parsePublished(value); !got.Equal(want) {
+			t.Errorf("parsePublished(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package extraction
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Heading is a single entry in a page's heading outline
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id,omitempty"`
+}
+
+// ExtractOutline returns the page's h1–h3 headings in document order, for
+// navigating long documents before reading them in full
+func (e *HybridExtractor) ExtractOutline(ctx context.Context, targetURL string) ([]Heading, error) {
+	rendered, err := e.renderHTML(ctx, targetURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseOutline(rendered.html)
+}
+
+// parseOutline collects h1–h3 headings from an HTML document
+func parseOutline(htmlContent string) ([]Heading, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	var headings []Heading
+	doc.Find("h1, h2, h3").Each(func(i int, s *goquery.Selection) {
+		text := strings.Join(strings.Fields(s.Text()), " ")
+		if text == "" {
+			return
+		}
+
+		id, _ := s.Attr("id")
+		headings = append(headings, Heading{
+			Level: int(goquery.NodeName(s)[1] - '0'),
+			Text:  text,
+			ID:    id,
+		})
+	})
+
+	return headings, nil
+}
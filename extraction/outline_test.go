@@ -0,0 +1,39 @@
+package extraction
+
+import "testing"
+
+func TestParseOutline(t *testing.T) {
+	html := `<html><body>
+		<h1>Guide Title</h1>
+		<p>Intro text</p>
+		<h2 id="install">Installation</h2>
+		<h3>On   Linux</h3>
+		<h4>Too deep to include</h4>
+		<h3>On macOS</h3>
+		<h2>Usage</h2>
+		<h2>   </h2>
+	</body></html>`
+
+	headings, err := parseOutline(html)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Heading{
+		{Level: 1, Text: "Guide Title"},
+		{Level: 2, Text: "Installation", ID: "install"},
+		{Level: 3, Text: "On Linux"},
+		{Level: 3, Text: "On macOS"},
+		{Level: 2, Text: "Usage"},
+	}
+
+	if len(headings) != len(expected) {
+		t.Fatalf("expected %d headings, got %d: %+v", len(expected), len(headings), headings)
+	}
+
+	for i, want := range expected {
+		if headings[i] != want {
+			t.Errorf("heading %d = %+v, want %+v", i, headings[i], want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package extraction
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var headClient = &http.Client{Timeout: 10 * time.Second}
+
+// PageInfo holds response metadata for a page, gathered without extracting
+// its content
+type PageInfo struct {
+	StatusCode   int
+	FinalURL     string
+	LastModified time.Time
+}
+
+// FetchPageInfo issues a HEAD request for url, following redirects, and
+// returns the final status code and URL along with the parsed Last-Modified
+// header. LastModified is zero when the server doesn't send the header.
+func FetchPageInfo(ctx context.Context, client *http.Client, url string) (*PageInfo, error) {
+	if client == nil {
+		client = headClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch headers from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	info := &PageInfo{
+		StatusCode: resp.StatusCode,
+		FinalURL:   resp.Request.URL.String(),
+	}
+
+	if header := resp.Header.Get("Last-Modified"); header != "" {
+		lastModified, err := http.ParseTime(header)
+		if err != nil {
+			return info, fmt.Errorf("invalid Last-Modified header %q: %w", header, err)
+		}
+		info.LastModified = lastModified
+	}
+
+	return info, nil
+}
+
+// PageInfo returns the page's status, final URL and Last-Modified header,
+// fetched through the extractor's proxy if it has one
+func (e *HybridExtractor) PageInfo(ctx context.Context, url string) (*PageInfo, error) {
+	if e.proxyErr != nil {
+		return nil, e.proxyErr
+	}
+	return FetchPageInfo(ctx, e.client, url)
+}
+
+// PageInfo returns the page's status, final URL and Last-Modified header,
+// fetched through the extractor's proxy if it has one
+func (e *ChromedpExtractor) PageInfo(ctx context.Context, url string) (*PageInfo, error) {
+	if e.proxyErr != nil {
+		return nil, e.proxyErr
+	}
+	return FetchPageInfo(ctx, e.client, url)
+}
@@ -0,0 +1,87 @@
+package extraction
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchPageInfo(t *testing.T) {
+	modified := time.Date(2024, 3, 15, 8, 30, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("expected HEAD request, got %s", r.Method)
+		}
+		switch r.URL.Path {
+		case "/dated":
+			w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		case "/moved":
+			http.Redirect(w, r, "/gone", http.StatusMovedPermanently)
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		}
+	}))
+	defer server.Close()
+
+	info, err := FetchPageInfo(context.Background(), server.Client(), server.URL+"/dated")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.LastModified.Equal(modified) {
+		t.Errorf("expected %v, got %v", modified, info.LastModified)
+	}
+	if info.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", info.StatusCode)
+	}
+
+	info, err = FetchPageInfo(context.Background(), server.Client(), server.URL+"/moved")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.LastModified.IsZero() {
+		t.Errorf("expected zero time without header, got %v", info.LastModified)
+	}
+	if info.StatusCode != http.StatusGone {
+		t.Errorf("expected status 410 after redirect, got %d", info.StatusCode)
+	}
+	if info.FinalURL != server.URL+"/gone" {
+		t.Errorf("expected final URL %s/gone, got %s", server.URL, info.FinalURL)
+	}
+}
+
+func TestPageInfoThroughProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.Method+" "+r.URL.String())
+		w.Header().Set("Last-Modified", "Fri, 15 Mar 2024 08:30:00 GMT")
+	}))
+	defer proxy.Close()
+
+	fetchers := map[string]interface {
+		PageInfo(ctx context.Context, url string) (*PageInfo, error)
+	}{
+		"hybrid":   NewHybridExtractor(WithProxyServer(proxy.URL)),
+		"chromedp": NewChromedpExtractor(WithChromedpProxyServer(proxy.URL)),
+		"http":     NewHTTPExtractor(WithHTTPProxy(proxy.URL)),
+	}
+	for name, fetcher := range fetchers {
+		proxied = nil
+		info, err := fetcher.PageInfo(context.Background(), "http://pages.example/post")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if len(proxied) != 1 || proxied[0] != "HEAD http://pages.example/post" {
+			t.Errorf("%s: expected the HEAD request to go through the proxy, got %v", name, proxied)
+		}
+		if info.LastModified.IsZero() {
+			t.Errorf("%s: expected the Last-Modified header, got %+v", name, info)
+		}
+	}
+
+	if _, err := NewHTTPExtractor(WithHTTPProxy("ftp://proxy:21")).ExtractContent(context.Background(), proxy.URL); err == nil {
+		t.Error("expected an invalid proxy to fail HTTP extraction")
+	}
+}
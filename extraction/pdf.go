@@ -0,0 +1,130 @@
+package extraction
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// maxPDFSize caps how much of a PDF is downloaded for text extraction
+const maxPDFSize = 20 << 20
+
+// errNotPDF is returned by fetchPDF when the server answers with something
+// other than a PDF, so the page can be rendered normally instead
+var errNotPDF = errors.New("response is not a PDF")
+
+// pdfDocument is a downloaded PDF's text and metadata
+type pdfDocument struct {
+	finalURL     string
+	status       int
+	title        string
+	text         string
+	lastModified time.Time
+}
+
+// isPDFURL reports whether rawURL's path names a PDF file
+func isPDFURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(strings.ToLower(u.Path), ".pdf")
+}
+
+// isPDFType reports whether a Content-Type or MIME type is PDF
+func isPDFType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/pdf"
+}
+
+// fetchPDF downloads targetURL and extracts its text, failing with
+// errNotPDF if the server doesn't serve a PDF
+func fetchPDF(ctx context.Context, client *http.Client, targetURL string) (*pdfDocument, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", targetURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	req.Header.Set("Accept", "application/pdf,*/*;q=0.8")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", targetURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("failed to fetch %s: status %d", targetURL, resp.StatusCode)
+	}
+	if !isPDFType(resp.Header.Get("Content-Type")) {
+		return nil, errNotPDF
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPDFSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", targetURL, err)
+	}
+	if len(data) > maxPDFSize {
+		return nil, fmt.Errorf("PDF %s is larger than %d bytes", targetURL, maxPDFSize)
+	}
+
+	title, text, err := pdfText(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract text from PDF %s: %w", targetURL, err)
+	}
+
+	return &pdfDocument{
+		finalURL:     resp.Request.URL.String(),
+		status:       resp.StatusCode,
+		title:        title,
+		text:         text,
+		lastModified: lastModifiedHeader(resp.Header.Get("Last-Modified")),
+	}, nil
+}
+
+// pdfText returns a PDF's title from its document information and the
+// plain text of all its pages
+func pdfText(data []byte) (title, text string, err error) {
+	// The parser panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed PDF: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", "", err
+	}
+
+	plain, err := reader.GetPlainText()
+	if err != nil {
+		return "", "", err
+	}
+	body, err := io.ReadAll(plain)
+	if err != nil {
+		return "", "", err
+	}
+
+	title = strings.TrimSpace(reader.Trailer().Key("Info").Key("Title").Text())
+	return title, string(body), nil
+}
+
+// pdfContent formats a PDF's text like page content, prefixed with the
+// title when the PDF has one
+func pdfContent(doc *pdfDocument) string {
+	text := CleanText(doc.text)
+	if doc.title != "" {
+		return fmt.Sprintf("# %s\n\n%s", doc.title, text)
+	}
+	return text
+}
@@ -0,0 +1,149 @@
+package extraction
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// buildPDF writes a one-page PDF showing lines of text, with title in its
+// document information
+func buildPDF(title string, lines ...string) []byte {
+	var stream strings.Builder
+	stream.WriteString("BT /F1 12 Tf 72 720 Td 14 TL\n")
+	for _, line := range lines {
+		fmt.Fprintf(&stream, "(%s) Tj T*\n", line)
+	}
+	stream.WriteString("ET")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", stream.Len(), stream.String()),
+		fmt.Sprintf("<< /Title (%s) >>", title),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, len(objects), xref)
+	return buf.Bytes()
+}
+
+func TestPDFText(t *testing.T) {
+	title, text, err := pdfText(buildPDF("Annual Report", "Revenue grew steadily.", "Costs fell."))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if title != "Annual Report" {
+		t.Errorf("expected the title from the document information, got %q", title)
+	}
+	if !strings.Contains(text, "Revenue grew steadily.") || !strings.Contains(text, "Costs fell.") {
+		t.Errorf("expected the page text, got %q", text)
+	}
+
+	if _, _, err := pdfText([]byte("not a pdf")); err == nil {
+		t.Error("expected an error for a malformed PDF")
+	}
+}
+
+func TestHybridExtractor_PDF(t *testing.T) {
+	doc := buildPDF("Annual Report", "Revenue grew steadily.")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/report.pdf", "/download":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Last-Modified", "Fri, 15 Mar 2024 08:30:00 GMT")
+			w.Write(doc)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html><body>Not a PDF</body></html>"))
+		}
+	}))
+	defer server.Close()
+
+	e := NewHybridExtractor()
+	e.client = server.Client()
+	e.renderPage = func(ctx context.Context, targetURL string) (*renderedPage, error) {
+		if strings.HasSuffix(targetURL, "/download") {
+			return &renderedPage{finalURL: targetURL, status: http.StatusOK, mimeType: "application/pdf"}, nil
+		}
+		if strings.HasSuffix(targetURL, "/fake.pdf") {
+			return &renderedPage{
+				html:     "<html><head><title>Fake</title></head><body><article><p>An HTML page behind a PDF-looking URL, long enough to be kept as the article content.</p></article></body></html>",
+				title:    "Fake",
+				finalURL: targetURL,
+				status:   http.StatusOK,
+			}, nil
+		}
+		return nil, errors.New("PDFs should not be rendered")
+	}
+
+	// A .pdf URL is read without the browser
+	page, err := e.ExtractPage(context.Background(), server.URL+"/report.pdf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(page.Content, "# Annual Report\n\n") || !strings.Contains(page.Content, "Revenue grew steadily.") {
+		t.Errorf("expected titled PDF text, got %q", page.Content)
+	}
+	if page.StatusCode != http.StatusOK || page.ContentHash != ContentHash(page.Content) {
+		t.Errorf("unexpected page: %+v", page)
+	}
+	if !page.LastModified.Equal(time.Date(2024, 3, 15, 8, 30, 0, 0, time.UTC)) {
+		t.Errorf("expected the PDF's Last-Modified date, got %v", page.LastModified)
+	}
+
+	// A PDF without a .pdf path is recognized from the browser's MIME type
+	content, err := e.ExtractContent(context.Background(), server.URL+"/download")
+	if err != nil || !strings.Contains(content, "Revenue grew steadily.") {
+		t.Errorf("expected PDF text for a PDF served without a .pdf path, got %q (err %v)", content, err)
+	}
+
+	// A .pdf URL that serves HTML is rendered as usual
+	content, err = e.ExtractContent(context.Background(), server.URL+"/fake.pdf")
+	if err != nil || !strings.Contains(content, "An HTML page") {
+		t.Errorf("expected the HTML page to be rendered, got %q (err %v)", content, err)
+	}
+}
+
+func TestHybridExtractor_PDFThroughProxy(t *testing.T) {
+	doc := buildPDF("Proxied Report", "Fetched through the proxy.")
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write(doc)
+	}))
+	defer proxy.Close()
+
+	e := NewHybridExtractor(WithProxyServer(proxy.URL))
+	content, err := e.ExtractContent(context.Background(), "http://reports.example/annual.pdf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(content, "Fetched through the proxy.") {
+		t.Errorf("expected the PDF text, got %q", content)
+	}
+	if len(proxied) != 1 || proxied[0] != "http://reports.example/annual.pdf" {
+		t.Errorf("expected the PDF to be requested through the proxy, got %v", proxied)
+	}
+}
@@ -0,0 +1,146 @@
+package extraction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrPoolClosed is returned when a tab is requested from a closed
+// BrowserPool
+var ErrPoolClosed = errors.New("browser pool is closed")
+
+// ErrPoolProxyMismatch is returned when an extractor with a proxy is given
+// a BrowserPool whose browsers don't use that proxy, which would otherwise
+// connect directly
+var ErrPoolProxyMismatch = errors.New("browser pool does not use the extractor's proxy")
+
+// BrowserPool keeps up to size headless Chrome instances running and hands
+// out one tab at a time on each, so extractions reuse a few warm browsers
+// instead of launching one per call. Browsers are launched on first use.
+type BrowserPool struct {
+	browsers []*sharedBrowser
+	// idle holds the browsers not currently serving a tab; receiving from
+	// it is what bounds the pool to size tabs in flight
+	idle      chan *sharedBrowser
+	done      chan struct{}
+	closeOnce sync.Once
+	// chrome is where the pool's browsers come from; proxyErr records an
+	// invalid proxy and fails every tab
+	chrome   chromeConfig
+	proxyErr error
+}
+
+// PoolOption configures a BrowserPool
+type PoolOption func(*BrowserPool)
+
+// WithPoolProxyServer launches the pool's browsers behind the proxy at
+// proxyURL; see WithProxyServer. Extractors with a proxy only take tabs
+// from a pool that uses the same one.
+func WithPoolProxyServer(proxyURL string) PoolOption {
+	return func(p *BrowserPool) {
+		p.chrome.proxyServer, p.proxyErr = parseProxyServer(proxyURL)
+	}
+}
+
+// NewBrowserPool creates a pool of size browsers; size below 1 means 1.
+// Close it to shut the browsers down.
+func NewBrowserPool(size int, opts ...PoolOption) *BrowserPool {
+	if size < 1 {
+		size = 1
+	}
+
+	p := &BrowserPool{
+		idle:   make(chan *sharedBrowser, size),
+		done:   make(chan struct{}),
+		chrome: defaultChromeConfig(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	for i := 0; i < size; i++ {
+		b := &sharedBrowser{launch: browserLauncher(p.chrome)}
+		p.browsers = append(p.browsers, b)
+		p.idle <- b
+	}
+	return p
+}
+
+// Size returns how many browsers the pool may run
+func (p *BrowserPool) Size() int {
+	return len(p.browsers)
+}
+
+// tab waits for an idle browser, launching it if needed, and opens a tab on
+// it. release closes the tab and returns the browser to the pool; pass
+// broken=true when the browser crashed so it is relaunched on next use.
+func (p *BrowserPool) tab(ctx context.Context) (tabCtx context.Context, release func(broken bool), err error) {
+	if p.proxyErr != nil {
+		return nil, nil, p.proxyErr
+	}
+
+	var b *sharedBrowser
+	select {
+	case <-p.done:
+		return nil, nil, ErrPoolClosed
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case b = <-p.idle:
+	}
+
+	// done and idle may both have been ready
+	select {
+	case <-p.done:
+		p.idle <- b
+		return nil, nil, ErrPoolClosed
+	default:
+	}
+
+	if err := b.start(); err != nil {
+		p.idle <- b
+		return nil, nil, err
+	}
+
+	tabCtx, cancel, ok := b.newTab(ctx)
+	if !ok {
+		p.idle <- b
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		return nil, nil, fmt.Errorf("pooled browser exited before a tab could be opened")
+	}
+
+	var once sync.Once
+	return tabCtx, func(broken bool) {
+		once.Do(func() {
+			cancel()
+			if broken {
+				b.close()
+			}
+			p.idle <- b
+		})
+	}, nil
+}
+
+// proxiedTab opens a tab like tab for an extractor whose browsers go
+// through proxyServer, failing with ErrPoolProxyMismatch unless the pool's
+// browsers use it too
+func (p *BrowserPool) proxiedTab(ctx context.Context, proxyServer string) (tabCtx context.Context, release func(broken bool), err error) {
+	if proxyServer != "" && p.chrome.proxyServer != proxyServer {
+		return nil, nil, ErrPoolProxyMismatch
+	}
+	return p.tab(ctx)
+}
+
+// Close shuts down every browser in the pool; tabs still open fail. It is
+// safe to call more than once.
+func (p *BrowserPool) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.done)
+		for _, b := range p.browsers {
+			b.close()
+		}
+	})
+	return nil
+}
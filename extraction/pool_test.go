@@ -0,0 +1,200 @@
+package extraction
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeLaunches makes every browser in pool "launch" a plain context,
+// counting the launches
+func fakeLaunches(pool *BrowserPool) *int32 {
+	var launches int32
+	for _, b := range pool.browsers {
+		b.launch = func() (context.Context, context.CancelFunc, error) {
+			atomic.AddInt32(&launches, 1)
+			ctx, cancel := context.WithCancel(context.Background())
+			return ctx, cancel, nil
+		}
+	}
+	return &launches
+}
+
+func TestBrowserPool_ReusesBrowsers(t *testing.T) {
+	pool := NewBrowserPool(2)
+	defer pool.Close()
+	launches := fakeLaunches(pool)
+
+	var inFlight, maxInFlight int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, release, err := pool.tab(context.Background())
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+			release(false)
+		}()
+	}
+	wg.Wait()
+
+	if *launches != 2 {
+		t.Errorf("expected 10 tabs to share 2 browser launches, got %d", *launches)
+	}
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 tabs at once, got %d", maxInFlight)
+	}
+}
+
+func TestBrowserPool_RelaunchesBrokenBrowser(t *testing.T) {
+	pool := NewBrowserPool(1)
+	defer pool.Close()
+	launches := fakeLaunches(pool)
+
+	_, release, err := pool.tab(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release(true)
+
+	_, release, err = pool.tab(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release(false)
+
+	if *launches != 2 {
+		t.Errorf("expected the crashed browser to be relaunched, got %d launches", *launches)
+	}
+}
+
+func TestBrowserPool_WaitAndClose(t *testing.T) {
+	pool := NewBrowserPool(1)
+	fakeLaunches(pool)
+
+	_, release, err := pool.tab(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The only browser is busy, so a second tab waits until ctx is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := pool.tab(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected to give up waiting with the context, got %v", err)
+	}
+
+	release(false)
+	pool.Close()
+	pool.Close()
+
+	if _, _, err := pool.tab(context.Background()); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("expected ErrPoolClosed after Close, got %v", err)
+	}
+	if pool.browsers[0].ready() {
+		t.Error("expected Close to shut the browser down")
+	}
+}
+
+func TestHybridExtractor_UsesBrowserPool(t *testing.T) {
+	pool := NewBrowserPool(1)
+	defer pool.Close()
+	launches := fakeLaunches(pool)
+
+	extractor := NewHybridExtractor(WithBrowserPool(pool))
+	_, release, err := extractor.newTab(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release(false)
+
+	if *launches != 1 {
+		t.Errorf("expected the tab to come from the pool, got %d launches", *launches)
+	}
+	if extractor.browser.ready() {
+		t.Error("expected the extractor's own browser to stay unused")
+	}
+}
+
+func TestWithProxyServer_Invalid(t *testing.T) {
+	hybrid := NewHybridExtractor(WithProxyServer("ftp://proxy:21"))
+	if _, err := hybrid.ExtractContent(context.Background(), "https://example.com"); err == nil {
+		t.Error("expected an invalid proxy to fail hybrid extraction")
+	}
+
+	chrome := NewChromedpExtractor(WithChromedpProxyServer("socks5://"))
+	if _, err := chrome.ExtractContent(context.Background(), "https://example.com"); err == nil {
+		t.Error("expected an invalid proxy to fail chromedp extraction")
+	}
+}
+
+func TestBrowserPool_Proxy(t *testing.T) {
+	pool := NewBrowserPool(1, WithPoolProxyServer("socks5://127.0.0.1:1080"))
+	defer pool.Close()
+	fakeLaunches(pool)
+
+	same := NewHybridExtractor(WithBrowserPool(pool), WithProxyServer("socks5://127.0.0.1:1080"))
+	_, release, err := same.newTab(context.Background())
+	if err != nil {
+		t.Fatalf("expected a tab from a pool behind the same proxy, got %v", err)
+	}
+	release(false)
+
+	// A pool behind another proxy, or none, would bypass the extractor's
+	direct := NewBrowserPool(1)
+	defer direct.Close()
+	for _, p := range []*BrowserPool{direct, NewBrowserPool(1, WithPoolProxyServer("http://other:8080"))} {
+		if _, _, err := NewChromedpExtractor(WithChromedpPool(p), WithChromedpProxyServer("socks5://127.0.0.1:1080")).newTab(context.Background()); !errors.Is(err, ErrPoolProxyMismatch) {
+			t.Errorf("expected ErrPoolProxyMismatch, got %v", err)
+		}
+	}
+
+	invalid := NewBrowserPool(1, WithPoolProxyServer("ftp://proxy:21"))
+	defer invalid.Close()
+	if _, _, err := NewHybridExtractor(WithBrowserPool(invalid)).newTab(context.Background()); err == nil {
+		t.Error("expected an invalid pool proxy to fail every tab")
+	}
+}
+
+func TestWithRemoteChrome(t *testing.T) {
+	t.Setenv(RemoteChromeEnv, "ws://chrome:9222")
+	if e := NewHybridExtractor(); e.chrome.remoteURL != "ws://chrome:9222" {
+		t.Errorf("expected the remote URL from the environment, got %q", e.chrome.remoteURL)
+	}
+	if e := NewChromedpExtractor(WithChromedpRemoteChrome("ws://other:9222")); e.chrome.remoteURL != "ws://other:9222" {
+		t.Errorf("expected the option to override the environment, got %q", e.chrome.remoteURL)
+	}
+
+	// Nothing listens on a closed port, so dialing it must fail rather
+	// than fall back to launching a local browser
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	e := NewHybridExtractor(WithRemoteChrome("ws://" + addr))
+	defer e.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.Warmup(ctx); err == nil {
+		t.Error("expected connecting to an unreachable remote Chrome to fail")
+	}
+}
@@ -0,0 +1,196 @@
+package extraction
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// readableMinText is the least text, in characters, the best container must
+// hold for readableContent to pick it
+const readableMinText = 250
+
+// readableMinParagraph is the shortest paragraph that counts towards its
+// container's score
+const readableMinParagraph = 25
+
+var (
+	// unlikelyCandidate matches the class and id of page furniture that is
+	// dropped before scoring, unless maybeCandidate matches too
+	unlikelyCandidate = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cookie|disqus|footer|header|menu|modal|nav|popup|related|remark|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|widget`)
+	maybeCandidate    = regexp.MustCompile(`(?i)article|body|column|content|main|post|shadow|story|text`)
+
+	// positiveClass and negativeClass adjust a container's score by its
+	// class and id
+	positiveClass = regexp.MustCompile(`(?i)article|body|content|entry|hentry|main|page|post|story|text`)
+	negativeClass = regexp.MustCompile(`(?i)ad-|advert|comment|footer|footnote|masthead|media|meta|promo|related|scroll|share|shopping|sidebar|sponsor|tags|tool|widget`)
+)
+
+// readableContent scores the document's elements the way Readability does,
+// by the paragraphs they hold, their tag, class and link density, and
+// returns the best content container together with the siblings that look
+// like part of the same article. It returns ok=false when that holds too
+// little text. The document is modified: boilerplate elements are removed
+// and merged siblings are moved into a new container.
+func readableContent(doc *goquery.Document) (*goquery.Selection, bool) {
+	doc.Find("script, style, noscript, iframe, nav, footer, aside").Remove()
+	doc.Find("*").Each(func(_ int, s *goquery.Selection) {
+		if name := goquery.NodeName(s); name == "html" || name == "body" || name == "article" || name == "main" {
+			return
+		}
+		class, _ := s.Attr("class")
+		id, _ := s.Attr("id")
+		if match := class + " " + id; unlikelyCandidate.MatchString(match) && !maybeCandidate.MatchString(match) {
+			s.Remove()
+		}
+	})
+
+	scores := map[*html.Node]float64{}
+	var candidates []*html.Node
+	addScore := func(n *html.Node, score float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+
+	doc.Find("p, pre, td, blockquote").Each(func(_ int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		length := utf8.RuneCountInString(text)
+		if length < readableMinParagraph {
+			return
+		}
+
+		// One point for the paragraph, one per comma and one per 100
+		// characters, up to three
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(length/100), 3)
+		parent := s.Nodes[0].Parent
+		addScore(parent, score)
+		if parent != nil {
+			addScore(parent.Parent, score/2)
+		}
+	})
+
+	// Scale each candidate's score by how little of its text is links
+	for _, n := range candidates {
+		scores[n] *= 1 - linkDensity(goquery.NewDocumentFromNode(n).Selection)
+	}
+
+	var best *html.Node
+	for _, n := range candidates {
+		if best == nil || scores[n] > scores[best] {
+			best = n
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+
+	content := mergeSiblings(best, scores)
+	if utf8.RuneCountInString(strings.TrimSpace(content.Text())) < readableMinText {
+		return nil, false
+	}
+	return content, true
+}
+
+// mergeSiblings returns best together with the siblings that look like part
+// of the same article: candidates scoring at least a fifth of best, and
+// paragraphs of prose with few links. Articles split over several
+// containers are kept whole this way.
+func mergeSiblings(best *html.Node, scores map[*html.Node]float64) *goquery.Selection {
+	if best.Parent == nil {
+		return goquery.NewDocumentFromNode(best).Selection
+	}
+
+	threshold := math.Max(10, scores[best]*0.2)
+	var keep []*html.Node
+	for c := best.Parent.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if c == best {
+			keep = append(keep, c)
+			continue
+		}
+		if score, ok := scores[c]; ok && score >= threshold {
+			keep = append(keep, c)
+			continue
+		}
+		if c.Data == "p" && proseParagraph(goquery.NewDocumentFromNode(c).Selection) {
+			keep = append(keep, c)
+		}
+	}
+	if len(keep) == 1 {
+		return goquery.NewDocumentFromNode(best).Selection
+	}
+
+	merged := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range keep {
+		n.Parent.RemoveChild(n)
+		merged.AppendChild(n)
+	}
+	return goquery.NewDocumentFromNode(merged).Selection
+}
+
+// proseParagraph reports whether a sibling paragraph reads like article
+// text: long with few links, or a short link-free sentence
+func proseParagraph(p *goquery.Selection) bool {
+	text := strings.TrimSpace(p.Text())
+	length := utf8.RuneCountInString(text)
+	density := linkDensity(p)
+	if length > 80 {
+		return density < 0.25
+	}
+	return length > 0 && density == 0 && strings.HasSuffix(text, ".")
+}
+
+// initialScore is a container's score before its paragraphs are counted,
+// from its tag and its class and id
+func initialScore(n *html.Node) float64 {
+	var score float64
+	switch n.Data {
+	case "div", "article", "main":
+		score = 5
+	case "pre", "td", "blockquote":
+		score = 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score = -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score = -5
+	}
+
+	for _, attr := range n.Attr {
+		if attr.Key != "class" && attr.Key != "id" {
+			continue
+		}
+		if negativeClass.MatchString(attr.Val) {
+			score -= 25
+		}
+		if positiveClass.MatchString(attr.Val) {
+			score += 25
+		}
+	}
+	return score
+}
+
+// linkDensity is the share of s's text that sits inside links
+func linkDensity(s *goquery.Selection) float64 {
+	length := utf8.RuneCountInString(strings.TrimSpace(s.Text()))
+	if length == 0 {
+		return 0
+	}
+	linkLength := 0
+	s.Find("a").Each(func(_ int, a *goquery.Selection) {
+		linkLength += utf8.RuneCountInString(strings.TrimSpace(a.Text()))
+	})
+	return float64(linkLength) / float64(length)
+}
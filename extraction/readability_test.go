@@ -0,0 +1,132 @@
+package extraction
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// blogPage is a news page with no <article> or known content class, whose
+// body sits among navigation, a link-heavy sidebar and a footer
+var blogPage = `<html><head><title>Rivers in Spring</title></head><body>
+	<div class="top"><a href="/">Home</a> <a href="/news">News</a> <a href="/sport">Sport</a></div>
+	<div class="wrap">
+		<div class="x1">
+			<h1>Rivers in Spring</h1>
+			<p>Snowmelt raises the rivers every spring, and towns along the valley prepare for weeks in advance, clearing drains and stacking sandbags.</p>
+			<p>Engineers, farmers and volunteers watch the gauges closely, because a warm week in the mountains can double the flow within days.</p>
+			<p>This year the forecast is mild, but officials say the preparations will go ahead as usual, just in case the weather turns.</p>
+		</div>
+		<div class="x2">
+			<p><a href="/a">Ten rivers you should see before you die, ranked</a></p>
+			<p><a href="/b">Why the valley towns keep flooding, explained</a></p>
+			<p><a href="/c">Subscribe to our newsletter for weekly updates</a></p>
+		</div>
+	</div>
+	<div id="site-footer"><p>Copyright the River Times, all rights reserved, since 1902.</p></div>
+</body></html>`
+
+func TestReadableContent_PicksBodyWithoutArticleTag(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(blogPage))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, ok := readableContent(doc)
+	if !ok {
+		t.Fatal("expected a content container")
+	}
+	if class, _ := content.Attr("class"); class != "x1" {
+		t.Errorf("expected the article container, got class %q", class)
+	}
+	text := content.Text()
+	if !strings.Contains(text, "Snowmelt raises the rivers") {
+		t.Errorf("expected the article body, got %q", text)
+	}
+	for _, boilerplate := range []string{"Ten rivers", "Copyright", "Sport"} {
+		if strings.Contains(text, boilerplate) {
+			t.Errorf("expected %q to be left out, got %q", boilerplate, text)
+		}
+	}
+}
+
+func TestReadableContent_TooLittleText(t *testing.T) {
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<html><body><div><p>Just one short paragraph of text here.</p></div></body></html>`))
+	if _, ok := readableContent(doc); ok {
+		t.Error("expected no container for a page with too little text")
+	}
+}
+
+// splitPage is an article split over two sibling containers, with its
+// heading in a <header> and a closing paragraph after them
+var splitPage = `<html><head><title>Harvest Report</title></head><body>
+	<div class="page">
+		<div class="part-one">
+			<header><h1>Harvest Report</h1></header>
+			<p>The wheat harvest started early this year, and farmers across the plain say the yield looks better than in any season since records began.</p>
+			<p>Dry weather in August let the combines run late into the night, although prices at the grain exchange have been slow to follow.</p>
+		</div>
+		<div class="part-two">
+			<p>Barley growers were less lucky, with hail in July flattening whole fields in the north, and insurers expect a busy autumn of claims.</p>
+			<p>Storage is the next worry, because the silos at the <a href="/depot">river depot</a> are still half full from last year's surplus crop.</p>
+		</div>
+		<p>The cooperative will publish its final figures in November.</p>
+		<div class="sidebar brand-links"><a href="/shop">Shop</a> <a href="/brands">Brands</a></div>
+	</div>
+</body></html>`
+
+func TestReadableContent_MergesSiblings(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(splitPage))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, ok := readableContent(doc)
+	if !ok {
+		t.Fatal("expected a content container")
+	}
+	text := content.Text()
+	for _, want := range []string{"Harvest Report", "wheat harvest", "Barley growers", "final figures in November"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in the merged content, got %q", want, text)
+		}
+	}
+	if strings.Contains(text, "Brands") {
+		t.Errorf("expected the sidebar to be left out, got %q", text)
+	}
+}
+
+func TestMaybeCandidate_WholeWords(t *testing.T) {
+	for _, class := range []string{"brand", "landing-banner", "sidebar-stand"} {
+		if maybeCandidate.MatchString(class) {
+			t.Errorf("expected %q not to rescue an unlikely candidate", class)
+		}
+	}
+}
+
+func TestScoredContent_ResolvesLinks(t *testing.T) {
+	content, ok := scoredContent("https://example.com/farming/harvest", splitPage, "")
+	if !ok {
+		t.Fatal("expected scored content")
+	}
+	if !strings.HasPrefix(content, "# Harvest Report\n\n") {
+		t.Errorf("expected the page title first, got %q", content)
+	}
+	if !strings.Contains(content, "(https://example.com/depot)") {
+		t.Errorf("expected relative links to be resolved, got %q", content)
+	}
+}
+
+func TestContentFromHTML_ArticleBody(t *testing.T) {
+	content, err := contentFromHTML("https://example.com/rivers", blogPage, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(content, "# Rivers in Spring\n\n") {
+		t.Errorf("expected the page title first, got %q", content)
+	}
+	if !strings.Contains(content, "Snowmelt raises the rivers") || strings.Contains(content, "newsletter") {
+		t.Errorf("expected only the article body, got %q", content)
+	}
+}
@@ -0,0 +1,84 @@
+package extraction
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fragmentWords is the most words a line can have and still be treated as
+// a fragment of a longer sentence
+const fragmentWords = 3
+
+// ReflowText joins lines that innerText split mid-sentence, as happens on
+// heavily styled pages where each word sits in its own element. Blank lines
+// are kept as paragraph breaks, and headings and list items stay on their
+// own lines.
+func ReflowText(text string) string {
+	lines := strings.Split(text, "\n")
+	var out []string
+	var current, previous string
+
+	flush := func() {
+		if current != "" {
+			out = append(out, current)
+			current = ""
+		}
+	}
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			flush()
+			out = append(out, "")
+		case current == "":
+			current = line
+		case shouldJoin(current, previous, line):
+			current += " " + line
+		default:
+			flush()
+			current = line
+		}
+		previous = line
+	}
+	flush()
+
+	return strings.Join(out, "\n")
+}
+
+// shouldJoin reports whether next continues the paragraph being built in
+// current, whose last source line was previous
+func shouldJoin(current, previous, next string) bool {
+	if endsSentence(current) || isBlockStart(current) || isBlockStart(next) {
+		return false
+	}
+
+	first := []rune(next)[0]
+	if unicode.IsLower(first) || unicode.IsPunct(first) {
+		return true
+	}
+
+	// Two short fragments in a row are pieces of one line
+	return isFragment(previous) && isFragment(next)
+}
+
+func endsSentence(line string) bool {
+	return strings.HasSuffix(line, ".") || strings.HasSuffix(line, "!") ||
+		strings.HasSuffix(line, "?") || strings.HasSuffix(line, ":") ||
+		strings.HasSuffix(line, ";")
+}
+
+// isBlockStart reports whether line is a Markdown heading or list item
+func isBlockStart(line string) bool {
+	if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "- ") ||
+		strings.HasPrefix(line, "* ") || strings.HasPrefix(line, "> ") {
+		return true
+	}
+
+	digits := strings.TrimLeftFunc(line, unicode.IsDigit)
+	return len(digits) < len(line) && (strings.HasPrefix(digits, ". ") || strings.HasPrefix(digits, ") "))
+}
+
+func isFragment(line string) bool {
+	return len(strings.Fields(line)) <= fragmentWords
+}
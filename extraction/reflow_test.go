@@ -0,0 +1,59 @@
+package extraction
+
+import "testing"
+
+func TestReflowText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "joins one-word lines",
+			input:    "The\nquick\nbrown fox\njumps over the lazy dog.",
+			expected: "The quick brown fox jumps over the lazy dog.",
+		},
+		{
+			name:     "keeps paragraph breaks",
+			input:    "First\nparagraph here.\n\nSecond\nparagraph.",
+			expected: "First paragraph here.\n\nSecond paragraph.",
+		},
+		{
+			name:     "joins lowercase continuation",
+			input:    "Prices start at $5 per month for the basic\nplan and go up from there.",
+			expected: "Prices start at $5 per month for the basic plan and go up from there.",
+		},
+		{
+			name:     "keeps heading before long sentence",
+			input:    "Introduction\nThis section explains how the tool works in detail.",
+			expected: "Introduction\nThis section explains how the tool works in detail.",
+		},
+		{
+			name:     "keeps sentences on separate lines",
+			input:    "First sentence.\nSecond sentence.",
+			expected: "First sentence.\nSecond sentence.",
+		},
+		{
+			name:     "keeps list items and headings",
+			input:    "# Title\n- one\n- two\n1. first\n2. second",
+			expected: "# Title\n- one\n- two\n1. first\n2. second",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReflowText(tt.input); got != tt.expected {
+				t.Errorf("ReflowText() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWithReflow(t *testing.T) {
+	if NewChromedpExtractor().reflow {
+		t.Error("expected reflow to be off by default")
+	}
+	if !NewChromedpExtractor(WithReflow(true)).reflow {
+		t.Error("expected WithReflow(true) to enable reflow")
+	}
+}
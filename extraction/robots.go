@@ -0,0 +1,215 @@
+package extraction
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// robotsUserAgent is the name the crawler looks for in robots.txt groups.
+// Rules for "*" apply when no group names it.
+const robotsUserAgent = "mcp-websearch-server"
+
+// robotsSkipped is the SubPageResult error for pages robots.txt disallows
+const robotsSkipped = "skipped: disallowed by robots.txt"
+
+// maxRobotsSize caps how much of a robots.txt file is read
+const maxRobotsSize = 512 * 1024
+
+// robotsRule is one Allow or Disallow line
+type robotsRule struct {
+	allow   bool
+	length  int
+	pattern *regexp.Regexp
+}
+
+// robotsRules are the rules that apply to our user-agent on one host
+type robotsRules []robotsRule
+
+// allowed reports whether path may be crawled. The longest matching rule
+// wins, and Allow wins a tie; a path no rule matches is allowed.
+func (rules robotsRules) allowed(path string) bool {
+	best := -1
+	allow := true
+	for _, rule := range rules {
+		if rule.pattern.MatchString(path) && (rule.length > best || (rule.length == best && rule.allow)) {
+			best = rule.length
+			allow = rule.allow
+		}
+	}
+	return allow
+}
+
+// parseRobots returns the rules in a robots.txt file for agent, falling
+// back to the "*" group when no group names the agent
+func parseRobots(r io.Reader, agent string) robotsRules {
+	agent = strings.ToLower(agent)
+
+	var specific, wildcard robotsRules
+	var matchesAgent, matchesWildcard, inAgents bool
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		field = strings.ToLower(strings.TrimSpace(field))
+		value = strings.TrimSpace(value)
+
+		switch field {
+		case "user-agent":
+			// Consecutive User-agent lines share one group
+			if !inAgents {
+				matchesAgent, matchesWildcard = false, false
+			}
+			inAgents = true
+			name := strings.ToLower(value)
+			if name == "*" {
+				matchesWildcard = true
+			} else if name != "" && strings.Contains(agent, name) {
+				matchesAgent = true
+			}
+		case "allow", "disallow":
+			inAgents = false
+			if value == "" {
+				// An empty Disallow allows everything
+				continue
+			}
+			rule := robotsRule{allow: field == "allow", length: len(value), pattern: robotsPattern(value)}
+			if matchesAgent {
+				specific = append(specific, rule)
+			}
+			if matchesWildcard {
+				wildcard = append(wildcard, rule)
+			}
+		default:
+			inAgents = false
+		}
+	}
+
+	if specific != nil {
+		return specific
+	}
+	return wildcard
+}
+
+// robotsPattern compiles a robots.txt path, where * matches any characters
+// and a trailing $ anchors the end
+func robotsPattern(path string) *regexp.Regexp {
+	anchored := strings.HasSuffix(path, "$")
+	path = strings.TrimSuffix(path, "$")
+
+	parts := strings.Split(path, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// robotsTimeout bounds a robots.txt fetch, independently of the crawl that
+// asked for it
+const robotsTimeout = 10 * time.Second
+
+// robotsCache fetches each host's robots.txt once. It is safe for
+// concurrent use.
+type robotsCache struct {
+	client *http.Client
+	mu     sync.Mutex
+	hosts  map[string]*robotsHost
+}
+
+// robotsHost holds one host's rules. Fetches that fail transiently are not
+// recorded, so the next page on the host tries again.
+type robotsHost struct {
+	mu      sync.Mutex
+	fetched bool
+	rules   robotsRules
+}
+
+func newRobotsCache() *robotsCache {
+	return &robotsCache{
+		client: &http.Client{Timeout: robotsTimeout},
+		hosts:  make(map[string]*robotsHost),
+	}
+}
+
+// allowed reports whether robots.txt lets us crawl rawURL. As RFC 9309
+// asks, a missing robots.txt (a 4xx) allows everything and an unreachable
+// one (a network error or a 5xx) disallows everything.
+func (c *robotsCache) allowed(ctx context.Context, rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return true
+	}
+
+	origin := u.Scheme + "://" + u.Host
+	c.mu.Lock()
+	host, ok := c.hosts[origin]
+	if !ok {
+		host = &robotsHost{}
+		c.hosts[origin] = host
+	}
+	c.mu.Unlock()
+
+	host.mu.Lock()
+	if !host.fetched {
+		rules, ok := c.fetch(ctx, origin+"/robots.txt")
+		if !ok {
+			host.mu.Unlock()
+			return false
+		}
+		host.rules, host.fetched = rules, true
+	}
+	rules := host.rules
+	host.mu.Unlock()
+
+	return rules.allowed(u.EscapedPath() + querySuffix(u))
+}
+
+func querySuffix(u *url.URL) string {
+	if u.RawQuery == "" {
+		return ""
+	}
+	return "?" + u.RawQuery
+}
+
+// fetch downloads and parses a robots.txt file. It returns no rules when the
+// file is missing, and ok=false when it is unreachable, which the caller
+// treats as a transient complete disallow. The fetch is bounded by its own
+// timeout so a caller's cancelled context doesn't decide the outcome.
+func (c *robotsCache) fetch(ctx context.Context, robotsURL string) (robotsRules, bool) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), robotsTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil, true
+	}
+	req.Header.Set("User-Agent", robotsUserAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, false
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return nil, false
+	case resp.StatusCode != http.StatusOK:
+		return nil, true
+	}
+	return parseRobots(io.LimitReader(resp.Body, maxRobotsSize), robotsUserAgent), true
+}
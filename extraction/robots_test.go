@@ -0,0 +1,135 @@
+package extraction
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParseRobots(t *testing.T) {
+	robots := `
+User-agent: *
+Disallow: /private/
+Allow: /private/public-page
+Disallow: /*.json$
+
+User-agent: OtherBot
+Disallow: /
+`
+	rules := parseRobots(strings.NewReader(robots), robotsUserAgent)
+
+	tests := []struct {
+		path    string
+		allowed bool
+	}{
+		{"/", true},
+		{"/articles/1", true},
+		{"/private/secret", false},
+		{"/private/public-page", true},
+		{"/data/feed.json", false},
+		{"/data/feed.json?v=2", true},
+	}
+	for _, tt := range tests {
+		if got := rules.allowed(tt.path); got != tt.allowed {
+			t.Errorf("allowed(%q) = %v, want %v", tt.path, got, tt.allowed)
+		}
+	}
+
+	// A group naming our agent replaces the wildcard group
+	specific := parseRobots(strings.NewReader("User-agent: *\nDisallow: /\n\nUser-agent: mcp-websearch-server\nDisallow: /tmp\n"), robotsUserAgent)
+	if !specific.allowed("/articles") || specific.allowed("/tmp/x") {
+		t.Error("expected the rules for our user-agent to apply")
+	}
+}
+
+func TestDeepReader_RespectsRobots(t *testing.T) {
+	var robotsFetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			atomic.AddInt32(&robotsFetches, 1)
+			w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	reader := NewDeepReader(WithDepth(2))
+	reader.fetchPage = func(ctx context.Context, url string) (*fetchedPage, error) {
+		page := &fetchedPage{title: url, content: "content"}
+		if url == server.URL+"/" {
+			page.links = []LinkInfo{
+				{URL: server.URL + "/private/report", Text: "Quarterly private report"},
+				{URL: server.URL + "/articles/one", Text: "First public article"},
+			}
+		}
+		return page, nil
+	}
+
+	result, err := reader.DeepRead(context.Background(), server.URL+"/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.SubPages) != 2 {
+		t.Fatalf("expected both links in SubPages, got %d", len(result.SubPages))
+	}
+	for _, page := range result.SubPages {
+		private := strings.Contains(page.URL, "/private/")
+		if private && page.Error != robotsSkipped {
+			t.Errorf("expected %s to be skipped, got error %q", page.URL, page.Error)
+		}
+		if !private && page.Error != "" {
+			t.Errorf("expected %s to be crawled, got error %q", page.URL, page.Error)
+		}
+	}
+
+	if !strings.Contains(result.ToMarkdown(), robotsSkipped) {
+		t.Error("expected the markdown to show the skipped page")
+	}
+	if n := atomic.LoadInt32(&robotsFetches); n != 1 {
+		t.Errorf("expected robots.txt to be fetched once per host, got %d", n)
+	}
+}
+
+func TestRobotsCache_Unreachable(t *testing.T) {
+	var status, fetches int32
+	atomic.StoreInt32(&status, http.StatusServiceUnavailable)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+	}))
+	defer server.Close()
+
+	cache := newRobotsCache()
+
+	// A 5xx disallows the host without being remembered
+	if cache.allowed(context.Background(), server.URL+"/page") {
+		t.Error("expected a 5xx robots.txt to disallow the host")
+	}
+	atomic.StoreInt32(&status, http.StatusNotFound)
+	if !cache.allowed(context.Background(), server.URL+"/page") {
+		t.Error("expected a missing robots.txt to allow the host")
+	}
+	if n := atomic.LoadInt32(&fetches); n != 2 {
+		t.Errorf("expected the failed fetch to be retried, got %d fetches", n)
+	}
+
+	// A cancelled caller doesn't cancel the fetch
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+	}))
+	defer other.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if cache.allowed(ctx, other.URL+"/private/page") {
+		t.Error("expected robots.txt to be honoured for a cancelled caller")
+	}
+	if !cache.allowed(context.Background(), other.URL+"/public") {
+		t.Error("expected the public page to be allowed")
+	}
+}
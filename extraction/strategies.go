@@ -0,0 +1,100 @@
+package extraction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// defaultMinContentLength is the least content a strategy must return to be
+// accepted when no minimum is given
+const defaultMinContentLength = 200
+
+// ExtractionStrategy is one way of getting a page's text
+type ExtractionStrategy struct {
+	Name    string
+	Extract func(ctx context.Context, url string) (string, error)
+}
+
+// MainContentStrategy extracts the page's main content element with chromedp
+func MainContentStrategy(e *ChromedpExtractor) ExtractionStrategy {
+	return ExtractionStrategy{Name: "main-content", Extract: e.ExtractContent}
+}
+
+// BodyTextStrategy extracts the whole body with boilerplate removed
+func BodyTextStrategy(e *ChromedpExtractor) ExtractionStrategy {
+	return ExtractionStrategy{Name: "body-text", Extract: e.ExtractBodyText}
+}
+
+// ReadabilityStrategy extracts the article with Readability
+func ReadabilityStrategy(e *HybridExtractor) ExtractionStrategy {
+	return ExtractionStrategy{Name: "readability", Extract: e.ExtractContent}
+}
+
+// DefaultExtractionStrategies returns the main-content, body-text and
+// Readability strategies, in that order
+func DefaultExtractionStrategies() []ExtractionStrategy {
+	chromedpExtractor := NewChromedpExtractor()
+	return []ExtractionStrategy{
+		MainContentStrategy(chromedpExtractor),
+		BodyTextStrategy(chromedpExtractor),
+		ReadabilityStrategy(NewHybridExtractor()),
+	}
+}
+
+// StrategyExtractor tries extraction strategies in order, moving on to the
+// next when one fails or returns too little content
+type StrategyExtractor struct {
+	strategies []ExtractionStrategy
+	minLength  int
+}
+
+// NewStrategyExtractor creates an extractor that tries strategies in order
+// until one returns at least minLength characters. Zero or a negative
+// minLength uses a default of 200.
+func NewStrategyExtractor(minLength int, strategies ...ExtractionStrategy) *StrategyExtractor {
+	if minLength <= 0 {
+		minLength = defaultMinContentLength
+	}
+	return &StrategyExtractor{
+		strategies: strategies,
+		minLength:  minLength,
+	}
+}
+
+// ExtractContent returns the first sufficient result. If every strategy
+// falls short, the longest non-empty content is returned, and an error only
+// when none produced anything.
+func (s *StrategyExtractor) ExtractContent(ctx context.Context, url string) (string, error) {
+	var best string
+	var errs []error
+
+	for _, strategy := range s.strategies {
+		if err := ctx.Err(); err != nil {
+			return best, err
+		}
+
+		content, err := strategy.Extract(ctx, url)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", strategy.Name, err))
+			continue
+		}
+
+		content = strings.TrimSpace(content)
+		if len(content) >= s.minLength {
+			return content, nil
+		}
+		if len(content) > len(best) {
+			best = content
+		}
+	}
+
+	if best != "" {
+		return best, nil
+	}
+	if len(errs) == 0 {
+		return "", fmt.Errorf("no content extracted from %s", url)
+	}
+	return "", fmt.Errorf("all extraction strategies failed for %s: %w", url, errors.Join(errs...))
+}
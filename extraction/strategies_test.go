@@ -0,0 +1,65 @@
+package extraction
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStrategyExtractor_FallsThroughToThirdStrategy(t *testing.T) {
+	var tried []string
+	strategy := func(name, content string, err error) ExtractionStrategy {
+		return ExtractionStrategy{
+			Name: name,
+			Extract: func(ctx context.Context, url string) (string, error) {
+				tried = append(tried, name)
+				return content, err
+			},
+		}
+	}
+
+	full := strings.Repeat("Readable article text. ", 10)
+	extractor := NewStrategyExtractor(50,
+		strategy("main-content", "", nil),
+		strategy("body-text", "", errors.New("navigation timeout")),
+		strategy("readability", full, nil),
+		strategy("never", "unused", nil),
+	)
+
+	content, err := extractor.ExtractContent(context.Background(), "https://example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if content != strings.TrimSpace(full) {
+		t.Errorf("expected content from the third strategy, got %q", content)
+	}
+	if strings.Join(tried, ",") != "main-content,body-text,readability" {
+		t.Errorf("unexpected strategy order: %v", tried)
+	}
+}
+
+func TestStrategyExtractor_ShortContent(t *testing.T) {
+	short := func(content string) ExtractionStrategy {
+		return ExtractionStrategy{Name: "short", Extract: func(ctx context.Context, url string) (string, error) {
+			return content, nil
+		}}
+	}
+
+	// When nothing reaches the minimum, the longest content wins
+	extractor := NewStrategyExtractor(100, short("tiny"), short("a bit longer"))
+	content, err := extractor.ExtractContent(context.Background(), "https://example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content != "a bit longer" {
+		t.Errorf("expected the longest content, got %q", content)
+	}
+
+	// When nothing produces content at all, it's an error
+	extractor = NewStrategyExtractor(100, short(""), short("  "))
+	if _, err := extractor.ExtractContent(context.Background(), "https://example.com"); err == nil {
+		t.Error("expected an error when no strategy yields content")
+	}
+}
@@ -0,0 +1,125 @@
+package extraction
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// sentenceEnd splits text after sentence-ending punctuation
+var sentenceEnd = regexp.MustCompile(`[.!?]["')\]]*\s+`)
+
+// minSummaryWords skips headings, captions and other fragments too short
+// to stand alone as a summary sentence
+const minSummaryWords = 5
+
+// summaryStopWords are too common to mark a sentence as on-topic
+var summaryStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
+	"you": true, "all": true, "any": true, "can": true, "has": true, "had": true,
+	"was": true, "were": true, "with": true, "this": true, "that": true, "from": true,
+	"they": true, "their": true, "there": true, "have": true, "will": true, "which": true,
+	"when": true, "what": true, "your": true, "into": true, "than": true, "then": true,
+	"also": true, "been": true, "its": true, "our": true, "more": true, "most": true,
+	"other": true, "some": true, "such": true, "these": true, "those": true, "about": true,
+}
+
+// ExtractiveSummary picks the maxSentences sentences of content with the
+// highest keyword density and returns them in their original order. A
+// keyword is any word of three or more letters that isn't a stop word,
+// weighted by how often it occurs in content; words of query count
+// double. No external model is involved.
+func ExtractiveSummary(content, query string, maxSentences int) string {
+	if maxSentences <= 0 {
+		return ""
+	}
+
+	sentences := splitSentences(content)
+	if len(sentences) == 0 {
+		return ""
+	}
+
+	freq := make(map[string]float64)
+	for _, s := range sentences {
+		for _, w := range keywords(s) {
+			freq[w]++
+		}
+	}
+	for _, w := range keywords(query) {
+		if freq[w] > 0 {
+			freq[w] *= 2
+		}
+	}
+
+	type scored struct {
+		index int
+		score float64
+	}
+	var candidates []scored
+	for i, s := range sentences {
+		words := strings.Fields(s)
+		if len(words) < minSummaryWords {
+			continue
+		}
+		score := 0.0
+		for _, w := range keywords(s) {
+			score += freq[w]
+		}
+		candidates = append(candidates, scored{i, score / float64(len(words))})
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].score > candidates[b].score
+	})
+	if len(candidates) > maxSentences {
+		candidates = candidates[:maxSentences]
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		return candidates[a].index < candidates[b].index
+	})
+
+	picked := make([]string, len(candidates))
+	for i, c := range candidates {
+		picked[i] = sentences[c.index]
+	}
+	return strings.Join(picked, " ")
+}
+
+// splitSentences breaks content into sentences, treating each line as a
+// separate block and dropping Markdown markers
+func splitSentences(content string) []string {
+	var sentences []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimLeft(strings.TrimSpace(line), "#>*- ")
+		if line == "" {
+			continue
+		}
+
+		start := 0
+		for _, loc := range sentenceEnd.FindAllStringIndex(line, -1) {
+			if s := strings.TrimSpace(line[start:loc[1]]); s != "" {
+				sentences = append(sentences, s)
+			}
+			start = loc[1]
+		}
+		if s := strings.TrimSpace(line[start:]); s != "" {
+			sentences = append(sentences, s)
+		}
+	}
+	return sentences
+}
+
+// keywords returns the lowercased words of text that count towards
+// keyword density
+func keywords(text string) []string {
+	var words []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) >= 3 && !summaryStopWords[w] {
+			words = append(words, w)
+		}
+	}
+	return words
+}
@@ -0,0 +1,37 @@
+package extraction
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractiveSummary(t *testing.T) {
+	content := `# Garbage collection in Go
+
+Go ships with a concurrent garbage collector. The garbage collector runs alongside the program and reclaims memory the program no longer uses.
+
+The weather in Mountain View was pleasant on the day the project was announced. Several people attended the event in person.
+
+Tuning the garbage collector is done with GOGC, which sets how much memory may grow before the next collection. A lower GOGC means more frequent garbage collection and less memory.
+
+Thanks for reading, and see you next time.`
+
+	summary := ExtractiveSummary(content, "garbage collector", 2)
+
+	want := "Go ships with a concurrent garbage collector. A lower GOGC means more frequent garbage collection and less memory."
+	if summary != want {
+		t.Errorf("expected the two most keyword-dense sentences, got %q", summary)
+	}
+	for _, offTopic := range []string{"weather", "Thanks for reading", "# Garbage"} {
+		if strings.Contains(summary, offTopic) {
+			t.Errorf("expected %q to be left out, got %q", offTopic, summary)
+		}
+	}
+	if len(summary) > len(content)/2 {
+		t.Errorf("expected a short summary, got %d of %d characters", len(summary), len(content))
+	}
+
+	if ExtractiveSummary("", "query", 2) != "" {
+		t.Error("expected an empty summary for empty content")
+	}
+}
@@ -0,0 +1,112 @@
+package extraction
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ExtractTablesCSV returns the page's data tables as CSV, one string per
+// table, largest first. Tables with fewer than two rows are treated as
+// layout and skipped.
+func (e *HybridExtractor) ExtractTablesCSV(ctx context.Context, targetURL string) ([]string, error) {
+	rendered, err := e.renderHTML(ctx, targetURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTablesCSV(rendered.html)
+}
+
+// parseTablesCSV converts each data table in an HTML document to CSV
+func parseTablesCSV(htmlContent string) ([]string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	type table struct {
+		csv   string
+		cells int
+	}
+	var tables []table
+
+	doc.Find("table").Each(func(i int, t *goquery.Selection) {
+		// Nested tables are picked up on their own
+		var rows [][]string
+		cells := 0
+		t.Find("tr").Each(func(j int, tr *goquery.Selection) {
+			if tr.Closest("table").Get(0) != t.Get(0) {
+				return
+			}
+			row := tableRow(tr)
+			if len(row) == 0 {
+				return
+			}
+			cells += len(row)
+			rows = append(rows, row)
+		})
+		if len(rows) < 2 {
+			return
+		}
+
+		var b strings.Builder
+		w := csv.NewWriter(&b)
+		if err := w.WriteAll(padRows(rows)); err != nil {
+			return
+		}
+		tables = append(tables, table{csv: b.String(), cells: cells})
+	})
+
+	sort.SliceStable(tables, func(i, j int) bool {
+		return tables[i].cells > tables[j].cells
+	})
+
+	result := make([]string, len(tables))
+	for i, t := range tables {
+		result[i] = t.csv
+	}
+	return result, nil
+}
+
+// tableRow returns the text of a row's cells, repeating cells that span
+// several columns so the columns stay aligned
+func tableRow(tr *goquery.Selection) []string {
+	var row []string
+	tr.ChildrenFiltered("th, td").Each(func(i int, cell *goquery.Selection) {
+		text := strings.Join(strings.Fields(cell.Text()), " ")
+		span := 1
+		if v, ok := cell.Attr("colspan"); ok {
+			if n, err := strconv.Atoi(v); err == nil && n > 1 && n <= 100 {
+				span = n
+			}
+		}
+		for k := 0; k < span; k++ {
+			row = append(row, text)
+		}
+	})
+	return row
+}
+
+// padRows extends short rows with empty cells so every record has the same
+// number of fields
+func padRows(rows [][]string) [][]string {
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	for i, row := range rows {
+		for len(row) < width {
+			row = append(row, "")
+		}
+		rows[i] = row
+	}
+	return rows
+}
@@ -0,0 +1,42 @@
+package extraction
+
+import "testing"
+
+func TestParseTablesCSV(t *testing.T) {
+	html := `<html><body>
+		<table><tr><td>Layout only</td></tr></table>
+		<table>
+			<thead><tr><th>Plan</th><th>Price</th><th>Notes</th></tr></thead>
+			<tbody>
+				<tr><td>Basic</td><td>$5</td><td>Email, chat</td></tr>
+				<tr><td>Pro</td><td>$20</td><td>Says "unlimited"</td></tr>
+				<tr><td>Team</td><td colspan="2">Contact us</td></tr>
+			</tbody>
+		</table>
+		<table>
+			<tr><th>A</th><th>B</th></tr>
+			<tr><td>1</td><td>2</td></tr>
+		</table>
+	</body></html>`
+
+	tables, err := parseTablesCSV(html)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tables) != 2 {
+		t.Fatalf("expected 2 data tables, got %d: %q", len(tables), tables)
+	}
+
+	expected := "Plan,Price,Notes\n" +
+		"Basic,$5,\"Email, chat\"\n" +
+		"Pro,$20,\"Says \"\"unlimited\"\"\"\n" +
+		"Team,Contact us,Contact us\n"
+	if tables[0] != expected {
+		t.Errorf("largest table CSV mismatch:\ngot:\n%s\nwant:\n%s", tables[0], expected)
+	}
+
+	if tables[1] != "A,B\n1,2\n" {
+		t.Errorf("unexpected second table CSV: %q", tables[1])
+	}
+}
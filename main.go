@@ -2,16 +2,25 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/liliang-cn/mcp-websearch-server/extraction"
 	"github.com/liliang-cn/mcp-websearch-server/mcp"
+	"github.com/liliang-cn/mcp-websearch-server/search"
 )
 
 func main() {
 	help := flag.Bool("help", false, "Show help information")
+	warmup := flag.Bool("warmup", false, "Launch the browser at startup so the first browser search is fast")
+	searxng := flag.String("searxng", "", "URL of a SearXNG instance to route searches through")
+	extractConcurrency := flag.Int("extract-concurrency", 0, "Pages to extract content from at once (default 2)")
+	maxBrowserTabs := flag.Int("max-browser-tabs", 0, "Browser tabs open at once across all tools (default unlimited)")
 	flag.Parse()
 
 	if *help {
@@ -19,6 +28,12 @@ func main() {
 		fmt.Println("\nUsage: mcp-websearch-server [options]")
 		fmt.Println("\nOptions:")
 		fmt.Println("  --help    Show this help message")
+		fmt.Println("  --warmup  Launch the browser at startup (default false)")
+		fmt.Println("  --searxng URL of a SearXNG instance to route searches through")
+		fmt.Println("  --extract-concurrency  Pages to extract content from at once (default 2, max 32)")
+		fmt.Println("  --max-browser-tabs  Browser tabs open at once across all tools (default unlimited)")
+		fmt.Println("\nEnvironment:")
+		fmt.Println("  CHROME_REMOTE_URL  DevTools WebSocket URL of a running Chrome to use instead of launching one")
 		fmt.Println("\nDescription:")
 		fmt.Println("  This server provides web search capabilities via the Model Context Protocol (MCP).")
 		fmt.Println("  It runs in stdio mode, reading MCP protocol messages from stdin and writing responses to stdout.")
@@ -26,12 +41,24 @@ func main() {
 		fmt.Println("  - websearch_basic: Basic search returning titles, URLs and snippets from a single engine")
 		fmt.Println("  - websearch_with_content: Search with intelligent page content extraction")
 		fmt.Println("  - websearch_multi_engine: Comprehensive multi-engine search with content extraction")
+		fmt.Println("  - websearch_news: News search sorted by recency, with sources and publication dates")
+		fmt.Println("  - websearch_images: Image search returning image, thumbnail and source URLs")
+		fmt.Println("  - websearch_suggest: Autocomplete suggestions for a partly typed query")
 		fmt.Println("  - websearch_ai_summary: Aggregated content optimized for AI analysis")
 		fmt.Println("  - fetch_page_content: Directly extract content from any URL")
+		fmt.Println("  - websearch_fetch: Readable content of a single URL, optionally capped with max_chars")
+		fmt.Println("  - websearch_screenshot: PNG screenshot of a page, with viewport and wait-for-selector options")
+		fmt.Println("  - websearch_deep_read: A page's content plus summaries of the related pages it links to")
 		fmt.Println("\nSearch Engines:")
 		fmt.Println("  - DuckDuckGo (primary)")
 		fmt.Println("  - Bing (fallback)")
 		fmt.Println("  - Brave (fallback)")
+		fmt.Println("  - Mojeek (fallback)")
+		fmt.Println("  - Startpage (fallback)")
+		fmt.Println("  - Google (fallback)")
+		fmt.Println("  - Yandex (fallback)")
+		fmt.Println("  - Qwant (fallback)")
+		fmt.Println("  - SearXNG (primary when --searxng is set)")
 		fmt.Println("\nIntegration with Claude Desktop:")
 		fmt.Println("  Add to ~/Library/Application Support/Claude/claude_desktop_config.json:")
 		fmt.Println(`  {
@@ -44,14 +71,26 @@ func main() {
 		os.Exit(0)
 	}
 
-	ctx := context.Background()
+	extraction.SetMaxBrowserConcurrency(*maxBrowserTabs)
 
-	server, err := mcp.NewServer()
+	// Cancel on SIGINT/SIGTERM so Run can shut down and release browsers
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server, err := mcp.NewServer(search.WithSearXNG(*searxng), search.WithExtractConcurrency(*extractConcurrency))
 	if err != nil {
 		log.Fatalf("Failed to create MCP server: %v", err)
 	}
 
-	if err := server.Run(ctx); err != nil {
+	if *warmup {
+		go func() {
+			if err := server.Warmup(ctx); err != nil {
+				log.Printf("Browser warmup failed: %v", err)
+			}
+		}()
+	}
+
+	if err := server.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
 		log.Fatalf("Server error: %v", err)
 	}
 }
@@ -2,7 +2,12 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/url"
+	"sync"
+	"time"
 
 	"github.com/liliang-cn/mcp-websearch-server/extraction"
 	"github.com/liliang-cn/mcp-websearch-server/search"
@@ -12,9 +17,35 @@ import (
 type Server struct {
 	mcpServer *mcp.Server
 	searcher  search.MultiEngineSearcher
+	// extractor backs the single-URL fetch tools
+	extractor search.ContentExtractor
+	// newScreenshotter creates the browser for a screenshot tool call
+	newScreenshotter func(opts ...extraction.ChromedpOption) screenshotter
+	// newDeepReader creates the crawler for a deep read tool call
+	newDeepReader func(opts ...extraction.DeepReaderOption) deepReader
+	// lifecycle serializes Warmup with Close, so Close waits for a browser
+	// still being launched and no warmup starts after it
+	lifecycle sync.Mutex
+	closed    bool
 }
 
-func NewServer() (*Server, error) {
+// screenshotter captures a page as PNG
+type screenshotter interface {
+	CaptureScreenshot(ctx context.Context, url string, fullPage bool) ([]byte, error)
+}
+
+// deepReader reads a page and the pages it links to
+type deepReader interface {
+	DeepRead(ctx context.Context, url string) (*extraction.DeepReadResult, error)
+}
+
+// NewServer creates a server backed by a hybrid searcher configured with
+// opts
+func NewServer(opts ...search.SearcherOption) (*Server, error) {
+	return newServer(search.NewHybridSearcher(opts...))
+}
+
+func newServer(searcher search.MultiEngineSearcher) (*Server, error) {
 	mcpServer := mcp.NewServer(
 		&mcp.Implementation{
 			Name:    "mcp-websearch-server",
@@ -25,7 +56,14 @@ func NewServer() (*Server, error) {
 
 	s := &Server{
 		mcpServer: mcpServer,
-		searcher:  search.NewHybridSearcher(),
+		searcher:  searcher,
+		extractor: extraction.NewHybridExtractor(),
+		newScreenshotter: func(opts ...extraction.ChromedpOption) screenshotter {
+			return extraction.NewChromedpExtractor(opts...)
+		},
+		newDeepReader: func(opts ...extraction.DeepReaderOption) deepReader {
+			return extraction.NewDeepReader(opts...)
+		},
 	}
 
 	if err := s.registerTools(); err != nil {
@@ -35,8 +73,148 @@ func NewServer() (*Server, error) {
 	return s, nil
 }
 
+// Run serves MCP over stdio until the client disconnects or ctx is
+// cancelled, then releases the searcher's resources.
 func (s *Server) Run(ctx context.Context) error {
-	return s.mcpServer.Run(ctx, &mcp.StdioTransport{})
+	return s.run(ctx, &mcp.StdioTransport{})
+}
+
+func (s *Server) run(ctx context.Context, transport mcp.Transport) error {
+	defer s.Close()
+	return s.mcpServer.Run(ctx, transport)
+}
+
+// Warmup prepares the searcher ahead of the first request, e.g. by
+// launching its browser. Searchers without a warmup step are left as is.
+func (s *Server) Warmup(ctx context.Context) error {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+
+	if s.closed {
+		return fmt.Errorf("server is closed")
+	}
+	if w, ok := s.searcher.(interface{ Warmup(context.Context) error }); ok {
+		return w.Warmup(ctx)
+	}
+	return nil
+}
+
+// Close releases resources held by the searcher, such as pooled browsers.
+// It waits for a Warmup in progress, and later Warmup calls fail.
+func (s *Server) Close() error {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+
+	s.closed = true
+	if closer, ok := s.searcher.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// engineToggler is implemented by searchers whose engines can be disabled
+// at runtime
+type engineToggler interface {
+	DisableEngine(name string) error
+	EnableEngine(name string) error
+}
+
+// Output formats accepted by the search tools' format argument
+const (
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+)
+
+// checkFormat rejects format values other than markdown and json; empty
+// means markdown
+func checkFormat(format string) error {
+	switch format {
+	case "", formatMarkdown, formatJSON:
+		return nil
+	}
+	return fmt.Errorf("unsupported format %q: use %q or %q", format, formatMarkdown, formatJSON)
+}
+
+// jsonResult returns results marshalled as a JSON array in a text content
+func jsonResult(results []search.SearchResult) (*mcp.CallToolResult, any, error) {
+	if results == nil {
+		results = []search.SearchResult{}
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode results: %w", err)
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(data)}}}, nil, nil
+}
+
+// totalEstimateLine is the markdown line reporting the engines' "About N
+// results" figure, the largest among results; empty when no engine
+// reported one
+func totalEstimateLine(results []search.SearchResult) string {
+	var total int64
+	for _, result := range results {
+		total = max(total, result.TotalEstimate)
+	}
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("**Estimated total results:** about %d\n\n", total)
+}
+
+// checkFetchURL rejects anything other than an absolute http or https URL
+func checkFetchURL(rawURL string) error {
+	if rawURL == "" {
+		return fmt.Errorf("URL is required")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid URL %q: must be an absolute http or https URL", rawURL)
+	}
+	return nil
+}
+
+// Bounds of the max_content_length argument of the search tools
+const (
+	defaultMaxContentLength = 1500
+	maxContentLengthCeiling = 20000
+)
+
+// checkContentLength returns the per-result content length for a
+// max_content_length argument: the default when it is zero, and an error
+// when it is negative or above maxContentLengthCeiling
+func checkContentLength(length int) (int, error) {
+	switch {
+	case length == 0:
+		return defaultMaxContentLength, nil
+	case length < 0:
+		return 0, fmt.Errorf("max_content_length must be positive, got %d", length)
+	case length > maxContentLengthCeiling:
+		return 0, fmt.Errorf("max_content_length must be at most %d, got %d", maxContentLengthCeiling, length)
+	}
+	return length, nil
+}
+
+// excerpt cuts content to at most maxChars characters, marking the cut
+// with "..."
+func excerpt(content string, maxChars int) string {
+	runes := []rune(content)
+	if len(runes) <= maxChars {
+		return content
+	}
+	return string(runes[:maxChars]) + "..."
+}
+
+// truncateContent cuts content to at most maxChars characters, noting the
+// cut; maxChars <= 0 means no limit
+func truncateContent(content string, maxChars int) string {
+	runes := []rune(content)
+	if maxChars <= 0 || len(runes) <= maxChars {
+		return content
+	}
+	return string(runes[:maxChars]) + fmt.Sprintf("\n\n[Content truncated to %d characters]", maxChars)
 }
 
 func (s *Server) registerTools() error {
@@ -52,6 +230,7 @@ func (s *Server) doRegisterTools() error {
 	type basicSearchArgs struct {
 		Query      string `json:"query" jsonschema:"the search query to execute"`
 		MaxResults int    `json:"max_results,omitempty" jsonschema:"maximum number of results to return"`
+		Format     string `json:"format,omitempty" jsonschema:"output format: markdown (default) or json for the raw result list"`
 	}
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
@@ -61,11 +240,17 @@ func (s *Server) doRegisterTools() error {
 		if args.MaxResults == 0 {
 			args.MaxResults = 10
 		}
+		if err := checkFormat(args.Format); err != nil {
+			return nil, nil, err
+		}
 		results, err := s.searcher.Search(ctx, args.Query, search.SearchOptions{MaxResults: args.MaxResults})
 		if err != nil {
 			return nil, nil, err
 		}
-		var content string
+		if args.Format == formatJSON {
+			return jsonResult(results)
+		}
+		content := totalEstimateLine(results)
 		for i, result := range results {
 			content += fmt.Sprintf("### Result %d\n**Title:** %s\n**URL:** %s\n**Snippet:** %s\n\n", i+1, result.Title, result.URL, result.Snippet)
 		}
@@ -74,9 +259,11 @@ func (s *Server) doRegisterTools() error {
 
 	// websearch_with_content
 	type searchWithContentArgs struct {
-		Query          string `json:"query" jsonschema:"the search query to execute"`
-		MaxResults     int    `json:"max_results,omitempty" jsonschema:"maximum number of results to return"`
-		ExtractContent bool   `json:"extract_content,omitempty" jsonschema:"whether to extract full page content"`
+		Query            string `json:"query" jsonschema:"the search query to execute"`
+		MaxResults       int    `json:"max_results,omitempty" jsonschema:"maximum number of results to return"`
+		ExtractContent   bool   `json:"extract_content,omitempty" jsonschema:"whether to extract full page content"`
+		Format           string `json:"format,omitempty" jsonschema:"output format: markdown (default) or json for the raw result list"`
+		MaxContentLength int    `json:"max_content_length,omitempty" jsonschema:"maximum characters of content shown per result in markdown output (default 1500, max 20000)"`
 	}
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
@@ -84,15 +271,17 @@ func (s *Server) doRegisterTools() error {
 		Description: "Web search with intelligent content extraction from result pages",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args searchWithContentArgs) (*mcp.CallToolResult, any, error) {
 		if args.MaxResults == 0 { args.MaxResults = 5 }
+		if err := checkFormat(args.Format); err != nil { return nil, nil, err }
+		maxContent, err := checkContentLength(args.MaxContentLength)
+		if err != nil { return nil, nil, err }
 		results, err := s.searcher.Search(ctx, args.Query, search.SearchOptions{MaxResults: args.MaxResults, ExtractContent: true})
 		if err != nil { return nil, nil, err }
-		var content string
+		if args.Format == formatJSON { return jsonResult(results) }
+		content := totalEstimateLine(results)
 		for i, result := range results {
 			content += fmt.Sprintf("### Result %d\n**Title:** %s\n**URL:** %s\n", i+1, result.Title, result.URL)
 			if result.Content != "" {
-				ext := result.Content
-				if len(ext) > 1500 { ext = ext[:1500] + "..." }
-				content += fmt.Sprintf("\n**Content:**\n%s\n", ext)
+				content += fmt.Sprintf("\n**Content:**\n%s\n", excerpt(result.Content, maxContent))
 			}
 			content += "\n---\n\n"
 		}
@@ -101,9 +290,11 @@ func (s *Server) doRegisterTools() error {
 
 	// websearch_multi_engine
 	type deepSearchArgs struct {
-		Query      string   `json:"query" jsonschema:"the search query to execute"`
-		MaxResults int      `json:"max_results,omitempty" jsonschema:"maximum number of results to return"`
-		Engines    []string `json:"engines,omitempty" jsonschema:"search engines to use"`
+		Query            string   `json:"query" jsonschema:"the search query to execute"`
+		MaxResults       int      `json:"max_results,omitempty" jsonschema:"maximum number of results to return"`
+		Engines          []string `json:"engines,omitempty" jsonschema:"search engines to use (bing, brave, duckduckgo, mojeek, startpage, google, yandex, qwant, searxng; aliases such as ddg are accepted)"`
+		Format           string   `json:"format,omitempty" jsonschema:"output format: markdown (default) or json for the raw result list"`
+		MaxContentLength int      `json:"max_content_length,omitempty" jsonschema:"maximum characters of content shown per result in markdown output (default 1500, max 20000)"`
 	}
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
@@ -111,25 +302,150 @@ func (s *Server) doRegisterTools() error {
 		Description: "Comprehensive search across multiple engines with content extraction",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args deepSearchArgs) (*mcp.CallToolResult, any, error) {
 		if args.MaxResults == 0 { args.MaxResults = 10 }
+		if err := checkFormat(args.Format); err != nil { return nil, nil, err }
+		maxContent, err := checkContentLength(args.MaxContentLength)
+		if err != nil { return nil, nil, err }
 		results, err := s.searcher.DeepSearch(ctx, args.Query, search.SearchOptions{MaxResults: args.MaxResults, Engines: args.Engines, ExtractContent: true})
 		if err != nil { return nil, nil, err }
-		var content string
+		if args.Format == formatJSON { return jsonResult(results) }
+		content := totalEstimateLine(results)
 		for i, result := range results {
 			content += fmt.Sprintf("### Result %d\n**Title:** %s\n**URL:** %s\n", i+1, result.Title, result.URL)
 			if result.Content != "" {
-				ext := result.Content
-				if len(ext) > 1500 { ext = ext[:1500] + "..." }
-				content += fmt.Sprintf("\n**Content:**\n%s\n", ext)
+				content += fmt.Sprintf("\n**Content:**\n%s\n", excerpt(result.Content, maxContent))
 			}
 			content += "\n---\n\n"
 		}
 		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: content}}}, nil, nil
 	})
 
+	// websearch_news
+	type newsSearchArgs struct {
+		Query      string   `json:"query" jsonschema:"the news search query to execute"`
+		MaxResults int      `json:"max_results,omitempty" jsonschema:"maximum number of results to return"`
+		Engines    []string `json:"engines,omitempty" jsonschema:"news engines to use (bing, duckduckgo; default both)"`
+		Format     string   `json:"format,omitempty" jsonschema:"output format: markdown (default) or json for the raw result list"`
+	}
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "websearch_news",
+		Description: "News search across the Bing and DuckDuckGo news verticals, newest first, with each story's source and publication date",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args newsSearchArgs) (*mcp.CallToolResult, any, error) {
+		if args.MaxResults == 0 {
+			args.MaxResults = 10
+		}
+		if err := checkFormat(args.Format); err != nil {
+			return nil, nil, err
+		}
+		results, err := search.SearchNews(ctx, s.searcher, args.Query, search.SearchOptions{MaxResults: args.MaxResults, Engines: args.Engines})
+		if err != nil {
+			return nil, nil, err
+		}
+		if args.Format == formatJSON {
+			return jsonResult(results)
+		}
+		var content string
+		for i, result := range results {
+			content += fmt.Sprintf("### Result %d\n**Title:** %s\n**URL:** %s\n", i+1, result.Title, result.URL)
+			if result.Source != "" {
+				content += fmt.Sprintf("**Source:** %s\n", result.Source)
+			}
+			if !result.PublishedAt.IsZero() {
+				content += fmt.Sprintf("**Published:** %s\n", result.PublishedAt.Format(time.RFC3339))
+			}
+			content += fmt.Sprintf("**Snippet:** %s\n\n", result.Snippet)
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: content}}}, nil, nil
+	})
+
+	// websearch_images
+	type imageSearchArgs struct {
+		Query      string `json:"query" jsonschema:"the image search query to execute"`
+		MaxResults int    `json:"max_results,omitempty" jsonschema:"maximum number of images to return"`
+	}
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "websearch_images",
+		Description: "Image search returning image, thumbnail and source page URLs as markdown",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args imageSearchArgs) (*mcp.CallToolResult, any, error) {
+		if args.MaxResults == 0 {
+			args.MaxResults = 10
+		}
+		images, ok := s.searcher.(search.ImageSearcher)
+		if !ok {
+			return nil, nil, fmt.Errorf("image search not supported")
+		}
+		results, err := images.SearchImages(ctx, args.Query, args.MaxResults)
+		if err != nil {
+			return nil, nil, err
+		}
+		var content string
+		for i, image := range results {
+			content += fmt.Sprintf("### Image %d\n![%s](%s)\n**Image URL:** %s\n**Thumbnail:** %s\n**Source:** %s\n", i+1, image.Title, image.ImageURL, image.ImageURL, image.ThumbnailURL, image.SourceURL)
+			if image.Width > 0 && image.Height > 0 {
+				content += fmt.Sprintf("**Size:** %dx%d\n", image.Width, image.Height)
+			}
+			content += "\n"
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: content}}}, nil, nil
+	})
+
+	// websearch_suggest
+	type suggestArgs struct {
+		Query string `json:"query" jsonschema:"the partly typed query to complete"`
+	}
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "websearch_suggest",
+		Description: "Query suggestions for a partly typed query, one per line, from DuckDuckGo or Bing autocomplete",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args suggestArgs) (*mcp.CallToolResult, any, error) {
+		suggester, ok := s.searcher.(search.Suggester)
+		if !ok {
+			return nil, nil, fmt.Errorf("suggestions not supported")
+		}
+		suggestions, err := suggester.Suggest(ctx, args.Query)
+		if err != nil {
+			return nil, nil, err
+		}
+		var content string
+		for _, suggestion := range suggestions {
+			content += fmt.Sprintf("- %s\n", suggestion)
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: content}}}, nil, nil
+	})
+
+	// websearch_set_engine_enabled
+	type setEngineEnabledArgs struct {
+		Engine  string `json:"engine" jsonschema:"the search engine to enable or disable (bing, brave, duckduckgo, mojeek, startpage, google, yandex, qwant)"`
+		Enabled bool   `json:"enabled" jsonschema:"true to enable the engine, false to disable it"`
+	}
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "websearch_set_engine_enabled",
+		Description: "Enable or disable a search engine at runtime, e.g. while it is blocked. Disabled engines are skipped by all searches.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args setEngineEnabledArgs) (*mcp.CallToolResult, any, error) {
+		if args.Engine == "" {
+			return nil, nil, fmt.Errorf("engine is required")
+		}
+		toggler, ok := s.searcher.(engineToggler)
+		if !ok {
+			return nil, nil, fmt.Errorf("enabling engines not supported")
+		}
+		state, toggle := "enabled", toggler.EnableEngine
+		if !args.Enabled {
+			state, toggle = "disabled", toggler.DisableEngine
+		}
+		if err := toggle(args.Engine); err != nil {
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Engine %s %s", args.Engine, state)}}}, nil, nil
+	})
+
 	// websearch_ai_summary
 	type searchAndAggregateArgs struct {
 		Query      string `json:"query" jsonschema:"the search query to execute"`
 		MaxResults int    `json:"max_results,omitempty" jsonschema:"maximum number of results to return"`
+		MaxTokens  int    `json:"max_tokens,omitempty" jsonschema:"approximate token budget for the whole aggregate, trimming content at sentence boundaries (default: 1500 characters per result)"`
 	}
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
@@ -138,7 +454,7 @@ func (s *Server) doRegisterTools() error {
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args searchAndAggregateArgs) (*mcp.CallToolResult, any, error) {
 		if args.MaxResults == 0 { args.MaxResults = 5 }
 		if hs, ok := s.searcher.(*search.HybridMultiEngineSearcher); ok {
-			aggregated, err := hs.SearchAndAggregate(ctx, args.Query, args.MaxResults)
+			aggregated, err := hs.SearchAndAggregateWithOptions(ctx, args.Query, search.AggregateOptions{MaxResults: args.MaxResults, MaxTokens: args.MaxTokens})
 			if err != nil { return nil, nil, err }
 			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: aggregated}}}, nil, nil
 		}
@@ -155,23 +471,56 @@ func (s *Server) doRegisterTools() error {
 		Description: "Directly fetch and extract the main content from a specific URL using Readability and Markdown conversion",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args fetchPageContentArgs) (*mcp.CallToolResult, any, error) {
 		if args.URL == "" { return nil, nil, fmt.Errorf("URL is required") }
-		content, err := extraction.NewHybridExtractor().ExtractContent(ctx, args.URL)
+		content, err := s.extractor.ExtractContent(ctx, args.URL)
 		if err != nil { return nil, nil, err }
 		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: content}}}, nil, nil
 	})
 
-	// take_screenshot
+	// websearch_fetch
+	type fetchArgs struct {
+		URL      string `json:"url" jsonschema:"the http or https URL of the page to fetch"`
+		MaxChars int    `json:"max_chars,omitempty" jsonschema:"maximum number of characters of content to return (default: no limit)"`
+	}
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "websearch_fetch",
+		Description: "Fetch a single URL and return its cleaned, readable content as markdown, without searching or crawling linked pages",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args fetchArgs) (*mcp.CallToolResult, any, error) {
+		if err := checkFetchURL(args.URL); err != nil {
+			return nil, nil, err
+		}
+		if args.MaxChars < 0 {
+			return nil, nil, fmt.Errorf("max_chars must be positive, got %d", args.MaxChars)
+		}
+		content, err := s.extractor.ExtractContent(ctx, args.URL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch %s: %w", args.URL, err)
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: truncateContent(content, args.MaxChars)}}}, nil, nil
+	})
+
+	// websearch_screenshot, and take_screenshot under its original name
 	type takeScreenshotArgs struct {
 		URL      string `json:"url" jsonschema:"the URL of the page to screenshot"`
 		FullPage bool   `json:"full_page,omitempty" jsonschema:"whether to take a full page screenshot"`
+		Width    int    `json:"width,omitempty" jsonschema:"viewport width in pixels, set together with height (default: Chrome's default)"`
+		Height   int    `json:"height,omitempty" jsonschema:"viewport height in pixels, set together with width (default: Chrome's default)"`
+		WaitFor  string `json:"wait_for,omitempty" jsonschema:"CSS selector to wait for before capturing, for pages rendered by JavaScript"`
 	}
 
-	mcp.AddTool(s.mcpServer, &mcp.Tool{
-		Name:        "take_screenshot",
-		Description: "Capture a screenshot of a webpage",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, args takeScreenshotArgs) (*mcp.CallToolResult, any, error) {
-		if args.URL == "" { return nil, nil, fmt.Errorf("URL is required") }
-		imgData, err := extraction.NewChromedpExtractor().CaptureScreenshot(ctx, args.URL, args.FullPage)
+	takeScreenshot := func(ctx context.Context, req *mcp.CallToolRequest, args takeScreenshotArgs) (*mcp.CallToolResult, any, error) {
+		if err := checkFetchURL(args.URL); err != nil { return nil, nil, err }
+		if (args.Width != 0 || args.Height != 0) && (args.Width <= 0 || args.Height <= 0) {
+			return nil, nil, fmt.Errorf("width and height must both be given and positive, got %dx%d", args.Width, args.Height)
+		}
+		var opts []extraction.ChromedpOption
+		if args.Width > 0 && args.Height > 0 {
+			opts = append(opts, extraction.WithViewport(args.Width, args.Height))
+		}
+		if args.WaitFor != "" {
+			opts = append(opts, extraction.WithWaitSelector(args.WaitFor))
+		}
+		imgData, err := s.newScreenshotter(opts...).CaptureScreenshot(ctx, args.URL, args.FullPage)
 		if err != nil { return nil, nil, err }
 		
 		return &mcp.CallToolResult{
@@ -183,21 +532,39 @@ func (s *Server) doRegisterTools() error {
 				&mcp.TextContent{Text: fmt.Sprintf("Successfully captured screenshot of %s (%d bytes).", args.URL, len(imgData))},
 			},
 		}, nil, nil
-	})
-
-	// deep_read_page
-	type deepReadPageArgs struct {
-		URL        string `json:"url" jsonschema:"the URL of the page to deep read"`
-		MaxLinks   int    `json:"max_links,omitempty" jsonschema:"maximum number of sub-pages to crawl (default 10, max 20)"`
-		CrossDomain bool   `json:"cross_domain,omitempty" jsonschema:"allow crawling cross-domain links (default false, same-domain only)"`
 	}
 
 	mcp.AddTool(s.mcpServer, &mcp.Tool{
-		Name:        "deep_read_page",
-		Description: "Deep read a webpage by extracting main content and intelligently crawling related sub-pages. Returns structured markdown with main content and linked page summaries. Useful for comprehensive page analysis.",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, args deepReadPageArgs) (*mcp.CallToolResult, any, error) {
-		if args.URL == "" {
-			return nil, nil, fmt.Errorf("URL is required")
+		Name:        "websearch_screenshot",
+		Description: "Capture a PNG screenshot of a webpage, optionally of the full page, at a given viewport size, or once a selector has rendered",
+	}, takeScreenshot)
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "take_screenshot",
+		Description: "Capture a screenshot of a webpage (same as websearch_screenshot)",
+	}, takeScreenshot)
+
+	// websearch_deep_read, and deep_read_page under its original name
+	type deepReadArgs struct {
+		URL          string `json:"url" jsonschema:"the http or https URL of the page to deep read"`
+		MaxLinks     int    `json:"max_links,omitempty" jsonschema:"maximum number of sub-pages to crawl (default 10, max 20)"`
+		SameDomain   *bool  `json:"same_domain,omitempty" jsonschema:"whether to crawl only links on the page's own domain (default true)"`
+		CrossDomain  bool   `json:"cross_domain,omitempty" jsonschema:"allow crawling cross-domain links, the same as same_domain false"`
+		ContentLimit int    `json:"content_limit,omitempty" jsonschema:"maximum characters of content kept per page (default 2000)"`
+	}
+
+	deepRead := func(ctx context.Context, req *mcp.CallToolRequest, args deepReadArgs) (*mcp.CallToolResult, any, error) {
+		if err := checkFetchURL(args.URL); err != nil {
+			return nil, nil, err
+		}
+		if args.MaxLinks < 0 || args.MaxLinks > extraction.MaxLinksLimit {
+			return nil, nil, fmt.Errorf("max_links must be between 1 and %d, or 0 for the default, got %d", extraction.MaxLinksLimit, args.MaxLinks)
+		}
+		if args.CrossDomain && args.SameDomain != nil && *args.SameDomain {
+			return nil, nil, fmt.Errorf("same_domain and cross_domain can't both be true")
+		}
+		if args.ContentLimit < 0 {
+			return nil, nil, fmt.Errorf("content_limit must be positive, got %d", args.ContentLimit)
 		}
 
 		// Build options - defaults are handled by DeepReader
@@ -205,19 +572,34 @@ func (s *Server) doRegisterTools() error {
 		if args.MaxLinks > 0 {
 			opts = append(opts, extraction.WithMaxLinks(args.MaxLinks))
 		}
+		if args.SameDomain != nil {
+			opts = append(opts, extraction.WithSameDomain(*args.SameDomain))
+		}
 		if args.CrossDomain {
 			opts = append(opts, extraction.WithSameDomain(false))
 		}
+		if args.ContentLimit > 0 {
+			opts = append(opts, extraction.WithContentLimit(args.ContentLimit))
+		}
 
-		reader := extraction.NewDeepReader(opts...)
-		result, err := reader.DeepRead(ctx, args.URL)
+		result, err := s.newDeepReader(opts...).DeepRead(ctx, args.URL)
 		if err != nil {
 			return nil, nil, err
 		}
 
 		markdown := result.ToMarkdown()
 		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: markdown}}}, nil, nil
-	})
+	}
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "websearch_deep_read",
+		Description: "Deep read a webpage by extracting its main content and crawling up to 20 related sub-pages, optionally off its domain. Returns structured markdown with the main content and each linked page's summary.",
+	}, deepRead)
+
+	mcp.AddTool(s.mcpServer, &mcp.Tool{
+		Name:        "deep_read_page",
+		Description: "Deep read a webpage by extracting main content and intelligently crawling related sub-pages. Returns structured markdown with main content and linked page summaries. Useful for comprehensive page analysis.",
+	}, deepRead)
 
 	return nil
 }
\ No newline at end of file
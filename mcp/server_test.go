@@ -1,7 +1,16 @@
 package mcp
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/liliang-cn/mcp-websearch-server/extraction"
+	"github.com/liliang-cn/mcp-websearch-server/search"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 func TestNewServer(t *testing.T) {
@@ -33,3 +42,617 @@ func TestServer_RegisterTools(t *testing.T) {
 		t.Fatal("MCP server should be initialized")
 	}
 }
+
+type closingSearcher struct {
+	search.MultiEngineSearcher
+	closed chan struct{}
+}
+
+func (c *closingSearcher) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func TestServer_RunClosesSearcherOnCancel(t *testing.T) {
+	searcher := &closingSearcher{closed: make(chan struct{})}
+	server, err := newServer(searcher)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	serverTransport, _ := mcp.NewInMemoryTransports()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.run(ctx, serverTransport)
+	}()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+
+	select {
+	case <-searcher.closed:
+	default:
+		t.Error("expected searcher to be closed after Run returned")
+	}
+}
+
+// warmingSearcher blocks in Warmup until release is closed
+type warmingSearcher struct {
+	closingSearcher
+	started chan struct{}
+	release chan struct{}
+	warmups int
+}
+
+func (w *warmingSearcher) Warmup(ctx context.Context) error {
+	w.warmups++
+	close(w.started)
+	<-w.release
+	return nil
+}
+
+func TestServer_CloseWaitsForWarmup(t *testing.T) {
+	searcher := &warmingSearcher{
+		closingSearcher: closingSearcher{closed: make(chan struct{})},
+		started:         make(chan struct{}),
+		release:         make(chan struct{}),
+	}
+	server, err := newServer(searcher)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	go server.Warmup(context.Background())
+	<-searcher.started
+
+	closed := make(chan struct{})
+	go func() {
+		server.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		t.Fatal("expected Close to wait for the warmup in progress")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(searcher.release)
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return after the warmup finished")
+	}
+
+	if err := server.Warmup(context.Background()); err == nil {
+		t.Error("expected Warmup after Close to fail")
+	}
+	if searcher.warmups != 1 {
+		t.Errorf("expected one warmup, got %d", searcher.warmups)
+	}
+}
+
+// connectClient connects an in-memory MCP client to server
+func connectClient(t *testing.T, server *Server) *mcp.ClientSession {
+	t.Helper()
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+	if _, err := server.mcpServer.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatalf("server connect failed: %v", err)
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect failed: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+type togglingSearcher struct {
+	search.MultiEngineSearcher
+	disabled map[string]bool
+}
+
+func (s *togglingSearcher) DisableEngine(name string) error {
+	if name != "bing" {
+		return &search.UnknownEngineError{Names: []string{name}}
+	}
+	s.disabled[name] = true
+	return nil
+}
+
+func (s *togglingSearcher) EnableEngine(name string) error {
+	if name != "bing" {
+		return &search.UnknownEngineError{Names: []string{name}}
+	}
+	delete(s.disabled, name)
+	return nil
+}
+
+func TestServer_SetEngineEnabledTool(t *testing.T) {
+	searcher := &togglingSearcher{disabled: make(map[string]bool)}
+	server, err := newServer(searcher)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	ctx := context.Background()
+	session := connectClient(t, server)
+
+	call := func(enabled bool) {
+		res, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "websearch_set_engine_enabled",
+			Arguments: map[string]any{"engine": "bing", "enabled": enabled},
+		})
+		if err != nil {
+			t.Fatalf("tool call failed: %v", err)
+		}
+		if res.IsError {
+			t.Fatalf("tool returned error: %+v", res.Content)
+		}
+	}
+
+	call(false)
+	if !searcher.disabled["bing"] {
+		t.Error("expected bing to be disabled")
+	}
+
+	call(true)
+	if searcher.disabled["bing"] {
+		t.Error("expected bing to be enabled again")
+	}
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "websearch_set_engine_enabled",
+		Arguments: map[string]any{"engine": "bnig", "enabled": false},
+	})
+	if err != nil {
+		t.Fatalf("tool call failed: %v", err)
+	}
+	if !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "bnig") {
+		t.Errorf("expected an unknown engine to be reported, got %+v", res.Content)
+	}
+}
+
+type fakeEngine struct {
+	results []search.SearchResult
+}
+
+func (f *fakeEngine) Name() string { return "bing" }
+
+func (f *fakeEngine) Search(ctx context.Context, query string, maxResults int) ([]search.SearchResult, error) {
+	return f.results, nil
+}
+
+func TestServer_BasicSearchWithFakeEngine(t *testing.T) {
+	searcher := search.NewSearcherWithEngines(map[string]search.SearchEngine{
+		"bing": &fakeEngine{results: []search.SearchResult{
+			{Title: "Fake Result", URL: "https://example.com/fake", Snippet: "From a fake engine", Engine: "bing"},
+		}},
+	}, nil)
+
+	server, err := newServer(searcher)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	res, err := connectClient(t, server).CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "websearch_basic",
+		Arguments: map[string]any{"query": "anything"},
+	})
+	if err != nil {
+		t.Fatalf("tool call failed: %v", err)
+	}
+
+	text := res.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "Fake Result") || !strings.Contains(text, "https://example.com/fake") {
+		t.Errorf("expected fake result in output, got %q", text)
+	}
+}
+
+func TestServer_SearchToolsTotalEstimate(t *testing.T) {
+	searcher := search.NewSearcherWithEngines(map[string]search.SearchEngine{
+		"bing": &fakeEngine{results: []search.SearchResult{
+			{Title: "Fake Result", URL: "https://example.com/fake", Engine: "bing", TotalEstimate: 1230000},
+		}},
+	}, &fakeExtractor{content: "Page content."})
+
+	server, err := newServer(searcher)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	session := connectClient(t, server)
+
+	for _, tool := range []string{"websearch_basic", "websearch_with_content", "websearch_multi_engine"} {
+		res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      tool,
+			Arguments: map[string]any{"query": "anything"},
+		})
+		if err != nil {
+			t.Fatalf("%s: tool call failed: %v", tool, err)
+		}
+		if text := res.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "about 1230000") {
+			t.Errorf("%s: expected the total estimate in the output, got %q", tool, text)
+		}
+	}
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "websearch_basic",
+		Arguments: map[string]any{"query": "anything else", "format": "json"},
+	})
+	if err != nil {
+		t.Fatalf("tool call failed: %v", err)
+	}
+	if text := res.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, `"total_estimate":1230000`) {
+		t.Errorf("expected total_estimate in the JSON output, got %q", text)
+	}
+}
+
+func TestServer_BasicSearchJSONFormat(t *testing.T) {
+	searcher := search.NewSearcherWithEngines(map[string]search.SearchEngine{
+		"bing": &fakeEngine{results: []search.SearchResult{
+			{Title: "Fake Result", URL: "https://example.com/fake", Snippet: "From a fake engine", Engine: "bing"},
+		}},
+	}, nil)
+
+	server, err := newServer(searcher)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	session := connectClient(t, server)
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "websearch_basic",
+		Arguments: map[string]any{"query": "anything", "format": "json"},
+	})
+	if err != nil {
+		t.Fatalf("tool call failed: %v", err)
+	}
+
+	var results []search.SearchResult
+	text := res.Content[0].(*mcp.TextContent).Text
+	if err := json.Unmarshal([]byte(text), &results); err != nil {
+		t.Fatalf("expected valid JSON, got %q: %v", text, err)
+	}
+	if len(results) != 1 || results[0].URL != "https://example.com/fake" || results[0].Snippet != "From a fake engine" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	res, err = session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "websearch_basic",
+		Arguments: map[string]any{"query": "anything", "format": "xml"},
+	})
+	if err != nil {
+		t.Fatalf("tool call failed: %v", err)
+	}
+	if !res.IsError {
+		t.Error("expected an unsupported format to be rejected")
+	}
+}
+
+func TestServer_MaxContentLength(t *testing.T) {
+	long := strings.Repeat("a", 6000)
+	searcher := search.NewSearcherWithEngines(map[string]search.SearchEngine{
+		"bing": &fakeEngine{results: []search.SearchResult{
+			{Title: "Long Page", URL: "https://example.com/long", Engine: "bing"},
+		}},
+	}, &fakeExtractor{content: long})
+
+	server, err := newServer(searcher)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	session := connectClient(t, server)
+
+	for _, tool := range []string{"websearch_with_content", "websearch_multi_engine"} {
+		for _, tt := range []struct {
+			args map[string]any
+			want int
+		}{
+			{map[string]any{"query": "anything"}, 1500},
+			{map[string]any{"query": "anything", "max_content_length": 5000}, 5000},
+		} {
+			res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: tool, Arguments: tt.args})
+			if err != nil {
+				t.Fatalf("%s: tool call failed: %v", tool, err)
+			}
+			text := res.Content[0].(*mcp.TextContent).Text
+			if !strings.Contains(text, long[:tt.want]+"...") || strings.Contains(text, long[:tt.want+1]) {
+				t.Errorf("%s: expected content cut at %d characters", tool, tt.want)
+			}
+		}
+
+		for _, length := range []int{-1, 20001} {
+			res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+				Name:      tool,
+				Arguments: map[string]any{"query": "anything", "max_content_length": length},
+			})
+			if err != nil {
+				t.Fatalf("%s: tool call failed: %v", tool, err)
+			}
+			if !res.IsError {
+				t.Errorf("%s: expected max_content_length %d to be rejected", tool, length)
+			}
+		}
+	}
+}
+
+type fakeExtractor struct {
+	content string
+	err     error
+	urls    []string
+}
+
+func (f *fakeExtractor) ExtractContent(ctx context.Context, url string) (string, error) {
+	f.urls = append(f.urls, url)
+	return f.content, f.err
+}
+
+func TestServer_FetchTool(t *testing.T) {
+	server, err := newServer(search.NewSearcherWithEngines(map[string]search.SearchEngine{"bing": &fakeEngine{}}, nil))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	extractor := &fakeExtractor{content: "# Title\n\nReadable body text"}
+	server.extractor = extractor
+	session := connectClient(t, server)
+
+	call := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "websearch_fetch", Arguments: args})
+		if err != nil {
+			t.Fatalf("tool call failed: %v", err)
+		}
+		return res
+	}
+
+	res := call(map[string]any{"url": "https://example.com/page"})
+	if res.IsError || res.Content[0].(*mcp.TextContent).Text != extractor.content {
+		t.Errorf("expected the extracted content, got %+v", res.Content)
+	}
+
+	res = call(map[string]any{"url": "https://example.com/page", "max_chars": 7})
+	if text := res.Content[0].(*mcp.TextContent).Text; !strings.HasPrefix(text, "# Title") || strings.Contains(text, "body") {
+		t.Errorf("expected content cut to 7 characters, got %q", text)
+	}
+
+	res = call(map[string]any{"url": "ftp://example.com/file"})
+	if !res.IsError || len(extractor.urls) != 2 {
+		t.Errorf("expected a non-http URL to be rejected before extraction, got %+v", res.Content)
+	}
+
+	res = call(map[string]any{"url": "https://example.com/page", "max_chars": -1})
+	if !res.IsError || len(extractor.urls) != 2 {
+		t.Errorf("expected a negative max_chars to be rejected before extraction, got %+v", res.Content)
+	}
+
+	extractor.err = errors.New("navigation timed out")
+	res = call(map[string]any{"url": "https://example.com/slow"})
+	if !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "navigation timed out") {
+		t.Errorf("expected the extraction error to be surfaced, got %+v", res.Content)
+	}
+}
+
+func TestServer_NewsTool(t *testing.T) {
+	published := time.Date(2025, 2, 11, 18, 0, 0, 0, time.UTC)
+	searcher := search.NewSearcherWithEngines(map[string]search.SearchEngine{
+		"bing": &fakeEngine{results: []search.SearchResult{
+			{Title: "Go 1.24 released", URL: "https://news.example.com/go-124", Engine: "bing", Source: "Example News", PublishedAt: published},
+		}},
+	}, nil)
+
+	server, err := newServer(searcher)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	res, err := connectClient(t, server).CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "websearch_news",
+		Arguments: map[string]any{"query": "golang"},
+	})
+	if err != nil {
+		t.Fatalf("tool call failed: %v", err)
+	}
+
+	text := res.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"Go 1.24 released", "**Source:** Example News", "**Published:** 2025-02-11T18:00:00Z"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in output, got %q", want, text)
+		}
+	}
+}
+
+// imageSearcher is a searcher that answers image searches with fixed results
+type imageSearcher struct {
+	search.MultiEngineSearcher
+	images []search.ImageResult
+}
+
+func (s *imageSearcher) SearchImages(ctx context.Context, query string, maxResults int) ([]search.ImageResult, error) {
+	return s.images, nil
+}
+
+func TestServer_ImagesTool(t *testing.T) {
+	server, err := newServer(&imageSearcher{images: []search.ImageResult{
+		{Title: "The Go gopher", ImageURL: "https://img.example.com/gopher.png", ThumbnailURL: "https://tse.example.com/th?id=1", SourceURL: "https://blog.example.com/gopher", Width: 1920, Height: 1080},
+	}})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	res, err := connectClient(t, server).CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "websearch_images",
+		Arguments: map[string]any{"query": "gopher"},
+	})
+	if err != nil {
+		t.Fatalf("tool call failed: %v", err)
+	}
+
+	text := res.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"![The Go gopher](https://img.example.com/gopher.png)", "**Source:** https://blog.example.com/gopher", "**Size:** 1920x1080"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in output, got %q", want, text)
+		}
+	}
+}
+
+// suggestingSearcher is a searcher that completes queries with fixed
+// suggestions
+type suggestingSearcher struct {
+	search.MultiEngineSearcher
+	prefix      string
+	suggestions []string
+}
+
+func (s *suggestingSearcher) Suggest(ctx context.Context, prefix string) ([]string, error) {
+	s.prefix = prefix
+	return s.suggestions, nil
+}
+
+func TestServer_SuggestTool(t *testing.T) {
+	searcher := &suggestingSearcher{suggestions: []string{"golang rocket", "golang roadmap"}}
+	server, err := newServer(searcher)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	res, err := connectClient(t, server).CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "websearch_suggest",
+		Arguments: map[string]any{"query": "golang ro"},
+	})
+	if err != nil {
+		t.Fatalf("tool call failed: %v", err)
+	}
+
+	if searcher.prefix != "golang ro" {
+		t.Errorf("expected the query to be completed, got %q", searcher.prefix)
+	}
+	if text := res.Content[0].(*mcp.TextContent).Text; text != "- golang rocket\n- golang roadmap\n" {
+		t.Errorf("expected one suggestion per line, got %q", text)
+	}
+}
+
+// fakeScreenshotter returns fixed image bytes and records the capture
+type fakeScreenshotter struct {
+	opts     int
+	url      string
+	fullPage bool
+}
+
+func (f *fakeScreenshotter) CaptureScreenshot(ctx context.Context, url string, fullPage bool) ([]byte, error) {
+	f.url, f.fullPage = url, fullPage
+	return []byte("\x89PNG"), nil
+}
+
+func TestServer_ScreenshotTool(t *testing.T) {
+	server, err := newServer(&closingSearcher{closed: make(chan struct{})})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	shooter := &fakeScreenshotter{}
+	server.newScreenshotter = func(opts ...extraction.ChromedpOption) screenshotter {
+		shooter.opts = len(opts)
+		return shooter
+	}
+	session := connectClient(t, server)
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "websearch_screenshot",
+		Arguments: map[string]any{"url": "https://example.com", "full_page": true, "width": 1280, "height": 800, "wait_for": "#app"},
+	})
+	if err != nil {
+		t.Fatalf("tool call failed: %v", err)
+	}
+
+	image, ok := res.Content[0].(*mcp.ImageContent)
+	if !ok || string(image.Data) != "\x89PNG" || image.MIMEType != "image/png" {
+		t.Fatalf("expected the PNG as image content, got %+v", res.Content[0])
+	}
+	if shooter.url != "https://example.com" || !shooter.fullPage || shooter.opts != 2 {
+		t.Errorf("expected a full-page capture with viewport and wait options, got %+v", shooter)
+	}
+
+	for _, args := range []map[string]any{
+		{"url": "file:///etc/passwd"},
+		{"url": "https://example.com", "width": 1280},
+		{"url": "https://example.com", "height": 800},
+		{"url": "https://example.com", "width": -1, "height": 800},
+	} {
+		res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "websearch_screenshot", Arguments: args})
+		if err == nil && !res.IsError {
+			t.Errorf("expected %v to be rejected", args)
+		}
+	}
+}
+
+// fakeDeepReader returns a fixed result and records the read
+type fakeDeepReader struct {
+	opts int
+	url  string
+}
+
+func (f *fakeDeepReader) DeepRead(ctx context.Context, url string) (*extraction.DeepReadResult, error) {
+	f.url = url
+	return &extraction.DeepReadResult{
+		MainURL:     url,
+		MainTitle:   "Docs",
+		MainContent: "Main content.",
+		SubPages:    []extraction.SubPageResult{{URL: url + "/install", LinkText: "Install", Content: "Install steps."}},
+	}, nil
+}
+
+func TestServer_DeepReadTool(t *testing.T) {
+	server, err := newServer(&closingSearcher{closed: make(chan struct{})})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	reader := &fakeDeepReader{}
+	server.newDeepReader = func(opts ...extraction.DeepReaderOption) deepReader {
+		reader.opts = len(opts)
+		return reader
+	}
+	session := connectClient(t, server)
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "websearch_deep_read",
+		Arguments: map[string]any{"url": "https://example.com/docs", "max_links": 5, "same_domain": false, "content_limit": 500},
+	})
+	if err != nil {
+		t.Fatalf("tool call failed: %v", err)
+	}
+
+	text := res.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"# [Docs](https://example.com/docs)", "Main content.", "[Install](https://example.com/docs/install)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in output, got %q", want, text)
+		}
+	}
+	if reader.url != "https://example.com/docs" || reader.opts != 3 {
+		t.Errorf("expected a read with max_links, same_domain and content_limit options, got %+v", reader)
+	}
+
+	for _, args := range []map[string]any{
+		{"url": "file:///etc/passwd"},
+		{"url": "https://example.com", "max_links": 21},
+		{"url": "https://example.com", "content_limit": -1},
+		{"url": "https://example.com", "same_domain": true, "cross_domain": true},
+	} {
+		res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "websearch_deep_read", Arguments: args})
+		if err == nil && !res.IsError {
+			t.Errorf("expected %v to be rejected", args)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestMultiEngineSearcher_SearchFallbackChain(t *testing.T) {
@@ -168,3 +169,148 @@ func TestMultiEngineSearcher_DeepSearchConcurrentErrors(t *testing.T) {
 		t.Log("Content extraction may have failed, but that's okay for this test")
 	}
 }
+
+func TestMultiEngineSearcher_MinResultsTopUp(t *testing.T) {
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing": &mockSearchEngine{name: "bing", results: []SearchResult{
+				{Title: "Bing 1", URL: "http://bing1.com", Engine: "bing"},
+			}},
+			"brave": &mockSearchEngine{name: "brave", results: []SearchResult{
+				{Title: "Brave 1", URL: "http://brave1.com", Engine: "brave"},
+				{Title: "Duplicate", URL: "http://bing1.com", Engine: "brave"},
+			}},
+		},
+		extractor: &mockContentExtractor{},
+	}
+
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{
+		MaxResults: 5,
+		MinResults: 2,
+	})
+	if err != nil {
+		t.Fatalf("expected top-up to satisfy MinResults: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results after top-up, got %d", len(results))
+	}
+
+	if results[1].URL != "http://brave1.com" {
+		t.Errorf("expected top-up result from brave, got %s", results[1].URL)
+	}
+}
+
+// engineQueryRecorder records the EngineQuery it is sent
+type engineQueryRecorder struct {
+	mockSearchEngine
+	query EngineQuery
+}
+
+func (e *engineQueryRecorder) SearchQuery(ctx context.Context, q EngineQuery) ([]SearchResult, error) {
+	e.query = q
+	return e.Search(ctx, q.Query, q.MaxResults)
+}
+
+func TestSearch_MinResultsTopUpKeepsQuery(t *testing.T) {
+	brave := &engineQueryRecorder{mockSearchEngine: mockSearchEngine{name: "brave", results: []SearchResult{
+		{Title: "Brave 1", URL: "http://brave1.com", Engine: "brave"},
+	}}}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{
+		"bing": &engineQueryRecorder{mockSearchEngine: mockSearchEngine{name: "bing", results: []SearchResult{
+			{Title: "Bing 1", URL: "http://bing1.com", Engine: "bing"},
+		}}},
+		"brave": brave,
+	}, nil).(*multiEngineSearcher)
+
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{
+		MaxResults: 5,
+		MinResults: 2,
+		Offset:     10,
+		SafeSearch: SafeSearchStrict,
+		Language:   "de",
+		Region:     "AT",
+		After:      after,
+	})
+	if err != nil || len(results) != 2 {
+		t.Fatalf("expected top-up to satisfy MinResults, got %d results: %v", len(results), err)
+	}
+
+	q := brave.query
+	if q.SafeSearch != SafeSearchStrict || q.Language != "de" || q.Region != "AT" || !q.After.Equal(after) || q.Offset != 10 || q.MaxResults != 5 {
+		t.Errorf("expected the top-up to keep the search's options, got %+v", q)
+	}
+	if stats := searcher.Stats().Engines["brave"]; stats.Requests != 1 {
+		t.Errorf("expected the top-up to be counted, got %+v", stats)
+	}
+}
+
+func TestMultiEngineSearcher_MinResultsInsufficient(t *testing.T) {
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing": &mockSearchEngine{name: "bing", results: []SearchResult{
+				{Title: "Bing 1", URL: "http://bing1.com", Engine: "bing"},
+			}},
+			"brave": &mockSearchEngine{name: "brave", results: []SearchResult{
+				{Title: "Brave 1", URL: "http://brave1.com", Engine: "brave"},
+			}},
+			"duckduckgo": &mockSearchEngine{name: "duckduckgo", err: errors.New("blocked")},
+		},
+		extractor: &mockContentExtractor{},
+	}
+
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{
+		MaxResults: 10,
+		MinResults: 5,
+	})
+
+	if !errors.Is(err, ErrInsufficientResults) {
+		t.Fatalf("expected ErrInsufficientResults, got %v", err)
+	}
+
+	var insufficient *InsufficientResultsError
+	if !errors.As(err, &insufficient) {
+		t.Fatalf("expected *InsufficientResultsError, got %T", err)
+	}
+
+	if insufficient.Want != 5 {
+		t.Errorf("expected Want=5, got %d", insufficient.Want)
+	}
+
+	if len(insufficient.Results) != 2 || len(results) != 2 {
+		t.Errorf("expected 2 partial results, got %d attached and %d returned", len(insufficient.Results), len(results))
+	}
+
+	_, err = searcher.DeepSearch(context.Background(), "test", SearchOptions{
+		MaxResults: 10,
+		MinResults: 5,
+	})
+	if !errors.Is(err, ErrInsufficientResults) {
+		t.Errorf("expected DeepSearch to return ErrInsufficientResults, got %v", err)
+	}
+}
+
+func TestSearch_MinResultsCountsFinalResults(t *testing.T) {
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing": &mockSearchEngine{name: "bing", results: []SearchResult{
+				{Title: "Bing 1", URL: "http://bing1.com", Engine: "bing"},
+				{Title: "Bing 2", URL: "http://bing2.com", Engine: "bing"},
+			}},
+		},
+		extractor: &mockContentExtractor{err: errors.New("unreadable")},
+	}
+
+	// Both results are found, but RequireContent drops them afterwards
+	opts := SearchOptions{MaxResults: 2, MinResults: 2, RequireContent: true}
+	results, err := searcher.Search(context.Background(), "test", opts)
+	if !errors.Is(err, ErrInsufficientResults) || len(results) != 0 {
+		t.Errorf("expected ErrInsufficientResults with no results from Search, got %d results: %v", len(results), err)
+	}
+
+	results, err = searcher.DeepSearch(context.Background(), "test", opts)
+	if !errors.Is(err, ErrInsufficientResults) || len(results) != 0 {
+		t.Errorf("expected ErrInsufficientResults with no results from DeepSearch, got %d results: %v", len(results), err)
+	}
+}
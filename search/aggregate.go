@@ -0,0 +1,60 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Orderings for AggregateOptions.SortBy
+const (
+	SortByRank   = "rank"
+	SortByLength = "length"
+	SortByDate   = "date"
+)
+
+// AggregateOptions configures SearchAndAggregateWithOptions
+type AggregateOptions struct {
+	MaxResults int
+	// PinnedURLs are moved to the top of the aggregation, in this order,
+	// when they are among the results
+	PinnedURLs []string
+	// FetchMissingPinned extracts pinned URLs the search didn't return and
+	// includes them
+	FetchMissingPinned bool
+	// SortBy orders the sections: SortByRank (the default) keeps the
+	// search order, SortByLength puts the longest extracted content first
+	// and SortByDate the newest. Pinned results still lead.
+	SortBy string
+	// RequireContent leaves out results whose content couldn't be
+	// extracted; see SearchOptions.RequireContent
+	RequireContent bool
+	// MaxTokens sizes the whole aggregate to fit this many tokens instead
+	// of cutting each result's content at 1500 characters; zero keeps the
+	// fixed cut
+	MaxTokens int
+	// TokenCounter counts tokens for MaxTokens; nil uses EstimateTokens
+	TokenCounter TokenCounter
+}
+
+// sortForAggregate orders results for aggregation. The sort is stable, so
+// ties keep their search order.
+func sortForAggregate(results []SearchResult, sortBy string) ([]SearchResult, error) {
+	sorted := append([]SearchResult(nil), results...)
+
+	switch sortBy {
+	case "", SortByRank:
+	case SortByLength:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return len(sorted[i].Content) > len(sorted[j].Content)
+		})
+	case SortByDate:
+		// Undated results go last
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Date().After(sorted[j].Date())
+		})
+	default:
+		return nil, fmt.Errorf("unsupported sort order %q: use %q, %q or %q", sortBy, SortByRank, SortByLength, SortByDate)
+	}
+
+	return sorted, nil
+}
@@ -0,0 +1,50 @@
+package search
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSortForAggregate(t *testing.T) {
+	now := time.Now()
+	results := []SearchResult{
+		{Title: "Short", Content: "Brief.", LastModified: now.Add(-48 * time.Hour)},
+		{Title: "Undated", Content: "A little more content here."},
+		{Title: "Long", Content: strings.Repeat("Substantive content. ", 50), LastModified: now.Add(-72 * time.Hour)},
+		{Title: "Recent", Content: "Fresh news.", LastModified: now},
+	}
+
+	titles := func(rs []SearchResult) string {
+		var names []string
+		for _, r := range rs {
+			names = append(names, r.Title)
+		}
+		return strings.Join(names, ",")
+	}
+
+	byLength, err := sortForAggregate(results, SortByLength)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if byLength[0].Title != "Long" {
+		t.Errorf("expected the longest content first, got %s", titles(byLength))
+	}
+	if !strings.HasPrefix(formatAggregate("q", byLength), "# Search Results for: q\n\n## 1. Long") {
+		t.Error("expected the longest content to be the first section")
+	}
+
+	byDate, _ := sortForAggregate(results, SortByDate)
+	if got := titles(byDate); got != "Recent,Short,Long,Undated" {
+		t.Errorf("expected newest first and undated last, got %s", got)
+	}
+
+	byRank, _ := sortForAggregate(results, "")
+	if got := titles(byRank); got != "Short,Undated,Long,Recent" {
+		t.Errorf("expected the default to keep search order, got %s", got)
+	}
+
+	if _, err := sortForAggregate(results, "popularity"); err == nil {
+		t.Error("expected an unknown sort order to fail")
+	}
+}
@@ -0,0 +1,33 @@
+package search
+
+import (
+	"time"
+
+	"github.com/liliang-cn/mcp-websearch-server/extraction"
+)
+
+// Content sources reported in SearchResult.ContentSource
+const (
+	ContentSourceLive    = "live"
+	ContentSourceArchive = "archive"
+)
+
+// defaultArchiveBase is prefixed to a page's URL to get its latest Wayback
+// Machine snapshot
+const defaultArchiveBase = "https://web.archive.org/web/"
+
+// needsArchive reports whether the live page couldn't be read, either
+// because extraction failed or because the server refused it, as paywalls
+// and bot walls usually do
+func needsArchive(r SearchResult) bool {
+	return r.ExtractError != "" || r.HTTPStatus >= 400
+}
+
+// applyArchiveContent replaces a result's content with its archived copy
+func applyArchiveContent(r *SearchResult, content string) {
+	r.Content = content
+	r.ContentHash = extraction.ContentHash(content)
+	r.ExtractError = ""
+	r.ExtractedAt = time.Now()
+	r.ContentSource = ContentSourceArchive
+}
@@ -0,0 +1,100 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// httpExtractor returns the response body as content, failing on error
+// statuses
+type httpExtractor struct {
+	client *http.Client
+}
+
+func (e *httpExtractor) ExtractContent(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+func TestMultiEngineSearcher_ArchiveFallback(t *testing.T) {
+	live := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "subscribe to read", http.StatusPaymentRequired)
+	}))
+	defer live.Close()
+
+	var archived string
+	archive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		archived = strings.TrimPrefix(r.URL.Path, "/web/")
+		fmt.Fprint(w, "archived article text")
+	}))
+	defer archive.Close()
+
+	newSearcher := func(archiveBase string) *multiEngineSearcher {
+		return &multiEngineSearcher{
+			engines: map[string]SearchEngine{
+				"bing": &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Paywalled", URL: live.URL + "/story"}}},
+			},
+			extractor:   &httpExtractor{client: http.DefaultClient},
+			archiveBase: archiveBase,
+		}
+	}
+
+	opts := SearchOptions{MaxResults: 1, ExtractContent: true}
+
+	results, err := newSearcher(archive.URL+"/web/").Search(context.Background(), "test", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := results[0]
+	if r.Content != "archived article text" {
+		t.Errorf("expected archived content, got %q", r.Content)
+	}
+	if r.ContentSource != ContentSourceArchive {
+		t.Errorf("expected content source %q, got %q", ContentSourceArchive, r.ContentSource)
+	}
+	if r.ExtractError != "" {
+		t.Errorf("expected extract error to be cleared, got %q", r.ExtractError)
+	}
+	if !strings.HasSuffix(archived, "/story") {
+		t.Errorf("expected archive to be asked for the page URL, got %q", archived)
+	}
+
+	// Without the option the failure is reported as is
+	results, err = newSearcher("").Search(context.Background(), "test", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Content != "" || results[0].ExtractError == "" {
+		t.Error("expected live failure to be kept when archive fallback is disabled")
+	}
+}
+
+func TestWithArchiveFallback(t *testing.T) {
+	if base := applySearcherOptions(nil).archiveBase(); base != "" {
+		t.Errorf("expected archive fallback to be off by default, got %q", base)
+	}
+
+	o := applySearcherOptions([]SearcherOption{WithArchiveFallback(true)})
+	if o.archiveBase() != defaultArchiveBase {
+		t.Errorf("expected %q, got %q", defaultArchiveBase, o.archiveBase())
+	}
+}
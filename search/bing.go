@@ -13,13 +13,15 @@ import (
 
 type bingSearchEngine struct {
 	client *http.Client
+	config browserEngineConfig
 }
 
-func NewBingSearchEngine() SearchEngine {
+func NewBingSearchEngine(opts ...BrowserEngineOption) SearchEngine {
 	return &bingSearchEngine{
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		config: newBrowserEngineConfig(opts...),
 	}
 }
 
@@ -30,7 +32,11 @@ func (b *bingSearchEngine) Name() string {
 func (b *bingSearchEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
 	searchURL := fmt.Sprintf("https://www.bing.com/search?q=%s", url.QueryEscape(query))
 
-	allocCtx, cancel := chromedp.NewContext(ctx)
+	if b.config.err != nil {
+		return nil, b.config.err
+	}
+
+	allocCtx, cancel := b.config.browserContext(ctx)
 	defer cancel()
 
 	var results []SearchResult
@@ -13,15 +13,29 @@ import (
 
 type bingGoQueryEngine struct {
 	client *http.Client
+	config engineConfig
 }
 
-func NewBingGoQueryEngine() SearchEngine {
+// DefaultBingSelectors are the selectors used to parse Bing results pages
+var DefaultBingSelectors = Selectors{
+	Result:  ".b_algo, li.b_algo",
+	Title:   []string{"h2 a", "a"},
+	Link:    []string{"h2 a", "a"},
+	Snippet: []string{".b_caption p", ".b_caption", "p"},
+
+	TotalCount: ".sb_count",
+	Sitelinks:  ".b_deep li, .b_algoSlinks li",
+}
+
+func NewBingGoQueryEngine(opts ...EngineOption) SearchEngine {
+	config := newEngineConfig(DefaultBingSelectors, opts)
 	return &bingGoQueryEngine{
-		client: &http.Client{
+		client: config.applyProxy(&http.Client{
 			Timeout: 10 * time.Second,
 			// Set user agent to avoid blocking
 			Transport: &http.Transport{},
-		},
+		}),
+		config: config,
 	}
 }
 
@@ -30,72 +44,109 @@ func (b *bingGoQueryEngine) Name() string {
 }
 
 func (b *bingGoQueryEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
-	searchURL := fmt.Sprintf("https://www.bing.com/search?q=%s", url.QueryEscape(query))
+	return b.SearchQuery(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+// SearchQuery is Search with the full set of per-search parameters
+func (b *bingGoQueryEngine) SearchQuery(ctx context.Context, q EngineQuery) ([]SearchResult, error) {
+	results, _, err := b.search(ctx, q)
+	return results, err
+}
+
+// SearchWithStats is Search that also reports the total-results estimate
+// shown on the results page
+func (b *bingGoQueryEngine) SearchWithStats(ctx context.Context, query string, maxResults int) ([]SearchResult, SearchStats, error) {
+	return b.search(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+func (b *bingGoQueryEngine) search(ctx context.Context, q EngineQuery) ([]SearchResult, SearchStats, error) {
+	if b.config.err != nil {
+		return nil, SearchStats{}, b.config.err
+	}
+	if q.Vertical == VerticalNews {
+		results, err := b.searchNews(ctx, q)
+		return results, SearchStats{}, err
+	}
+
+	searchURL := fmt.Sprintf("https://www.bing.com/search?q=%s", url.QueryEscape(q.Query))
+	if q.Offset > 0 {
+		// first is the 1-based position of the first result on the page
+		searchURL += fmt.Sprintf("&first=%d", q.Offset+1)
+	}
+	if q.hasDateRange() {
+		// ez5 takes the range as days since the Unix epoch
+		from, to := q.dateRange()
+		searchURL += "&filters=" + url.QueryEscape(fmt.Sprintf(`ex1:"ez5_%d_%d"`, from.Unix()/86400, to.Unix()/86400))
+	}
+	if q.Language != "" {
+		searchURL += "&setlang=" + url.QueryEscape(strings.ToLower(q.Language))
+	}
+	if q.Region != "" {
+		searchURL += "&cc=" + url.QueryEscape(strings.ToUpper(q.Region))
+	}
+	searchURL += "&adlt=" + string(q.safeSearch())
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
-		return nil, err
+		return nil, SearchStats{}, err
 	}
 	
 	// Set headers to appear more like a real browser
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+	req.Header.Set("User-Agent", b.config.userAgent())
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
+	req.Header.Set("Accept-Language", q.acceptLanguage("en-US,en;q=0.5"))
 	
-	resp, err := b.client.Do(req)
+	resp, err := fetchResultsPage(b.client, req, b.Name())
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch Bing results: %w", err)
+		return nil, SearchStats{}, err
 	}
 	defer resp.Body.Close()
 	
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, err := parseLimitedBody(resp.Body, b.config.maxBodySize, searchURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+		return nil, SearchStats{}, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 	
+	results := b.parseResults(doc, q.MaxResults)
+	if len(results) == 0 && captchaPage(doc) {
+		return nil, SearchStats{}, &BlockedError{Engine: b.Name(), URL: searchURL}
+	}
+	b.resolveRedirects(ctx, results)
+	return results, parseSearchStats(doc, b.config.selectors), nil
+}
+
+func (b *bingGoQueryEngine) parseResults(doc *goquery.Document, maxResults int) []SearchResult {
+	sel := b.config.selectors
 	var results []SearchResult
 	
-	// Try multiple selectors for Bing results
-	doc.Find(".b_algo, li.b_algo").Each(func(i int, s *goquery.Selection) {
-		if i >= maxResults {
+	doc.Find(sel.Result).Each(func(i int, s *goquery.Selection) {
+		if len(results) >= maxResults || i < b.config.skipFirst {
 			return
 		}
 		
-		// Extract title and link
-		titleElem := s.Find("h2 a").First()
-		if titleElem.Length() == 0 {
-			titleElem = s.Find("a").First()
-		}
-		
-		title := strings.TrimSpace(titleElem.Text())
-		link, _ := titleElem.Attr("href")
-		
-		// Extract snippet
-		snippet := strings.TrimSpace(s.Find(".b_caption p").Text())
-		if snippet == "" {
-			snippet = strings.TrimSpace(s.Find(".b_caption").Text())
-		}
-		if snippet == "" {
-			snippet = strings.TrimSpace(s.Find("p").First().Text())
-		}
+		title := firstText(s, sel.Title)
+		link := firstAttr(s, sel.Link, "href")
+		snippet := firstText(s, sel.Snippet)
 		
 		if link != "" && title != "" {
-			// Clean up Bing redirect URLs if needed
-			if strings.Contains(link, "bing.com/ck/a") {
-				// For now, keep the redirect URL
-				// In production, you might want to follow the redirect
+			// Replace Bing's click-tracking redirects with their destination
+			link = decodeBingRedirect(link)
+			subResults := parseSitelinks(s, sel.Sitelinks, link, b.Name())
+			for j := range subResults {
+				subResults[j].URL = decodeBingRedirect(subResults[j].URL)
 			}
-			
+
 			results = append(results, SearchResult{
-				Title:   title,
-				URL:     link,
-				Snippet: snippet,
-				Engine:  b.Name(),
+				Title:      title,
+				URL:        link,
+				Snippet:    snippet,
+				Engine:     b.Name(),
+				SubResults: subResults,
 			})
 		}
 	})
 	
-	// If no results found with .b_algo, try other selectors
+	// If no results found with the result selector, try other selectors
 	if len(results) == 0 {
 		doc.Find("#b_results h2").Each(func(i int, s *goquery.Selection) {
 			if i >= maxResults {
@@ -109,7 +160,7 @@ func (b *bingGoQueryEngine) Search(ctx context.Context, query string, maxResults
 			if link != "" && title != "" {
 				results = append(results, SearchResult{
 					Title:   title,
-					URL:     link,
+					URL:     decodeBingRedirect(link),
 					Snippet: "",
 					Engine:  b.Name(),
 				})
@@ -117,5 +168,5 @@ func (b *bingGoQueryEngine) Search(ctx context.Context, query string, maxResults
 		})
 	}
 	
-	return results, nil
+	return results
 }
\ No newline at end of file
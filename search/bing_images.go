@@ -0,0 +1,103 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ImageEngines are the engines with image search, in the order searchers
+// try them
+var ImageEngines = []string{"bing"}
+
+// bingImageMeta is the JSON Bing stores in each image link's m attribute
+type bingImageMeta struct {
+	MediaURL string `json:"murl"`
+	ThumbURL string `json:"turl"`
+	PageURL  string `json:"purl"`
+	Title    string `json:"t"`
+}
+
+// imageSizePattern matches the "1920 x 1080" size shown under an image
+var imageSizePattern = regexp.MustCompile(`(\d+)\s*[x×]\s*(\d+)`)
+
+// SearchImages searches Bing Images
+func (b *bingGoQueryEngine) SearchImages(ctx context.Context, query string, maxResults int) ([]ImageResult, error) {
+	if b.config.err != nil {
+		return nil, b.config.err
+	}
+
+	searchURL := fmt.Sprintf("https://www.bing.com/images/search?q=%s&form=HDRSC2", url.QueryEscape(query))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", b.config.userAgent())
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
+	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Bing Images results: %w", err)
+	}
+	defer resp.Body.Close()
+
+	doc, err := parseLimitedBody(resp.Body, b.config.maxBodySize, searchURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	return parseBingImages(doc, maxResults), nil
+}
+
+// parseBingImages reads the results from each a.iusc link's m attribute.
+// Entries whose JSON doesn't parse or lacks the image URL are skipped, so
+// a change to the format loses those images rather than the whole search.
+func parseBingImages(doc *goquery.Document, maxResults int) []ImageResult {
+	var results []ImageResult
+
+	doc.Find("a.iusc").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if len(results) >= maxResults {
+			return false
+		}
+
+		var meta bingImageMeta
+		if err := json.Unmarshal([]byte(s.AttrOr("m", "")), &meta); err != nil || meta.MediaURL == "" {
+			return true
+		}
+
+		image := ImageResult{
+			Title:        strings.TrimSpace(meta.Title),
+			ImageURL:     meta.MediaURL,
+			ThumbnailURL: meta.ThumbURL,
+			SourceURL:    meta.PageURL,
+		}
+		if m := imageSizePattern.FindStringSubmatch(s.Closest(".imgpt").Find(".img_info .nowrap").Text()); m != nil {
+			image.Width, _ = strconv.Atoi(m[1])
+			image.Height, _ = strconv.Atoi(m[2])
+		}
+
+		results = append(results, image)
+		return true
+	})
+
+	return results
+}
+
+// imageSearcher returns engine's image search, looking through a
+// fallbackEngine to its primary
+func imageSearcher(engine SearchEngine) (ImageSearcher, bool) {
+	if f, ok := engine.(*fallbackEngine); ok {
+		engine = f.primary
+	}
+	images, ok := engine.(ImageSearcher)
+	return images, ok
+}
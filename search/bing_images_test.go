@@ -0,0 +1,77 @@
+package search
+
+import (
+	"context"
+	"html"
+	"strings"
+	"testing"
+)
+
+func bingImagesFixture() string {
+	item := func(m, size string) string {
+		return `<li><div class="imgpt"><a class="iusc" m="` + html.EscapeString(m) + `" href="#"></a>` +
+			`<div class="img_info"><span class="nowrap">` + size + `</span></div></div></li>`
+	}
+	return `<html><body><ul>` +
+		item(`{"murl":"https://img.example.com/gopher.png","turl":"https://tse.example.com/th?id=1","purl":"https://blog.example.com/gopher","t":"The Go gopher"}`, "1920 x 1080 · png") +
+		item(`{"murl":`, "") +
+		item(`{"turl":"https://tse.example.com/th?id=2","t":"No image URL"}`, "") +
+		item(`{"murl":"https://img.example.com/logo.svg","turl":"https://tse.example.com/th?id=3","purl":"https://go.dev","t":"Go logo","extra":{"new":true}}`, "") +
+		`</ul></body></html>`
+}
+
+func TestBingGoQuery_SearchImages(t *testing.T) {
+	engine := NewBingGoQueryEngine()
+	transport := &fixtureTransport{body: bingImagesFixture()}
+	engine.(*bingGoQueryEngine).client.Transport = transport
+
+	images, err := engine.(ImageSearcher).SearchImages(context.Background(), "gopher", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The malformed and incomplete entries are skipped
+	if len(images) != 2 {
+		t.Fatalf("expected 2 images, got %d: %+v", len(images), images)
+	}
+	want := ImageResult{
+		Title:        "The Go gopher",
+		ImageURL:     "https://img.example.com/gopher.png",
+		ThumbnailURL: "https://tse.example.com/th?id=1",
+		SourceURL:    "https://blog.example.com/gopher",
+		Width:        1920,
+		Height:       1080,
+	}
+	if images[0] != want {
+		t.Errorf("expected %+v, got %+v", want, images[0])
+	}
+	if images[1].ImageURL != "https://img.example.com/logo.svg" || images[1].Width != 0 {
+		t.Errorf("unexpected second image: %+v", images[1])
+	}
+
+	if len(transport.urls) != 1 || !strings.HasPrefix(transport.urls[0], "https://www.bing.com/images/search?q=gopher") {
+		t.Errorf("expected a Bing Images request, got %v", transport.urls)
+	}
+}
+
+func TestSearcher_SearchImages(t *testing.T) {
+	bing := NewBingGoQueryEngine()
+	bing.(*bingGoQueryEngine).client.Transport = &fixtureTransport{body: bingImagesFixture()}
+
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{
+		"bing": NewFallbackEngine(bing, &mockSearchEngine{name: "bing"}),
+	}, nil)
+
+	images, err := searcher.(ImageSearcher).SearchImages(context.Background(), "gopher", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(images) != 1 {
+		t.Errorf("expected 1 image, got %d", len(images))
+	}
+
+	searcher.(interface{ DisableEngine(string) error }).DisableEngine("bing")
+	if _, err := searcher.(ImageSearcher).SearchImages(context.Background(), "gopher", 1); err == nil {
+		t.Error("expected an error with the image engine disabled")
+	}
+}
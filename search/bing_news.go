@@ -0,0 +1,113 @@
+package search
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// bingNewsFeed is the RSS document Bing News serves for format=rss
+type bingNewsFeed struct {
+	Items []struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+		PubDate     string `xml:"pubDate"`
+		// Source is in Bing's News: namespace
+		Source string `xml:"Source"`
+	} `xml:"channel>item"`
+}
+
+// searchNews queries Bing News through its RSS feed, which carries each
+// story's publication date and publisher
+func (b *bingGoQueryEngine) searchNews(ctx context.Context, q EngineQuery) ([]SearchResult, error) {
+	searchURL := fmt.Sprintf("https://www.bing.com/news/search?q=%s&format=rss", url.QueryEscape(q.Query))
+	if q.Offset > 0 {
+		searchURL += fmt.Sprintf("&first=%d", q.Offset+1)
+	}
+	if q.Language != "" {
+		searchURL += "&setlang=" + url.QueryEscape(strings.ToLower(q.Language))
+	}
+	if q.Region != "" {
+		searchURL += "&cc=" + url.QueryEscape(strings.ToUpper(q.Region))
+	}
+	searchURL += "&adlt=" + string(q.safeSearch())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", b.config.userAgent())
+	req.Header.Set("Accept", "application/rss+xml,application/xml;q=0.9,*/*;q=0.8")
+	req.Header.Set("Accept-Language", q.acceptLanguage("en-US,en;q=0.5"))
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Bing News results: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bing news returned status %d", resp.StatusCode)
+	}
+
+	data, err := readLimitedBody(resp.Body, b.config.maxBodySize, searchURL)
+	if err != nil {
+		return nil, err
+	}
+	return parseBingNews(data, q)
+}
+
+// parseBingNews turns a Bing News RSS feed into results, unwrapping Bing's
+// click-tracking links to the article URLs
+func parseBingNews(data []byte, q EngineQuery) ([]SearchResult, error) {
+	var feed bingNewsFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse Bing News feed: %w", err)
+	}
+
+	var results []SearchResult
+	for _, item := range feed.Items {
+		if len(results) >= q.MaxResults {
+			break
+		}
+
+		link := bingNewsLink(strings.TrimSpace(item.Link))
+		title := strings.TrimSpace(item.Title)
+		if link == "" || title == "" {
+			continue
+		}
+
+		published, _ := time.Parse(time.RFC1123, strings.TrimSpace(item.PubDate))
+		if !q.inDateRange(published) {
+			continue
+		}
+
+		results = append(results, SearchResult{
+			Title:       title,
+			URL:         link,
+			Snippet:     strings.TrimSpace(item.Description),
+			Engine:      "bing",
+			PublishedAt: published,
+			Source:      strings.TrimSpace(item.Source),
+		})
+	}
+	return results, nil
+}
+
+// bingNewsLink returns the article URL carried in the url parameter of a
+// Bing News apiclick.aspx link, or link itself for a direct link
+func bingNewsLink(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || !strings.HasSuffix(u.Host, "bing.com") {
+		return link
+	}
+	if target := u.Query().Get("url"); target != "" {
+		return target
+	}
+	return link
+}
@@ -0,0 +1,103 @@
+package search
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// isBingRedirect reports whether link is one of Bing's /ck/a click-tracking
+// redirects
+func isBingRedirect(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	return isBingHost(u.Hostname()) && u.Path == "/ck/a"
+}
+
+// isBingHost reports whether host is bing.com or one of its subdomains
+func isBingHost(host string) bool {
+	return hostMatches(strings.ToLower(host), []string{"bing.com"})
+}
+
+// decodeBingRedirect returns the destination of a Bing /ck/a redirect,
+// which Bing carries in the u parameter as "a1" followed by the URL in
+// unpadded URL-safe base64. Other links, and redirects it can't decode,
+// are returned unchanged.
+func decodeBingRedirect(link string) string {
+	if !isBingRedirect(link) {
+		return link
+	}
+	u, _ := url.Parse(link)
+
+	encoded, ok := strings.CutPrefix(u.Query().Get("u"), "a1")
+	if !ok {
+		return link
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return link
+	}
+
+	target, err := url.Parse(string(decoded))
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return link
+	}
+	return target.String()
+}
+
+// resolveRedirects replaces the Bing redirects left in results and their
+// sitelinks, the ones decodeBingRedirect couldn't decode, with where a HEAD
+// request following them ends up. The requests run concurrently; a
+// redirect that fails or ends on Bing is kept.
+func (b *bingGoQueryEngine) resolveRedirects(ctx context.Context, results []SearchResult) {
+	var pending []*SearchResult
+	for i := range results {
+		if isBingRedirect(results[i].URL) {
+			pending = append(pending, &results[i])
+		}
+		for j := range results[i].SubResults {
+			if isBingRedirect(results[i].SubResults[j].URL) {
+				pending = append(pending, &results[i].SubResults[j])
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, r := range pending {
+		wg.Add(1)
+		go func(r *SearchResult) {
+			defer wg.Done()
+			if target, ok := b.followRedirect(ctx, r.URL); ok {
+				r.URL = target
+			}
+		}(r)
+	}
+	wg.Wait()
+}
+
+// followRedirect sends a HEAD request for link and returns the URL the
+// client's redirects lead to, if that is off Bing
+func (b *bingGoQueryEngine) followRedirect(ctx context.Context, link string) (string, bool) {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", link, nil)
+	if err != nil {
+		return "", false
+	}
+	req.Header.Set("User-Agent", b.config.userAgent())
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", false
+	}
+	resp.Body.Close()
+
+	final := resp.Request.URL
+	if isBingHost(final.Hostname()) {
+		return "", false
+	}
+	return final.String(), true
+}
@@ -0,0 +1,112 @@
+package search
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// bingClickURL returns a Bing /ck/a redirect to target
+func bingClickURL(target string) string {
+	return "https://www.bing.com/ck/a?!&&p=abc&ptn=3&u=a1" + base64.RawURLEncoding.EncodeToString([]byte(target)) + "&ntb=1"
+}
+
+func TestDecodeBingRedirect(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{"redirect", bingClickURL("https://example.com/article?id=1"), "https://example.com/article?id=1"},
+		{"padded", strings.Replace(bingClickURL("https://go.dev/"), "&ntb", "%3D%3D&ntb", 1), "https://go.dev/"},
+		{"plain link", "https://example.com/page", "https://example.com/page"},
+		{"no a1 prefix", "https://www.bing.com/ck/a?u=aHR0cHM6Ly9leGFtcGxlLmNvbQ", "https://www.bing.com/ck/a?u=aHR0cHM6Ly9leGFtcGxlLmNvbQ"},
+		{"bad base64", "https://www.bing.com/ck/a?u=a1!!!", "https://www.bing.com/ck/a?u=a1!!!"},
+		{"not http", bingClickURL("javascript:alert(1)"), bingClickURL("javascript:alert(1)")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeBingRedirect(tt.link); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// redirectTransport redirects HEAD requests for Bing URLs to location and
+// answers everything else with 200
+type redirectTransport struct {
+	location string
+	methods  []string
+}
+
+func (r *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.methods = append(r.methods, req.Method)
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}
+	if isBingHost(req.URL.Hostname()) && r.location != "" {
+		resp.StatusCode = http.StatusFound
+		resp.Header.Set("Location", r.location)
+	}
+	return resp, nil
+}
+
+func TestBingGoQuery_ResolveRedirects(t *testing.T) {
+	engine := NewBingGoQueryEngine().(*bingGoQueryEngine)
+	transport := &redirectTransport{location: "https://example.com/resolved"}
+	engine.client.Transport = transport
+
+	results := []SearchResult{
+		{URL: "https://www.bing.com/ck/a?!&&p=opaque"},
+		{URL: "https://example.com/direct"},
+	}
+	engine.resolveRedirects(context.Background(), results)
+
+	if results[0].URL != "https://example.com/resolved" {
+		t.Errorf("expected the redirect to be followed, got %s", results[0].URL)
+	}
+	if results[1].URL != "https://example.com/direct" {
+		t.Errorf("expected a direct link to be left alone, got %s", results[1].URL)
+	}
+	if len(transport.methods) == 0 || transport.methods[0] != "HEAD" {
+		t.Errorf("expected a HEAD request, got %v", transport.methods)
+	}
+
+	// Sitelinks are resolved too
+	results = []SearchResult{{URL: "https://example.com/direct", SubResults: []SearchResult{{URL: "https://www.bing.com/ck/a?!&&p=sitelink"}}}}
+	engine.resolveRedirects(context.Background(), results)
+	if results[0].SubResults[0].URL != "https://example.com/resolved" {
+		t.Errorf("expected the sitelink redirect to be followed, got %s", results[0].SubResults[0].URL)
+	}
+
+	// A redirect that stays on Bing is kept
+	transport.location = ""
+	results = []SearchResult{{URL: "https://www.bing.com/ck/a?!&&p=opaque"}}
+	engine.resolveRedirects(context.Background(), results)
+	if results[0].URL != "https://www.bing.com/ck/a?!&&p=opaque" {
+		t.Errorf("expected the unresolved redirect to be kept, got %s", results[0].URL)
+	}
+}
+
+func TestBingGoQuery_DecodesResultRedirects(t *testing.T) {
+	engine := NewBingGoQueryEngine()
+	engine.(*bingGoQueryEngine).client.Transport = &fixtureTransport{body: `<html><body><ol id="b_results">
+		<li class="b_algo"><h2><a href="` + bingClickURL("https://example.com/article") + `">Article</a></h2>
+		<div class="b_caption"><p>Snippet</p></div></li>
+	</ol></body></html>`}
+
+	results, err := engine.Search(context.Background(), "query", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].URL != "https://example.com/article" {
+		t.Errorf("expected the decoded destination, got %+v", results)
+	}
+}
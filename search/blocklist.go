@@ -0,0 +1,40 @@
+package search
+
+import "sync"
+
+// engineBlocklist tracks engines disabled at runtime. The zero value has
+// nothing disabled and it is safe for concurrent use.
+type engineBlocklist struct {
+	mu    sync.RWMutex
+	names map[string]bool
+}
+
+func (b *engineBlocklist) disable(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.names == nil {
+		b.names = make(map[string]bool)
+	}
+	b.names[normalizeEngineName(name)] = true
+}
+
+func (b *engineBlocklist) enable(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.names, normalizeEngineName(name))
+}
+
+// lookup returns the named engine unless it is unknown or disabled
+func (b *engineBlocklist) lookup(engines map[string]SearchEngine, name string) (SearchEngine, bool) {
+	name = normalizeEngineName(name)
+
+	b.mu.RLock()
+	disabled := b.names[name]
+	b.mu.RUnlock()
+	if disabled {
+		return nil, false
+	}
+
+	engine, ok := engines[name]
+	return engine, ok
+}
@@ -0,0 +1,57 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMultiEngineSearcher_DisableEngine(t *testing.T) {
+	bing := &mockSearchEngine{name: "bing", results: []SearchResult{{URL: "http://bing.example.com", Engine: "bing"}}}
+	brave := &mockSearchEngine{name: "brave", results: []SearchResult{{URL: "http://brave.example.com", Engine: "brave"}}}
+
+	searcher := &multiEngineSearcher{
+		engines:   map[string]SearchEngine{"bing": bing, "brave": brave},
+		extractor: &mockContentExtractor{},
+	}
+
+	if err := searcher.DisableEngine("Bing"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := searcher.DisableEngine("bnig"); !errors.Is(err, ErrUnknownEngine) {
+		t.Errorf("expected ErrUnknownEngine for a misspelt engine, got %v", err)
+	}
+
+	ctx := context.Background()
+	results, err := searcher.Search(ctx, "test", SearchOptions{MaxResults: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Engine != "brave" {
+		t.Errorf("expected brave to be selected, got %s", results[0].Engine)
+	}
+
+	// Explicitly requested and deep searches skip it too
+	searcher.Search(ctx, "test", SearchOptions{MaxResults: 1, Engines: []string{"bing"}})
+	searcher.DeepSearch(ctx, "test", SearchOptions{MaxResults: 2})
+	brave.err = errors.New("blocked")
+	searcher.Search(ctx, "test", SearchOptions{MaxResults: 1})
+	if bing.calls != 0 {
+		t.Fatalf("expected disabled engine not to be queried, got %d calls", bing.calls)
+	}
+
+	if err := searcher.EnableEngine("bing"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := searcher.EnableEngine("altavista"); !errors.Is(err, ErrUnknownEngine) {
+		t.Errorf("expected ErrUnknownEngine for an unregistered engine, got %v", err)
+	}
+	brave.err = nil
+	results, err = searcher.Search(ctx, "test", SearchOptions{MaxResults: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Engine != "bing" || bing.calls != 1 {
+		t.Errorf("expected bing to be queried again after EnableEngine")
+	}
+}
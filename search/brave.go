@@ -14,13 +14,15 @@ import (
 
 type braveSearchEngine struct {
 	client *http.Client
+	config browserEngineConfig
 }
 
-func NewBraveSearchEngine() SearchEngine {
+func NewBraveSearchEngine(opts ...BrowserEngineOption) SearchEngine {
 	return &braveSearchEngine{
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		config: newBrowserEngineConfig(opts...),
 	}
 }
 
@@ -31,7 +33,11 @@ func (b *braveSearchEngine) Name() string {
 func (b *braveSearchEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
 	searchURL := fmt.Sprintf("https://search.brave.com/search?q=%s", url.QueryEscape(query))
 
-	allocCtx, cancel := chromedp.NewContext(ctx)
+	if b.config.err != nil {
+		return nil, b.config.err
+	}
+
+	allocCtx, cancel := b.config.browserContext(ctx)
 	defer cancel()
 
 	var results []SearchResult
@@ -73,6 +79,15 @@ func (b *braveSearchEngine) Search(ctx context.Context, query string, maxResults
 			break
 		}
 
+		// Each node takes several round trips to the browser, so stop as
+		// soon as the search is cancelled and keep what was scraped
+		if ctx.Err() != nil {
+			if len(results) == 0 {
+				return nil, ctx.Err()
+			}
+			break
+		}
+
 		var title, link, snippet string
 
 		// Try various selectors for title
@@ -13,13 +13,27 @@ import (
 
 type braveGoQueryEngine struct {
 	client *http.Client
+	config engineConfig
 }
 
-func NewBraveGoQueryEngine() SearchEngine {
+// DefaultBraveSelectors are the selectors used to parse Brave results pages
+var DefaultBraveSelectors = Selectors{
+	Result:  ".snippet, .result-card, article[data-type='web']",
+	Title:   []string{".snippet-title", "h3 a", "a[data-testid='result-title']", "a"},
+	Link:    []string{".snippet-title", "h3 a", "a[data-testid='result-title']", "a[href]"},
+	Snippet: []string{".snippet-description", "[data-testid='result-description']", ".desc", "p"},
+}
+
+// braveResultsPerPage is how many web results a Brave results page holds
+const braveResultsPerPage = 20
+
+func NewBraveGoQueryEngine(opts ...EngineOption) SearchEngine {
+	config := newEngineConfig(DefaultBraveSelectors, opts)
 	return &braveGoQueryEngine{
-		client: &http.Client{
+		client: config.applyProxy(&http.Client{
 			Timeout: 10 * time.Second,
-		},
+		}),
+		config: config,
 	}
 }
 
@@ -28,71 +42,85 @@ func (b *braveGoQueryEngine) Name() string {
 }
 
 func (b *braveGoQueryEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
-	searchURL := fmt.Sprintf("https://search.brave.com/search?q=%s", url.QueryEscape(query))
+	return b.SearchQuery(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+// SearchQuery is Search with the full set of per-search parameters
+func (b *braveGoQueryEngine) SearchQuery(ctx context.Context, q EngineQuery) ([]SearchResult, error) {
+	results, _, err := b.search(ctx, q)
+	return results, err
+}
+
+// SearchWithStats is Search that also reports the total-results estimate
+// shown on the results page
+func (b *braveGoQueryEngine) SearchWithStats(ctx context.Context, query string, maxResults int) ([]SearchResult, SearchStats, error) {
+	return b.search(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+func (b *braveGoQueryEngine) search(ctx context.Context, q EngineQuery) ([]SearchResult, SearchStats, error) {
+	if b.config.err != nil {
+		return nil, SearchStats{}, b.config.err
+	}
+
+	// Brave pages by page number, so skip into the page for offsets that
+	// don't fall on a page boundary
+	page, skip := q.Offset/braveResultsPerPage, q.Offset%braveResultsPerPage
+	searchURL := fmt.Sprintf("https://search.brave.com/search?q=%s", url.QueryEscape(q.Query))
+	if page > 0 {
+		searchURL += fmt.Sprintf("&offset=%d", page)
+	}
+	if q.hasDateRange() {
+		from, to := q.dateRange()
+		searchURL += "&tf=" + from.Format("2006-01-02") + "to" + to.Format("2006-01-02")
+	}
+	if q.Region != "" {
+		searchURL += "&country=" + url.QueryEscape(strings.ToLower(q.Region))
+	}
+	if q.Language != "" {
+		searchURL += "&search_lang=" + url.QueryEscape(strings.ToLower(q.Language))
+	}
+	searchURL += "&safesearch=" + string(q.safeSearch())
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
-		return nil, err
+		return nil, SearchStats{}, err
 	}
 	
 	// Set headers to appear more like a real browser
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+	req.Header.Set("User-Agent", b.config.userAgent())
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
+	req.Header.Set("Accept-Language", q.acceptLanguage("en-US,en;q=0.5"))
 	
-	resp, err := b.client.Do(req)
+	resp, err := fetchResultsPage(b.client, req, b.Name())
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch Brave results: %w", err)
+		return nil, SearchStats{}, err
 	}
 	defer resp.Body.Close()
 	
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, err := parseLimitedBody(resp.Body, b.config.maxBodySize, searchURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+		return nil, SearchStats{}, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 	
+	results := b.parseResults(doc, skip+q.MaxResults)
+	if len(results) == 0 && captchaPage(doc) {
+		return nil, SearchStats{}, &BlockedError{Engine: b.Name(), URL: searchURL}
+	}
+	return skipResults(results, skip, q.MaxResults), parseSearchStats(doc, b.config.selectors), nil
+}
+
+func (b *braveGoQueryEngine) parseResults(doc *goquery.Document, maxResults int) []SearchResult {
+	sel := b.config.selectors
 	var results []SearchResult
 	
-	// Try multiple selectors for Brave results
-	doc.Find(".snippet, .result-card, article[data-type='web']").Each(func(i int, s *goquery.Selection) {
-		if i >= maxResults {
+	doc.Find(sel.Result).Each(func(i int, s *goquery.Selection) {
+		if len(results) >= maxResults || i < b.config.skipFirst {
 			return
 		}
 		
-		// Extract title and link
-		var title, link string
-		
-		// Try different title selectors
-		titleElem := s.Find(".snippet-title").First()
-		if titleElem.Length() == 0 {
-			titleElem = s.Find("h3 a").First()
-		}
-		if titleElem.Length() == 0 {
-			titleElem = s.Find("a[data-testid='result-title']").First()
-		}
-		if titleElem.Length() == 0 {
-			titleElem = s.Find("a").First()
-		}
-		
-		title = strings.TrimSpace(titleElem.Text())
-		link, _ = titleElem.Attr("href")
-		
-		// If link is from a parent element
-		if link == "" {
-			link, _ = s.Find("a[href]").First().Attr("href")
-		}
-		
-		// Extract snippet
-		snippet := strings.TrimSpace(s.Find(".snippet-description").Text())
-		if snippet == "" {
-			snippet = strings.TrimSpace(s.Find("[data-testid='result-description']").Text())
-		}
-		if snippet == "" {
-			snippet = strings.TrimSpace(s.Find(".desc").Text())
-		}
-		if snippet == "" {
-			snippet = strings.TrimSpace(s.Find("p").First().Text())
-		}
+		title := firstText(s, sel.Title)
+		link := firstAttr(s, sel.Link, "href")
+		snippet := firstText(s, sel.Snippet)
 		
 		if link != "" && title != "" {
 			// Ensure link has protocol
@@ -101,10 +129,11 @@ func (b *braveGoQueryEngine) Search(ctx context.Context, query string, maxResult
 			}
 			
 			results = append(results, SearchResult{
-				Title:   title,
-				URL:     link,
-				Snippet: snippet,
-				Engine:  b.Name(),
+				Title:      title,
+				URL:        link,
+				Snippet:    snippet,
+				Engine:     b.Name(),
+				SubResults: parseSitelinks(s, sel.Sitelinks, link, b.Name()),
 			})
 		}
 	})
@@ -135,5 +164,5 @@ func (b *braveGoQueryEngine) Search(ctx context.Context, query string, maxResult
 		})
 	}
 	
-	return results, nil
+	return results
 }
\ No newline at end of file
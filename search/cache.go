@@ -0,0 +1,163 @@
+package search
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachingSearcher decorates a MultiEngineSearcher with an in-memory cache,
+// so repeating a query within the TTL doesn't hit the engines again. The
+// least recently used entry is evicted once maxEntries is reached. It is
+// safe for concurrent use.
+type CachingSearcher struct {
+	inner      MultiEngineSearcher
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // most recently used at the front
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key     string
+	results []SearchResult
+	expires time.Time
+}
+
+// NewCachingSearcher wraps inner with a cache whose entries live for ttl.
+// A maxEntries of zero or less leaves the cache unbounded.
+func NewCachingSearcher(inner MultiEngineSearcher, ttl time.Duration, maxEntries int) *CachingSearcher {
+	return &CachingSearcher{
+		inner:      inner,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+func (c *CachingSearcher) Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	return c.cached("search", query, opts, func() ([]SearchResult, error) {
+		return c.inner.Search(ctx, query, opts)
+	})
+}
+
+func (c *CachingSearcher) DeepSearch(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	return c.cached("deep", query, opts, func() ([]SearchResult, error) {
+		return c.inner.DeepSearch(ctx, query, opts)
+	})
+}
+
+// Len returns the number of entries in the cache, including expired ones
+// not yet evicted
+func (c *CachingSearcher) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// cached returns the cached results for the search, calling fetch on a
+// miss. Failed searches are not cached.
+func (c *CachingSearcher) cached(mode, query string, opts SearchOptions, fetch func() ([]SearchResult, error)) ([]SearchResult, error) {
+	key := cacheKey(mode, query, opts)
+
+	if results, ok := c.get(key); ok {
+		return results, nil
+	}
+
+	results, err := fetch()
+	if err != nil {
+		return results, err
+	}
+
+	c.put(key, results)
+	return copyResults(results), nil
+}
+
+func (c *CachingSearcher) get(key string) ([]SearchResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return copyResults(entry.results), true
+}
+
+func (c *CachingSearcher) put(key string, results []SearchResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, results: copyResults(results), expires: c.now().Add(c.ttl)}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// cacheKey hashes every option that changes what a search returns; only
+// Timeout is left out. Engine names and domains are sorted so their order
+// doesn't matter.
+func cacheKey(mode, query string, opts SearchOptions) string {
+	engines := make([]string, len(opts.Engines))
+	for i, name := range opts.Engines {
+		engines[i] = strings.ToLower(name)
+	}
+	sort.Strings(engines)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%d\x00%t\x00%d\x00%s\x00%s\x00%d\x00%d\x00%s\x00%s",
+		mode, query, strings.Join(engines, ","), opts.MaxResults, opts.ExtractContent, opts.Offset,
+		strings.ToLower(opts.Language), strings.ToLower(opts.Region), opts.After.Unix(), opts.Before.Unix(), opts.SafeSearch, opts.Vertical)
+	fmt.Fprintf(h, "\x00%d\x00%d\x00%d\x00%s\x00%t\x00%t\x00%t\x00%t\x00%t\x00%t\x00%d",
+		opts.MaxEngines, opts.MinResults, opts.MaxConsecutiveSameDomain, opts.Strategy, opts.Rank, opts.RedactPII,
+		opts.CollapseDuplicateTitles, opts.ReplaceTranslatedSnippets, opts.Summarize, opts.RequireContent, opts.ExtractConcurrency)
+	fmt.Fprintf(h, "\x00%d\x00%q\x00%t",
+		opts.Filters.MinSnippetLength, opts.Filters.ExcludeTitlePatterns, opts.Filters.RelaxIfEmpty)
+	fmt.Fprintf(h, "\x00%s\x00%s", sortedDomains(opts.AllowDomains), sortedDomains(opts.BlockDomains))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// sortedDomains normalizes domains the way the domain filters do and joins
+// them in sorted order
+func sortedDomains(domains []string) string {
+	normalized := normalizeDomains(domains)
+	sort.Strings(normalized)
+	return strings.Join(normalized, ",")
+}
+
+// copyResults returns a copy of results so callers can't modify the cache
+func copyResults(results []SearchResult) []SearchResult {
+	if results == nil {
+		return nil
+	}
+	return append([]SearchResult(nil), results...)
+}
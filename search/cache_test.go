@@ -0,0 +1,152 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type countingSearcher struct {
+	calls int
+	err   error
+}
+
+func (s *countingSearcher) Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []SearchResult{{Title: query, URL: "http://example.com/" + query}}, nil
+}
+
+func (s *countingSearcher) DeepSearch(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	return s.Search(ctx, query, opts)
+}
+
+func TestCachingSearcher_HitsAndExpiry(t *testing.T) {
+	inner := &countingSearcher{}
+	cache := NewCachingSearcher(inner, time.Minute, 10)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	opts := SearchOptions{MaxResults: 5, Engines: []string{"bing", "brave"}}
+
+	first, _ := cache.Search(ctx, "golang", opts)
+	first[0].Title = "modified by caller"
+
+	// Engine order doesn't change the key
+	second, err := cache.Search(ctx, "golang", SearchOptions{MaxResults: 5, Engines: []string{"brave", "bing"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inner.calls != 1 {
+		t.Fatalf("expected the second search to be served from the cache, got %d calls", inner.calls)
+	}
+	if second[0].Title != "golang" {
+		t.Errorf("expected cached results to be unaffected by callers, got %q", second[0].Title)
+	}
+
+	// DeepSearch and different options are cached separately
+	cache.DeepSearch(ctx, "golang", opts)
+	cache.Search(ctx, "golang", SearchOptions{MaxResults: 5, ExtractContent: true})
+	if inner.calls != 3 {
+		t.Errorf("expected 3 underlying searches, got %d", inner.calls)
+	}
+
+	now = now.Add(time.Minute)
+	cache.Search(ctx, "golang", opts)
+	if inner.calls != 4 {
+		t.Errorf("expected the stale entry to be refetched, got %d calls", inner.calls)
+	}
+}
+
+func TestCachingSearcher_LRUEviction(t *testing.T) {
+	inner := &countingSearcher{}
+	cache := NewCachingSearcher(inner, time.Hour, 2)
+	ctx := context.Background()
+
+	cache.Search(ctx, "a", SearchOptions{})
+	cache.Search(ctx, "b", SearchOptions{})
+	cache.Search(ctx, "a", SearchOptions{}) // a is now the most recent
+	cache.Search(ctx, "c", SearchOptions{}) // evicts b
+
+	if cache.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", cache.Len())
+	}
+
+	calls := inner.calls
+	cache.Search(ctx, "a", SearchOptions{})
+	if inner.calls != calls {
+		t.Error("expected the recently used entry to survive eviction")
+	}
+	cache.Search(ctx, "b", SearchOptions{})
+	if inner.calls != calls+1 {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+}
+
+func TestCachingSearcher_ErrorsNotCached(t *testing.T) {
+	inner := &countingSearcher{err: errors.New("rate limited")}
+	cache := NewCachingSearcher(inner, time.Hour, 10)
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Search(context.Background(), "q", SearchOptions{}); err == nil {
+			t.Fatal("expected the error to be returned")
+		}
+	}
+	if inner.calls != 2 {
+		t.Errorf("expected failed searches to be retried, got %d calls", inner.calls)
+	}
+}
+
+func TestCachingSearcher_KeyCoversResultOptions(t *testing.T) {
+	inner := &countingSearcher{}
+	cache := NewCachingSearcher(inner, time.Hour, 10)
+	ctx := context.Background()
+
+	variants := []SearchOptions{
+		{MaxResults: 5},
+		{MaxResults: 5, RedactPII: true},
+		{MaxResults: 5, Rank: true},
+		{MaxResults: 5, Filters: ResultFilters{MinSnippetLength: 40}},
+		{MaxResults: 5, Filters: ResultFilters{ExcludeTitlePatterns: []string{"(?i)sponsored"}}},
+		{MaxResults: 5, Strategy: StrategyRandom},
+		{MaxResults: 5, MinResults: 3},
+		{MaxResults: 5, RequireContent: true},
+	}
+	for _, opts := range variants {
+		cache.Search(ctx, "golang", opts)
+	}
+	if inner.calls != len(variants) {
+		t.Errorf("expected every option variant to miss the cache, got %d calls for %d variants", inner.calls, len(variants))
+	}
+
+	// Toggling RedactPII back hits the matching entry
+	cache.Search(ctx, "golang", SearchOptions{MaxResults: 5, RedactPII: true})
+	cache.Search(ctx, "golang", SearchOptions{MaxResults: 5})
+	if inner.calls != len(variants) {
+		t.Errorf("expected repeated options to be served from the cache, got %d calls", inner.calls)
+	}
+}
+
+func TestCachingSearcher_KeyCoversDomainFilters(t *testing.T) {
+	inner := &countingSearcher{}
+	cache := NewCachingSearcher(inner, time.Hour, 10)
+	ctx := context.Background()
+
+	cache.Search(ctx, "golang", SearchOptions{MaxResults: 5})
+	cache.Search(ctx, "golang", SearchOptions{MaxResults: 5, BlockDomains: []string{"pinterest.com"}})
+	cache.Search(ctx, "golang", SearchOptions{MaxResults: 5, AllowDomains: []string{"go.dev", "github.com"}})
+	if inner.calls != 3 {
+		t.Fatalf("expected domain filters to miss the cache, got %d calls", inner.calls)
+	}
+
+	// Normalization and order don't change the key
+	cache.Search(ctx, "golang", SearchOptions{MaxResults: 5, BlockDomains: []string{" www.Pinterest.com "}})
+	cache.Search(ctx, "golang", SearchOptions{MaxResults: 5, AllowDomains: []string{"github.com", "go.dev"}})
+	if inner.calls != 3 {
+		t.Errorf("expected equivalent domain lists to hit the cache, got %d calls", inner.calls)
+	}
+}
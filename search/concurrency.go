@@ -0,0 +1,31 @@
+package search
+
+import "fmt"
+
+// MaxExtractConcurrency caps how many pages a search extracts at once,
+// whatever is requested
+const MaxExtractConcurrency = 32
+
+// checkExtractConcurrency rejects a negative SearchOptions.ExtractConcurrency
+func checkExtractConcurrency(n int) error {
+	if n < 0 {
+		return fmt.Errorf("invalid extract concurrency %d: must be positive, or zero for the default", n)
+	}
+	return nil
+}
+
+// extractConcurrency returns the first positive of requested, configured
+// and fallback, capped at MaxExtractConcurrency
+func extractConcurrency(requested, configured, fallback int) int {
+	n := fallback
+	switch {
+	case requested > 0:
+		n = requested
+	case configured > 0:
+		n = configured
+	}
+	if n > MaxExtractConcurrency {
+		n = MaxExtractConcurrency
+	}
+	return n
+}
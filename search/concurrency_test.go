@@ -0,0 +1,78 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExtractConcurrency(t *testing.T) {
+	tests := []struct {
+		requested, configured, want int
+	}{
+		{0, 0, 2},
+		{0, 8, 8},
+		{4, 8, 4},
+		{100, 0, MaxExtractConcurrency},
+		{0, 100, MaxExtractConcurrency},
+	}
+	for _, tt := range tests {
+		if got := extractConcurrency(tt.requested, tt.configured, 2); got != tt.want {
+			t.Errorf("extractConcurrency(%d, %d, 2) = %d, want %d", tt.requested, tt.configured, got, tt.want)
+		}
+	}
+}
+
+// peakExtractor records the most extractions in flight at once
+type peakExtractor struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (e *peakExtractor) ExtractContent(ctx context.Context, url string) (string, error) {
+	e.mu.Lock()
+	e.inFlight++
+	if e.inFlight > e.peak {
+		e.peak = e.inFlight
+	}
+	e.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	e.mu.Lock()
+	e.inFlight--
+	e.mu.Unlock()
+	return "content", nil
+}
+
+func TestSearcher_ExtractConcurrency(t *testing.T) {
+	engine := &mockSearchEngine{name: "bing"}
+	for i := 0; i < 8; i++ {
+		engine.results = append(engine.results, SearchResult{Title: fmt.Sprintf("Result %d", i), URL: fmt.Sprintf("https://example.com/%d", i)})
+	}
+
+	extractor := &peakExtractor{}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": engine}, extractor, WithExtractConcurrency(1))
+
+	if _, err := searcher.Search(context.Background(), "golang", SearchOptions{MaxResults: 8, ExtractContent: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if extractor.peak != 1 {
+		t.Errorf("expected the searcher setting to allow 1 extraction at a time, got %d", extractor.peak)
+	}
+
+	extractor.peak = 0
+	if _, err := searcher.Search(context.Background(), "golang", SearchOptions{MaxResults: 8, ExtractContent: true, ExtractConcurrency: 8}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if extractor.peak < 2 || extractor.peak > 8 {
+		t.Errorf("expected the per-search setting to allow up to 8 extractions at a time, got %d", extractor.peak)
+	}
+
+	if _, err := searcher.Search(context.Background(), "golang", SearchOptions{MaxResults: 8, ExtractConcurrency: -1}); err == nil {
+		t.Error("expected a negative concurrency to be rejected")
+	}
+}
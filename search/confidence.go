@@ -0,0 +1,97 @@
+package search
+
+import "strings"
+
+// Confidence combines four signals, each normalized to 0–1, into a single
+// 0–1 score:
+//
+//	Confidence = 0.35*relevance + 0.25*consensus + 0.20*extraction + 0.20*source
+//
+// relevance is the fraction of query terms found in the title or snippet.
+// consensus is the number of engines that returned the URL, out of
+// consensusEngines. extraction is 1 when content was extracted, 0 when
+// extraction failed and 0.5 when it was not attempted. source is looked up
+// in sourceCredibility by SourceCategory.
+const (
+	relevanceShare  = 0.35
+	consensusShare  = 0.25
+	extractionShare = 0.20
+	sourceShare     = 0.20
+
+	// consensusEngines is the agreement that earns full consensus credit
+	consensusEngines = 3
+)
+
+// sourceCredibility scores each source category; uncategorized sources get
+// defaultCredibility
+var sourceCredibility = map[string]float64{
+	SourceOfficial: 1.0,
+	SourceWiki:     0.8,
+	SourceNews:     0.8,
+	SourceForum:    0.4,
+	SourceBlog:     0.4,
+}
+
+const defaultCredibility = 0.5
+
+// engineAgreement counts the distinct engines that returned each URL
+func engineAgreement(results []SearchResult) map[string]int {
+	engines := make(map[string]map[string]bool)
+	for _, r := range results {
+		if engines[r.URL] == nil {
+			engines[r.URL] = make(map[string]bool)
+		}
+		for _, name := range resultEngines(r) {
+			engines[r.URL][name] = true
+		}
+	}
+
+	agreement := make(map[string]int, len(engines))
+	for u, names := range engines {
+		agreement[u] = len(names)
+	}
+	return agreement
+}
+
+// scoreConfidence sets Confidence on each result. agreement is the number of
+// engines that returned each URL, as counted by engineAgreement before
+// duplicates are merged. Source categories must already be set.
+func scoreConfidence(query string, results []SearchResult, agreement map[string]int) {
+	terms := queryTerms(query)
+	for i := range results {
+		results[i].Confidence = confidence(terms, results[i], agreement[results[i].URL])
+	}
+}
+
+// confidence applies the formula documented on relevanceShare
+func confidence(terms []string, r SearchResult, engines int) float64 {
+	relevance := 0.0
+	if len(terms) > 0 {
+		text := strings.ToLower(r.Title + "\n" + r.Snippet)
+		matched := 0
+		for _, term := range terms {
+			if strings.Contains(text, term) {
+				matched++
+			}
+		}
+		relevance = float64(matched) / float64(len(terms))
+	}
+
+	consensus := float64(min(max(engines, 1), consensusEngines)) / consensusEngines
+
+	extraction := 0.5
+	switch {
+	case r.ExtractError != "":
+		extraction = 0
+	case r.Content != "":
+		extraction = 1
+	}
+
+	source, ok := sourceCredibility[r.SourceCategory]
+	if !ok {
+		source = defaultCredibility
+	}
+
+	return relevanceShare*relevance + consensusShare*consensus +
+		extractionShare*extraction + sourceShare*source
+}
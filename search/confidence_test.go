@@ -0,0 +1,82 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// blogFailingExtractor fails to extract any blog page
+type blogFailingExtractor struct{}
+
+func (blogFailingExtractor) ExtractContent(ctx context.Context, url string) (string, error) {
+	if strings.Contains(url, "medium.com") {
+		return "", errors.New("extraction failed")
+	}
+	return "tax filing deadlines for this year", nil
+}
+
+func TestDeepSearch_Confidence(t *testing.T) {
+	official := SearchResult{Title: "Tax filing deadlines", URL: "https://www.irs.gov/deadlines", Snippet: "When to file taxes"}
+	blog := SearchResult{Title: "My tax filing story", URL: "https://medium.com/@someone/taxes", Snippet: "Filing taxes late"}
+
+	withEngine := func(r SearchResult, engine string) SearchResult {
+		r.Engine = engine
+		return r
+	}
+
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing":       &mockSearchEngine{name: "bing", results: []SearchResult{withEngine(official, "bing"), withEngine(blog, "bing")}},
+			"brave":      &mockSearchEngine{name: "brave", results: []SearchResult{withEngine(official, "brave")}},
+			"duckduckgo": &mockSearchEngine{name: "duckduckgo", results: []SearchResult{withEngine(official, "duckduckgo")}},
+		},
+		extractor: blogFailingExtractor{},
+	}
+
+	results, err := searcher.DeepSearch(context.Background(), "tax filing", SearchOptions{
+		MaxResults:     6,
+		ExtractContent: true,
+		Rank:           true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	scores := make(map[string]float64)
+	for _, r := range results {
+		if r.Confidence < 0 || r.Confidence > 1 {
+			t.Errorf("expected confidence in [0, 1], got %v for %s", r.Confidence, r.URL)
+		}
+		scores[r.URL] = r.Confidence
+	}
+
+	if scores[official.URL] <= scores[blog.URL] {
+		t.Errorf("expected the agreed, extracted official result (%v) to beat the failed blog result (%v)",
+			scores[official.URL], scores[blog.URL])
+	}
+
+	data, err := json.Marshal(results[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"confidence":`) {
+		t.Errorf("expected confidence in the structured output, got %s", data)
+	}
+}
+
+func TestConfidence_Formula(t *testing.T) {
+	terms := queryTerms("golang tutorial")
+	best := SearchResult{Title: "Golang tutorial", Content: "content", SourceCategory: SourceOfficial}
+	if got := confidence(terms, best, consensusEngines); got < 0.999 {
+		t.Errorf("expected full confidence, got %v", got)
+	}
+
+	worst := SearchResult{Title: "Cooking", ExtractError: "failed", SourceCategory: SourceBlog}
+	want := consensusShare/consensusEngines + sourceShare*0.4
+	if got := confidence(terms, worst, 1); got-want > 1e-9 || want-got > 1e-9 {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
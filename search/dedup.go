@@ -0,0 +1,86 @@
+package search
+
+import (
+	"net/url"
+	"strings"
+)
+
+// normalizeURL reduces a URL to a form that compares equal across engines:
+// lowercase scheme and host, no trailing slash, fragment or utm_* and
+// fbclid tracking parameters. Unparseable URLs are returned trimmed.
+func normalizeURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return strings.TrimSuffix(raw, "/")
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = strings.TrimSuffix(u.RawPath, "/")
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			if strings.HasPrefix(strings.ToLower(key), "utm_") || strings.EqualFold(key, "fbclid") {
+				query.Del(key)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String()
+}
+
+// dedupeResults drops results whose normalized URL was already seen,
+// keeping the first. Engines on each kept result lists every engine that
+// returned the page, in the order they were seen.
+func dedupeResults(results []SearchResult) []SearchResult {
+	var deduped []SearchResult
+	byURL := make(map[string]int)
+
+	for _, r := range results {
+		key := normalizeURL(r.URL)
+		i, ok := byURL[key]
+		if !ok {
+			byURL[key] = len(deduped)
+			r.Engines = appendEngines(nil, resultEngines(r)...)
+			deduped = append(deduped, r)
+			continue
+		}
+		deduped[i].Engines = appendEngines(deduped[i].Engines, resultEngines(r)...)
+	}
+
+	return deduped
+}
+
+// resultEngines returns the engines that returned r
+func resultEngines(r SearchResult) []string {
+	if len(r.Engines) > 0 {
+		return r.Engines
+	}
+	if r.Engine != "" {
+		return []string{r.Engine}
+	}
+	return nil
+}
+
+// appendEngines adds the names not already in engines
+func appendEngines(engines []string, names ...string) []string {
+	for _, name := range names {
+		found := false
+		for _, e := range engines {
+			if e == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			engines = append(engines, name)
+		}
+	}
+	return engines
+}
@@ -0,0 +1,56 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	same := []string{
+		"https://example.com/article",
+		"https://Example.COM/article/",
+		"HTTPS://example.com/article#comments",
+		"https://example.com/article?utm_source=news&utm_medium=email",
+		"https://example.com/article?fbclid=abc123",
+	}
+	for _, u := range same {
+		if got := normalizeURL(u); got != "https://example.com/article" {
+			t.Errorf("normalizeURL(%q) = %q", u, got)
+		}
+	}
+
+	if got := normalizeURL("https://example.com/search?q=go&utm_campaign=x"); got != "https://example.com/search?q=go" {
+		t.Errorf("expected other query parameters to be kept, got %q", got)
+	}
+	if normalizeURL("https://example.com/a") == normalizeURL("https://example.com/b") {
+		t.Error("expected different paths to stay distinct")
+	}
+}
+
+func TestDedupeResults(t *testing.T) {
+	results := []SearchResult{
+		{Title: "Go", URL: "https://go.dev/", Engine: "bing"},
+		{Title: "Tour", URL: "https://tour.golang.org", Engine: "bing"},
+		{Title: "Go (Brave)", URL: "https://GO.dev?utm_source=brave", Engine: "brave"},
+		{Title: "Go (DDG)", URL: "https://go.dev#top", Engine: "duckduckgo"},
+		{Title: "Go (Bing again)", URL: "https://go.dev", Engine: "bing"},
+	}
+
+	deduped := dedupeResults(results)
+	if len(deduped) != 2 {
+		t.Fatalf("expected 2 distinct pages, got %d: %+v", len(deduped), deduped)
+	}
+	if deduped[0].Title != "Go" || deduped[0].Engine != "bing" {
+		t.Errorf("expected the first occurrence to be kept, got %+v", deduped[0])
+	}
+	if want := []string{"bing", "brave", "duckduckgo"}; !reflect.DeepEqual(deduped[0].Engines, want) {
+		t.Errorf("expected engines %v, got %v", want, deduped[0].Engines)
+	}
+	if want := []string{"bing"}; !reflect.DeepEqual(deduped[1].Engines, want) {
+		t.Errorf("expected engines %v, got %v", want, deduped[1].Engines)
+	}
+
+	if got := engineAgreement(deduped)[deduped[0].URL]; got != 3 {
+		t.Errorf("expected merged engines to count towards agreement, got %d", got)
+	}
+}
@@ -0,0 +1,43 @@
+package search
+
+// diversifyDomains reorders results so that no more than maxRun results from
+// the same domain appear consecutively, pulling the next result from a
+// different domain forward where possible. Relative order is otherwise
+// preserved and no results are dropped.
+func diversifyDomains(results []SearchResult, maxRun int) []SearchResult {
+	if maxRun <= 0 || len(results) <= maxRun {
+		return results
+	}
+
+	pending := append([]SearchResult(nil), results...)
+	ordered := make([]SearchResult, 0, len(results))
+	lastDomain := ""
+	run := 0
+
+	for len(pending) > 0 {
+		pick := 0
+		if run >= maxRun {
+			// Take the first result from another domain; if there is none,
+			// the rest are all from lastDomain and clustering can't be avoided
+			for i, r := range pending {
+				if resultDomain(r.URL) != lastDomain {
+					pick = i
+					break
+				}
+			}
+		}
+
+		r := pending[pick]
+		pending = append(pending[:pick], pending[pick+1:]...)
+		ordered = append(ordered, r)
+
+		if domain := resultDomain(r.URL); domain == lastDomain {
+			run++
+		} else {
+			lastDomain = domain
+			run = 1
+		}
+	}
+
+	return ordered
+}
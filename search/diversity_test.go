@@ -0,0 +1,93 @@
+package search
+
+import (
+	"context"
+	"testing"
+)
+
+func maxDomainRun(results []SearchResult) int {
+	longest, run := 0, 0
+	last := ""
+	for _, r := range results {
+		if d := resultDomain(r.URL); d == last {
+			run++
+		} else {
+			last, run = d, 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+	return longest
+}
+
+func TestDiversifyDomains(t *testing.T) {
+	results := []SearchResult{
+		{URL: "https://a.com/1"},
+		{URL: "https://www.a.com/2"},
+		{URL: "https://a.com/3"},
+		{URL: "https://a.com/4"},
+		{URL: "https://b.com/1"},
+		{URL: "https://c.com/1"},
+		{URL: "https://a.com/5"},
+	}
+
+	ordered := diversifyDomains(results, 2)
+
+	if len(ordered) != len(results) {
+		t.Fatalf("expected %d results, got %d", len(results), len(ordered))
+	}
+	if run := maxDomainRun(ordered); run > 2 {
+		t.Errorf("expected at most 2 consecutive same-domain results, got %d", run)
+	}
+
+	// Relative order within a domain is kept
+	if ordered[0].URL != "https://a.com/1" || ordered[1].URL != "https://www.a.com/2" || ordered[2].URL != "https://b.com/1" {
+		t.Errorf("unexpected order: %v", ordered)
+	}
+}
+
+func TestDiversifyDomains_Disabled(t *testing.T) {
+	results := []SearchResult{{URL: "https://a.com/1"}, {URL: "https://a.com/2"}, {URL: "https://a.com/3"}}
+	ordered := diversifyDomains(results, 0)
+	if maxDomainRun(ordered) != 3 {
+		t.Error("expected results unchanged when the option is disabled")
+	}
+}
+
+func TestMultiEngineSearcher_MaxConsecutiveSameDomain(t *testing.T) {
+	engine := &mockSearchEngine{
+		name: "bing",
+		results: []SearchResult{
+			{Title: "1", URL: "https://docs.example.com/a"},
+			{Title: "2", URL: "https://docs.example.com/b"},
+			{Title: "3", URL: "https://docs.example.com/c"},
+			{Title: "4", URL: "https://other.com/a"},
+			{Title: "5", URL: "https://docs.example.com/d"},
+			{Title: "6", URL: "https://third.org/a"},
+		},
+	}
+
+	searcher := &multiEngineSearcher{
+		engines:   map[string]SearchEngine{"bing": engine},
+		extractor: &mockContentExtractor{},
+	}
+
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{
+		MaxResults:               6,
+		MaxConsecutiveSameDomain: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 6 {
+		t.Fatalf("expected no results to be dropped, got %d", len(results))
+	}
+
+	// docs.example.com has 4 of 6 results, so one run of 2 at the tail is
+	// unavoidable; everything before it must alternate
+	if run := maxDomainRun(results[:4]); run > 1 {
+		t.Errorf("expected no consecutive same-domain results, got run of %d in %v", run, results)
+	}
+}
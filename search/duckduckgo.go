@@ -14,13 +14,15 @@ import (
 
 type duckDuckGoSearchEngine struct {
 	client *http.Client
+	config browserEngineConfig
 }
 
-func NewDuckDuckGoSearchEngine() SearchEngine {
+func NewDuckDuckGoSearchEngine(opts ...BrowserEngineOption) SearchEngine {
 	return &duckDuckGoSearchEngine{
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		config: newBrowserEngineConfig(opts...),
 	}
 }
 
@@ -31,7 +33,11 @@ func (d *duckDuckGoSearchEngine) Name() string {
 func (d *duckDuckGoSearchEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
 	searchURL := fmt.Sprintf("https://duckduckgo.com/?q=%s", url.QueryEscape(query))
 
-	allocCtx, cancel := chromedp.NewContext(ctx)
+	if d.config.err != nil {
+		return nil, d.config.err
+	}
+
+	allocCtx, cancel := d.config.browserContext(ctx)
 	defer cancel()
 
 	var results []SearchResult
@@ -74,6 +80,15 @@ func (d *duckDuckGoSearchEngine) Search(ctx context.Context, query string, maxRe
 			break
 		}
 
+		// Each node takes several round trips to the browser, so stop as
+		// soon as the search is cancelled and keep what was scraped
+		if ctx.Err() != nil {
+			if len(results) == 0 {
+				return nil, ctx.Err()
+			}
+			break
+		}
+
 		var title, link, snippet string
 
 		// Try various selectors for title
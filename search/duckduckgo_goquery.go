@@ -13,13 +13,24 @@ import (
 
 type duckDuckGoGoQueryEngine struct {
 	client *http.Client
+	config engineConfig
 }
 
-func NewDuckDuckGoGoQueryEngine() SearchEngine {
+// DefaultDuckDuckGoSelectors are the selectors used to parse DuckDuckGo Lite
+// results pages. The result element is the link itself, and the snippet is
+// looked up in the table row following the link's row.
+var DefaultDuckDuckGoSelectors = Selectors{
+	Result:  "a.result-link",
+	Snippet: []string{".result-snippet"},
+}
+
+func NewDuckDuckGoGoQueryEngine(opts ...EngineOption) SearchEngine {
+	config := newEngineConfig(DefaultDuckDuckGoSelectors, opts)
 	return &duckDuckGoGoQueryEngine{
-		client: &http.Client{
+		client: config.applyProxy(&http.Client{
 			Timeout: 10 * time.Second,
-		},
+		}),
+		config: config,
 	}
 }
 
@@ -28,40 +39,110 @@ func (d *duckDuckGoGoQueryEngine) Name() string {
 }
 
 func (d *duckDuckGoGoQueryEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	return d.SearchQuery(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+// SearchQuery is Search with the full set of per-search parameters
+func (d *duckDuckGoGoQueryEngine) SearchQuery(ctx context.Context, q EngineQuery) ([]SearchResult, error) {
+	results, _, err := d.search(ctx, q)
+	return results, err
+}
+
+// SearchWithStats is Search that also reports the total-results estimate
+// shown on the results page
+func (d *duckDuckGoGoQueryEngine) SearchWithStats(ctx context.Context, query string, maxResults int) ([]SearchResult, SearchStats, error) {
+	return d.search(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+func (d *duckDuckGoGoQueryEngine) search(ctx context.Context, q EngineQuery) ([]SearchResult, SearchStats, error) {
+	if d.config.err != nil {
+		return nil, SearchStats{}, d.config.err
+	}
+	if q.Vertical == VerticalNews {
+		results, err := d.searchNews(ctx, q)
+		return results, SearchStats{}, err
+	}
+
 	// DuckDuckGo Lite version (GET request with Lynx UA)
 	// Using Lite version with Lynx UA avoids most CAPTCHA/bot detection issues
-	searchURL := fmt.Sprintf("https://duckduckgo.com/lite/?q=%s", url.QueryEscape(query))
+	searchURL := fmt.Sprintf("https://duckduckgo.com/lite/?q=%s", url.QueryEscape(q.Query))
+	if q.Offset > 0 {
+		// s is the 0-based offset and dc the 1-based position of the first
+		// result, as sent by the Lite page's "Next" button
+		searchURL += fmt.Sprintf("&s=%d&dc=%d", q.Offset, q.Offset+1)
+	}
+	if q.hasDateRange() {
+		from, to := q.dateRange()
+		searchURL += "&df=" + from.Format("2006-01-02") + ".." + to.Format("2006-01-02")
+	}
+	if kl := duckDuckGoRegion(q); kl != "" {
+		searchURL += "&kl=" + url.QueryEscape(kl)
+	}
+	searchURL += "&kp=" + duckDuckGoSafeSearch[q.safeSearch()]
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
-		return nil, err
+		return nil, SearchStats{}, err
 	}
 	
-	// Use Lynx User-Agent to ensure we get the lightweight HTML version
-	req.Header.Set("User-Agent", "Lynx/2.8.9rel.1 libwww-FM/2.14 SSL-MM/1.4.1 OpenSSL/1.1.1d")
+	// Use Lynx User-Agent to ensure we get the lightweight HTML version,
+	// unless the caller supplied their own pool
+	userAgent := "Lynx/2.8.9rel.1 libwww-FM/2.14 SSL-MM/1.4.1 OpenSSL/1.1.1d"
+	if len(d.config.userAgents) > 0 {
+		userAgent = d.config.userAgent()
+	}
+	req.Header.Set("User-Agent", userAgent)
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	if lang := q.acceptLanguage(""); lang != "" {
+		req.Header.Set("Accept-Language", lang)
+	}
 	
-	resp, err := d.client.Do(req)
+	resp, err := fetchResultsPage(d.client, req, d.Name())
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch DuckDuckGo results: %w", err)
+		return nil, SearchStats{}, err
 	}
 	defer resp.Body.Close()
 	
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, err := parseLimitedBody(resp.Body, d.config.maxBodySize, searchURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+		return nil, SearchStats{}, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 	
+	results := d.parseResults(doc, q.MaxResults)
+	if len(results) == 0 && captchaPage(doc) {
+		return nil, SearchStats{}, &BlockedError{Engine: d.Name(), URL: searchURL}
+	}
+	return results, parseSearchStats(doc, d.config.selectors), nil
+}
+
+// duckDuckGoRegion returns DuckDuckGo's kl region code for q, which is
+// country first ("de-de", "us-en"). A missing half is filled in from the
+// other, and empty means no targeting.
+func duckDuckGoRegion(q EngineQuery) string {
+	lang, region := strings.ToLower(q.Language), strings.ToLower(q.Region)
+	switch {
+	case lang == "" && region == "":
+		return ""
+	case region == "":
+		region = lang
+	case lang == "":
+		lang = region
+	}
+	return region + "-" + lang
+}
+
+func (d *duckDuckGoGoQueryEngine) parseResults(doc *goquery.Document, maxResults int) []SearchResult {
+	sel := d.config.selectors
 	var results []SearchResult
 	
 	// Lite version uses tables for layout. Result links have class "result-link"
-	doc.Find("a.result-link").Each(func(i int, s *goquery.Selection) {
-		if len(results) >= maxResults {
+	doc.Find(sel.Result).Each(func(i int, s *goquery.Selection) {
+		if len(results) >= maxResults || i < d.config.skipFirst {
 			return
 		}
 		
-		title := strings.TrimSpace(s.Text())
-		link, _ := s.Attr("href")
+		title := firstText(s, sel.Title)
+		link := firstAttr(s, sel.Link, "href")
 		
 		// Snippet is usually in the next row's cell with class .result-snippet
 		snippet := ""
@@ -70,10 +151,7 @@ func (d *duckDuckGoGoQueryEngine) Search(ctx context.Context, query string, maxR
 		if tr.Length() > 0 {
 			snippetTr := tr.Next()
 			if snippetTr.Length() > 0 {
-				snippetElem := snippetTr.Find(".result-snippet")
-				if snippetElem.Length() > 0 {
-					snippet = strings.TrimSpace(snippetElem.Text())
-				}
+				snippet = firstText(snippetTr, sel.Snippet)
 			}
 		}
 		
@@ -107,5 +185,5 @@ func (d *duckDuckGoGoQueryEngine) Search(ctx context.Context, query string, maxR
 		}
 	})
 	
-	return results, nil
+	return results
 }
\ No newline at end of file
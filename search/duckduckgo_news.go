@@ -0,0 +1,137 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// duckDuckGoVQD finds the per-query token DuckDuckGo's JSON endpoints
+// require, embedded in its regular results page
+var duckDuckGoVQD = regexp.MustCompile(`vqd=["']?([0-9-]+)`)
+
+// duckDuckGoNews is the response of DuckDuckGo's news.js endpoint
+type duckDuckGoNews struct {
+	Results []struct {
+		Date    int64  `json:"date"`
+		Excerpt string `json:"excerpt"`
+		Source  string `json:"source"`
+		Title   string `json:"title"`
+		URL     string `json:"url"`
+	} `json:"results"`
+}
+
+// searchNews queries DuckDuckGo News. Its JSON endpoint needs a vqd token
+// for the query, so the regular results page is fetched first to get one.
+func (d *duckDuckGoGoQueryEngine) searchNews(ctx context.Context, q EngineQuery) ([]SearchResult, error) {
+	vqd, err := d.newsToken(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	region := duckDuckGoRegion(q)
+	if region == "" {
+		region = "wt-wt"
+	}
+	searchURL := fmt.Sprintf("https://duckduckgo.com/news.js?o=json&noamp=1&q=%s&vqd=%s&l=%s&p=%s",
+		url.QueryEscape(q.Query), url.QueryEscape(vqd), url.QueryEscape(region), duckDuckGoSafeSearch[q.safeSearch()])
+	if q.Offset > 0 {
+		searchURL += fmt.Sprintf("&s=%d", q.Offset)
+	}
+
+	data, err := d.fetchNews(ctx, searchURL, q)
+	if err != nil {
+		return nil, err
+	}
+	return parseDuckDuckGoNews(data, q)
+}
+
+// newsToken fetches the vqd token for q's query
+func (d *duckDuckGoGoQueryEngine) newsToken(ctx context.Context, q EngineQuery) (string, error) {
+	tokenURL := fmt.Sprintf("https://duckduckgo.com/?q=%s&ia=news", url.QueryEscape(q.Query))
+	data, err := d.fetchNews(ctx, tokenURL, q)
+	if err != nil {
+		return "", err
+	}
+
+	m := duckDuckGoVQD.FindSubmatch(data)
+	if m == nil {
+		return "", &BlockedError{Engine: "duckduckgo", URL: tokenURL}
+	}
+	return string(m[1]), nil
+}
+
+// fetchNews GETs one of DuckDuckGo's news URLs and returns the body
+func (d *duckDuckGoGoQueryEngine) fetchNews(ctx context.Context, target string, q EngineQuery) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", d.config.userAgent())
+	req.Header.Set("Referer", "https://duckduckgo.com/")
+	req.Header.Set("Accept-Language", q.acceptLanguage("en-US,en;q=0.5"))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch DuckDuckGo News results: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("duckduckgo news returned status %d", resp.StatusCode)
+	}
+	return readLimitedBody(resp.Body, d.config.maxBodySize, target)
+}
+
+// parseDuckDuckGoNews turns a news.js response into results
+func parseDuckDuckGoNews(data []byte, q EngineQuery) ([]SearchResult, error) {
+	var news duckDuckGoNews
+	if err := json.Unmarshal(data, &news); err != nil {
+		return nil, fmt.Errorf("failed to parse DuckDuckGo News response: %w", err)
+	}
+
+	var results []SearchResult
+	for _, item := range news.Results {
+		if len(results) >= q.MaxResults {
+			break
+		}
+		if item.URL == "" || item.Title == "" {
+			continue
+		}
+
+		var published time.Time
+		if item.Date > 0 {
+			published = time.Unix(item.Date, 0).UTC()
+		}
+		if !q.inDateRange(published) {
+			continue
+		}
+
+		results = append(results, SearchResult{
+			Title:       htmlText(item.Title),
+			URL:         item.URL,
+			Snippet:     htmlText(item.Excerpt),
+			Engine:      "duckduckgo",
+			PublishedAt: published,
+			Source:      item.Source,
+		})
+	}
+	return results, nil
+}
+
+// htmlText returns the text of an HTML fragment such as an excerpt with
+// <b> highlighting
+func htmlText(fragment string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fragment))
+	if err != nil {
+		return strings.TrimSpace(fragment)
+	}
+	return strings.TrimSpace(doc.Text())
+}
@@ -0,0 +1,52 @@
+package search
+
+import "strings"
+
+// engineAliases maps common alternative engine names to their registered
+// keys. Names are normalized with normalizeEngineName before lookup, so
+// "Duck Duck Go" and "duck-duck-go" need no entry of their own.
+//
+//	ddg, duck      -> duckduckgo
+//	g              -> google
+//	bravesearch    -> brave
+//	bingsearch     -> bing
+var engineAliases = map[string]string{
+	"ddg":         "duckduckgo",
+	"duck":        "duckduckgo",
+	"g":           "google",
+	"bravesearch": "brave",
+	"bingsearch":  "bing",
+}
+
+// normalizeEngineName lowercases name, strips spaces, hyphens, underscores
+// and dots, and resolves aliases
+func normalizeEngineName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '-', '_', '.':
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+
+	if canonical, ok := engineAliases[name]; ok {
+		return canonical
+	}
+	return name
+}
+
+// checkEngineNames returns an *UnknownEngineError listing any requested
+// names that don't resolve to a registered engine
+func checkEngineNames(engines map[string]SearchEngine, names []string) error {
+	var unknown []string
+	for _, name := range names {
+		if _, ok := engines[normalizeEngineName(name)]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return &UnknownEngineError{Names: unknown}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNormalizeEngineName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"bing", "bing"},
+		{"Bing", "bing"},
+		{"ddg", "duckduckgo"},
+		{"DDG", "duckduckgo"},
+		{"Duck Duck Go", "duckduckgo"},
+		{"duck-duck-go", "duckduckgo"},
+		{"g", "google"},
+		{"Brave Search", "brave"},
+		{"unknown", "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := normalizeEngineName(tt.input); got != tt.want {
+				t.Errorf("normalizeEngineName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiEngineSearcher_EngineAliases(t *testing.T) {
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing":       &mockSearchEngine{name: "bing", results: []SearchResult{{URL: "http://bing.com", Engine: "bing"}}},
+			"duckduckgo": &mockSearchEngine{name: "duckduckgo", results: []SearchResult{{URL: "http://ddg.com", Engine: "duckduckgo"}}},
+		},
+		extractor: &mockContentExtractor{},
+	}
+
+	if engine := searcher.selectEngine([]string{"ddg"}); engine == nil || engine.Name() != "duckduckgo" {
+		t.Errorf("expected ddg to resolve to duckduckgo, got %v", engine)
+	}
+
+	engines := searcher.getEngines([]string{"Bing", "Duck Duck Go"})
+	if len(engines) != 2 {
+		t.Fatalf("expected both aliases to resolve, got %d engines", len(engines))
+	}
+
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{
+		MaxResults: 1,
+		Engines:    []string{"DDG"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Engine != "duckduckgo" {
+		t.Errorf("expected duckduckgo result, got %s", results[0].Engine)
+	}
+}
+
+func TestMultiEngineSearcher_UnknownEngineReported(t *testing.T) {
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing": &mockSearchEngine{name: "bing", results: []SearchResult{{URL: "http://bing.com"}}},
+		},
+		extractor: &mockContentExtractor{},
+	}
+
+	_, err := searcher.DeepSearch(context.Background(), "test", SearchOptions{
+		MaxResults: 5,
+		Engines:    []string{"bing", "altavista"},
+	})
+
+	if !errors.Is(err, ErrUnknownEngine) {
+		t.Fatalf("expected ErrUnknownEngine, got %v", err)
+	}
+
+	var unknown *UnknownEngineError
+	if !errors.As(err, &unknown) || len(unknown.Names) != 1 || unknown.Names[0] != "altavista" {
+		t.Errorf("expected altavista to be reported, got %v", err)
+	}
+}
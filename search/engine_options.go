@@ -0,0 +1,205 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/liliang-cn/mcp-websearch-server/extraction"
+	"github.com/liliang-cn/mcp-websearch-server/utils"
+)
+
+// EngineOption configures a goquery-based search engine
+type EngineOption func(*engineConfig)
+
+type engineConfig struct {
+	selectors   Selectors
+	maxBodySize int64
+	// skipFirst is how many leading result elements are dropped as
+	// non-organic
+	skipFirst int
+	// proxy is where the engine's requests are sent, nil for direct
+	proxy *url.URL
+	// err is set by an option that couldn't be applied, such as an
+	// invalid proxy URL, and fails every search
+	err error
+	// userAgents overrides the UserAgents pool requests pick from
+	userAgents []string
+}
+
+// defaultMaxBodySize caps how much of a results page is read and parsed
+const defaultMaxBodySize = 5 << 20
+
+// WithSelectors overrides the CSS selectors used to parse results. Empty
+// fields keep the engine's defaults.
+func WithSelectors(s Selectors) EngineOption {
+	return func(c *engineConfig) {
+		c.selectors = s.merge(c.selectors)
+	}
+}
+
+// WithMaxBodySize caps how many bytes of a response are read, so a huge
+// page can't exhaust memory; parsing can't be interrupted by the context.
+// HTML pages are cut off at the limit and parsed as far as they got, with
+// a warning logged, while larger JSON and feed responses fail with a
+// *ResponseTooLargeError. Zero or a negative n keeps the 5MB default.
+func WithMaxBodySize(n int64) EngineOption {
+	return func(c *engineConfig) {
+		if n > 0 {
+			c.maxBodySize = n
+		}
+	}
+}
+
+// WithSkipFirst drops the first n elements matched by the result selector,
+// for engines whose leading rows are reliably ads or internal links rather
+// than organic results. They are dropped before results are counted
+// against maxResults.
+func WithSkipFirst(n int) EngineOption {
+	return func(c *engineConfig) {
+		if n > 0 {
+			c.skipFirst = n
+		}
+	}
+}
+
+// WithProxy sends the engine's requests through the proxy at proxyURL,
+// which may use the http, https or socks5 scheme. NewGoQueryEngine reports
+// an invalid URL when the engine is built; engines built with their own
+// constructors fail every search instead of silently connecting directly.
+func WithProxy(proxyURL string) EngineOption {
+	return func(c *engineConfig) {
+		u, err := utils.ParseProxyURL(proxyURL)
+		if err != nil {
+			c.err = err
+			return
+		}
+		c.proxy = u
+	}
+}
+
+// WithUserAgents makes the engine pick each request's User-Agent from
+// agents instead of the UserAgents pool. An empty list keeps the default.
+func WithUserAgents(agents ...string) EngineOption {
+	return func(c *engineConfig) {
+		if len(agents) > 0 {
+			c.userAgents = agents
+		}
+	}
+}
+
+// userAgent picks the User-Agent for one request
+func (c engineConfig) userAgent() string {
+	if len(c.userAgents) > 0 {
+		return randomUserAgent(c.userAgents)
+	}
+	return RandomUserAgent()
+}
+
+// ValidateProxyURL reports whether proxyURL is usable with WithProxy
+func ValidateProxyURL(proxyURL string) error {
+	_, err := utils.ParseProxyURL(proxyURL)
+	return err
+}
+
+// applyProxy routes client's requests through the configured proxy, if
+// there is one
+func (c engineConfig) applyProxy(client *http.Client) *http.Client {
+	if c.proxy != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(c.proxy)
+		client.Transport = transport
+	}
+	return client
+}
+
+// goQueryEngines are the constructors NewGoQueryEngine picks from, by
+// engine name
+var goQueryEngines = map[string]func(...EngineOption) SearchEngine{
+	"bing":       NewBingGoQueryEngine,
+	"brave":      NewBraveGoQueryEngine,
+	"duckduckgo": NewDuckDuckGoGoQueryEngine,
+	"google":     NewGoogleGoQueryEngine,
+	"mojeek":     NewMojeekGoQueryEngine,
+	"qwant":      NewQwantEngine,
+	"startpage":  NewStartpageGoQueryEngine,
+	"yandex":     NewYandexGoQueryEngine,
+}
+
+// NewGoQueryEngine builds the HTTP engine called name, such as "bing" or
+// "ddg", with opts. Unlike the engines' own constructors it reports an
+// option that can't be applied, such as an invalid proxy URL, instead of
+// returning an engine whose every search fails. An unknown name is an
+// *UnknownEngineError.
+func NewGoQueryEngine(name string, opts ...EngineOption) (SearchEngine, error) {
+	constructor, ok := goQueryEngines[normalizeEngineName(name)]
+	if !ok {
+		return nil, &UnknownEngineError{Names: []string{name}}
+	}
+	if err := newEngineConfig(Selectors{}, opts).err; err != nil {
+		return nil, err
+	}
+	return constructor(opts...), nil
+}
+
+func newEngineConfig(defaults Selectors, opts []EngineOption) engineConfig {
+	c := engineConfig{selectors: defaults, maxBodySize: defaultMaxBodySize}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// BrowserEngineOption configures a chromedp-based search engine
+type BrowserEngineOption func(*browserEngineConfig)
+
+type browserEngineConfig struct {
+	// remoteURL is the DevTools WebSocket URL of the Chrome searches run
+	// in, empty to launch a local one
+	remoteURL string
+	// proxyServer is where a local Chrome's traffic is sent, empty for
+	// direct
+	proxyServer string
+	// err is set by an invalid proxy URL and fails every search
+	err error
+}
+
+// newBrowserEngineConfig returns the config for opts, dialing the Chrome
+// named by extraction.RemoteChromeEnv unless an option says otherwise
+func newBrowserEngineConfig(opts ...BrowserEngineOption) browserEngineConfig {
+	config := browserEngineConfig{remoteURL: os.Getenv(extraction.RemoteChromeEnv)}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// WithBrowserProxy launches the engine's browser behind the proxy at
+// proxyURL, which may use the http, https or socks5 scheme. An invalid URL
+// makes every search fail rather than connect directly. A remote Chrome
+// keeps its own proxy settings.
+func WithBrowserProxy(proxyURL string) BrowserEngineOption {
+	return func(c *browserEngineConfig) {
+		u, err := utils.ParseProxyURL(proxyURL)
+		if err != nil {
+			c.err = err
+			return
+		}
+		c.proxyServer = u.String()
+	}
+}
+
+// browserContext opens the Chrome c describes for a single search
+func (c browserEngineConfig) browserContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return extraction.NewProxiedBrowserContext(ctx, c.remoteURL, c.proxyServer)
+}
+
+// WithRemoteChrome runs the engine's searches in the running Chrome whose
+// DevTools endpoint is at wsURL, such as ws://chrome:9222, instead of
+// launching a local browser per search. An empty wsURL launches locally.
+func WithRemoteChrome(wsURL string) BrowserEngineOption {
+	return func(c *browserEngineConfig) {
+		c.remoteURL = wsURL
+	}
+}
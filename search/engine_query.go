@@ -0,0 +1,128 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// EngineQuery holds the per-search parameters passed down to an engine
+type EngineQuery struct {
+	Query      string
+	MaxResults int
+	// Offset is how many leading results to skip, for paging
+	Offset int
+	// After and Before restrict results to pages published in that
+	// window; a zero time leaves that end open. Engines that can't
+	// express a date range ignore them.
+	After  time.Time
+	Before time.Time
+	// Language is an ISO 639-1 code such as "de" and Region an ISO 3166-1
+	// country code such as "DE"; empty leaves the engine's default
+	Language string
+	Region   string
+	// SafeSearch is the explicit-content filter level; empty means
+	// moderate
+	SafeSearch SafeSearchLevel
+	// Vertical is the index to search, empty for the web; see
+	// SearchOptions.Vertical
+	Vertical string
+}
+
+// acceptLanguage returns the Accept-Language header for q's language and
+// region, or fallback when no language is set
+func (q EngineQuery) acceptLanguage(fallback string) string {
+	if q.Language == "" {
+		return fallback
+	}
+	lang := strings.ToLower(q.Language)
+	if q.Region == "" {
+		return lang
+	}
+	return lang + "-" + strings.ToUpper(q.Region) + "," + lang + ";q=0.9"
+}
+
+// hasDateRange reports whether either end of the date window is set
+func (q EngineQuery) hasDateRange() bool {
+	return !q.After.IsZero() || !q.Before.IsZero()
+}
+
+// dateRange returns the window's bounds in UTC, filling an open start with
+// the Unix epoch and an open end with today
+func (q EngineQuery) dateRange() (from, to time.Time) {
+	from, to = time.Unix(0, 0).UTC(), time.Now().UTC()
+	if !q.After.IsZero() {
+		from = q.After.UTC()
+	}
+	if !q.Before.IsZero() {
+		to = q.Before.UTC()
+	}
+	return from, to
+}
+
+// QueryEngine is implemented by engines that accept the full EngineQuery,
+// such as a native offset parameter, rather than just a query and a
+// result count
+type QueryEngine interface {
+	SearchQuery(ctx context.Context, q EngineQuery) ([]SearchResult, error)
+}
+
+// engineQuery returns the EngineQuery for a search with opts
+func engineQuery(query string, opts SearchOptions) EngineQuery {
+	return EngineQuery{
+		Query:      query,
+		MaxResults: opts.MaxResults,
+		Offset:     opts.Offset,
+		After:      opts.After,
+		Before:     opts.Before,
+		Language:   opts.Language,
+		Region:     opts.Region,
+		SafeSearch: opts.SafeSearch,
+		Vertical:   opts.Vertical,
+	}
+}
+
+// statsQueryEngine is implemented by the engines that read SearchStats off
+// their results page
+type statsQueryEngine interface {
+	search(ctx context.Context, q EngineQuery) ([]SearchResult, SearchStats, error)
+}
+
+// searchEngine runs q on engine. Engines that don't implement QueryEngine
+// are asked for Offset+MaxResults results and the first Offset are dropped.
+// Engines that report stats have their TotalEstimate set on every result.
+func searchEngine(ctx context.Context, engine SearchEngine, q EngineQuery) ([]SearchResult, error) {
+	if se, ok := engine.(statsQueryEngine); ok {
+		results, stats, err := se.search(ctx, q)
+		for i := range results {
+			results[i].TotalEstimate = stats.TotalEstimate
+		}
+		return results, err
+	}
+	if qe, ok := engine.(QueryEngine); ok {
+		return qe.SearchQuery(ctx, q)
+	}
+
+	if q.Offset <= 0 {
+		return engine.Search(ctx, q.Query, q.MaxResults)
+	}
+
+	results, err := engine.Search(ctx, q.Query, q.Offset+q.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	return skipResults(results, q.Offset, q.MaxResults), nil
+}
+
+// skipResults drops the first skip results and caps the rest at
+// maxResults, for engines whose pages don't start at the requested offset
+func skipResults(results []SearchResult, skip, maxResults int) []SearchResult {
+	if skip >= len(results) {
+		return nil
+	}
+	results = results[skip:]
+	if len(results) > maxResults {
+		results = results[:maxResults]
+	}
+	return results
+}
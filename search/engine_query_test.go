@@ -0,0 +1,201 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearch_Offset(t *testing.T) {
+	var all []SearchResult
+	for i := 1; i <= 25; i++ {
+		all = append(all, SearchResult{Title: fmt.Sprintf("Result %d", i), URL: fmt.Sprintf("http://%d.example.com", i), Engine: "bing"})
+	}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": &mockSearchEngine{name: "bing", results: all}}, nil)
+
+	results, err := searcher.Search(context.Background(), "golang", SearchOptions{MaxResults: 10, Offset: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 10 || results[0].Title != "Result 11" || results[9].Title != "Result 20" {
+		t.Errorf("expected results 11-20, got %d starting at %q", len(results), results[0].Title)
+	}
+}
+
+// fixtureTransport records request URLs and headers and answers with a
+// fixed page
+type fixtureTransport struct {
+	body    string
+	urls    []string
+	headers []http.Header
+}
+
+func (f *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.urls = append(f.urls, req.URL.String())
+	f.headers = append(f.headers, req.Header.Clone())
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/html"}},
+		Body:       io.NopCloser(strings.NewReader(f.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestGoQueryEngines_OffsetParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine SearchEngine
+		client func(SearchEngine) *http.Client
+		want   string
+	}{
+		{"bing", NewBingGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*bingGoQueryEngine).client }, "&first=11"},
+		{"duckduckgo", NewDuckDuckGoGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*duckDuckGoGoQueryEngine).client }, "&s=10&dc=11"},
+		{"google", NewGoogleGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*googleGoQueryEngine).client }, "&start=10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &fixtureTransport{body: "<html><body></body></html>"}
+			tt.client(tt.engine).Transport = transport
+
+			if _, err := tt.engine.(QueryEngine).SearchQuery(context.Background(), EngineQuery{Query: "golang", MaxResults: 10, Offset: 10}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(transport.urls) != 1 || !strings.Contains(transport.urls[0], tt.want) {
+				t.Errorf("expected request URL to contain %q, got %v", tt.want, transport.urls)
+			}
+		})
+	}
+}
+
+func TestBraveGoQueryEngine_OffsetWithinPage(t *testing.T) {
+	var page strings.Builder
+	page.WriteString("<html><body>")
+	for i := 21; i <= 40; i++ {
+		fmt.Fprintf(&page, `<div class="snippet"><a class="snippet-title" href="https://example.com/%d">Result %d</a></div>`, i, i)
+	}
+	page.WriteString("</body></html>")
+
+	engine := NewBraveGoQueryEngine().(*braveGoQueryEngine)
+	transport := &fixtureTransport{body: page.String()}
+	engine.client.Transport = transport
+
+	// Result 26 onwards lives five results into Brave's second page
+	results, err := engine.SearchQuery(context.Background(), EngineQuery{Query: "golang", MaxResults: 3, Offset: 25})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(transport.urls[0], "&offset=1") {
+		t.Errorf("expected the second page to be requested, got %s", transport.urls[0])
+	}
+	if len(results) != 3 || results[0].Title != "Result 26" || results[2].Title != "Result 28" {
+		t.Errorf("expected results 26-28, got %+v", results)
+	}
+}
+
+func TestGoQueryEngines_DateRangeParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine SearchEngine
+		client func(SearchEngine) *http.Client
+		want   string
+	}{
+		{"bing", NewBingGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*bingGoQueryEngine).client }, "&filters=ex1%3A%22ez5_19723_19753%22"},
+		{"duckduckgo", NewDuckDuckGoGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*duckDuckGoGoQueryEngine).client }, "&df=2024-01-01..2024-01-31"},
+		{"brave", NewBraveGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*braveGoQueryEngine).client }, "&tf=2024-01-01to2024-01-31"},
+		{"google", NewGoogleGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*googleGoQueryEngine).client }, "&tbs=cdr%3A1%2Ccd_min%3A1%2F1%2F2024%2Ccd_max%3A1%2F31%2F2024"},
+	}
+
+	q := EngineQuery{
+		Query:      "golang",
+		MaxResults: 10,
+		After:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Before:     time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &fixtureTransport{body: "<html><body></body></html>"}
+			tt.client(tt.engine).Transport = transport
+
+			if _, err := tt.engine.(QueryEngine).SearchQuery(context.Background(), q); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(transport.urls) != 1 || !strings.Contains(transport.urls[0], tt.want) {
+				t.Errorf("expected request URL to contain %q, got %v", tt.want, transport.urls)
+			}
+		})
+	}
+}
+
+func TestEngineQuery_DateRangeFromOptions(t *testing.T) {
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	q := engineQuery("golang", SearchOptions{MaxResults: 5, After: after})
+
+	if !q.After.Equal(after) || !q.Before.IsZero() {
+		t.Errorf("expected After to be passed through and Before left open, got %+v", q)
+	}
+
+	from, to := q.dateRange()
+	if !from.Equal(after) || time.Since(to) > time.Minute {
+		t.Errorf("expected the open end to default to now, got %v..%v", from, to)
+	}
+}
+
+func TestGoQueryEngines_LanguageRegionParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine SearchEngine
+		client func(SearchEngine) *http.Client
+		want   []string
+	}{
+		{"bing", NewBingGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*bingGoQueryEngine).client }, []string{"&setlang=de", "&cc=DE"}},
+		{"duckduckgo", NewDuckDuckGoGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*duckDuckGoGoQueryEngine).client }, []string{"&kl=de-de"}},
+		{"brave", NewBraveGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*braveGoQueryEngine).client }, []string{"&country=de", "&search_lang=de"}},
+		{"google", NewGoogleGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*googleGoQueryEngine).client }, []string{"&hl=de", "&gl=de"}},
+		{"startpage", NewStartpageGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*startpageGoQueryEngine).client }, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &fixtureTransport{body: "<html><body></body></html>"}
+			tt.client(tt.engine).Transport = transport
+
+			// Startpage reports an empty page as blocked; only the request matters here
+			tt.engine.(QueryEngine).SearchQuery(context.Background(), EngineQuery{Query: "golang", MaxResults: 10, Language: "de", Region: "DE"})
+			if len(transport.urls) != 1 {
+				t.Fatalf("expected one request, got %v", transport.urls)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(transport.urls[0], want) {
+					t.Errorf("expected request URL to contain %q, got %s", want, transport.urls[0])
+				}
+			}
+			if got := transport.headers[0].Get("Accept-Language"); got != "de-DE,de;q=0.9" {
+				t.Errorf("expected a German Accept-Language header, got %q", got)
+			}
+		})
+	}
+}
+
+func TestDuckDuckGoRegion(t *testing.T) {
+	tests := []struct {
+		language, region, want string
+	}{
+		{"", "", ""},
+		{"fr", "", "fr-fr"},
+		{"", "DE", "de-de"},
+		{"en", "US", "us-en"},
+	}
+	for _, tt := range tests {
+		if got := duckDuckGoRegion(EngineQuery{Language: tt.language, Region: tt.region}); got != tt.want {
+			t.Errorf("duckDuckGoRegion(%q, %q) = %q, want %q", tt.language, tt.region, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package search
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// EngineStats counts the searches sent to one engine
+type EngineStats struct {
+	Requests  int64 `json:"requests"`
+	Successes int64 `json:"successes"`
+	Failures  int64 `json:"failures"`
+	// Fallbacks is how many times the engine failed as the selected
+	// engine of a search, which then fell back to the others
+	Fallbacks int64 `json:"fallbacks"`
+}
+
+// Stats is a snapshot of a searcher's engine counters since it was created
+type Stats struct {
+	// Engines holds the counters of each engine that was queried, keyed
+	// by name
+	Engines map[string]EngineStats `json:"engines"`
+	// Fallbacks is how many searches fell back after their selected
+	// engine failed
+	Fallbacks int64 `json:"fallbacks"`
+}
+
+// EngineStatsReporter is implemented by searchers that count their
+// engines' successes, failures and fallbacks
+type EngineStatsReporter interface {
+	Stats() Stats
+}
+
+// engineCounters are one engine's live counters
+type engineCounters struct {
+	requests  atomic.Int64
+	successes atomic.Int64
+	failures  atomic.Int64
+	fallbacks atomic.Int64
+}
+
+// searchStats counts engine activity for a searcher. A nil *searchStats
+// counts nothing, so searchers built as struct literals still work.
+type searchStats struct {
+	mu        sync.RWMutex
+	engines   map[string]*engineCounters
+	fallbacks atomic.Int64
+}
+
+func newSearchStats() *searchStats {
+	return &searchStats{engines: make(map[string]*engineCounters)}
+}
+
+// counters returns name's counters, creating them on first use
+func (s *searchStats) counters(name string) *engineCounters {
+	s.mu.RLock()
+	c, ok := s.engines[name]
+	s.mu.RUnlock()
+	if ok {
+		return c
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.engines[name]; ok {
+		return c
+	}
+	c = &engineCounters{}
+	s.engines[name] = c
+	return c
+}
+
+// search runs q on engine through searchEngine, counting the request and
+// its outcome
+func (s *searchStats) search(ctx context.Context, engine SearchEngine, q EngineQuery) ([]SearchResult, error) {
+	if s == nil {
+		return searchEngine(ctx, engine, q)
+	}
+
+	c := s.counters(engine.Name())
+	c.requests.Add(1)
+	results, err := searchEngine(ctx, engine, q)
+	if err != nil {
+		c.failures.Add(1)
+	} else {
+		c.successes.Add(1)
+	}
+	return results, err
+}
+
+// fallback records that a search fell back after failedEngine failed
+func (s *searchStats) fallback(failedEngine string) {
+	if s == nil {
+		return
+	}
+	s.fallbacks.Add(1)
+	s.counters(failedEngine).fallbacks.Add(1)
+}
+
+// snapshot returns the current counts
+func (s *searchStats) snapshot() Stats {
+	stats := Stats{Engines: make(map[string]EngineStats)}
+	if s == nil {
+		return stats
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for name, c := range s.engines {
+		stats.Engines[name] = EngineStats{
+			Requests:  c.requests.Load(),
+			Successes: c.successes.Load(),
+			Failures:  c.failures.Load(),
+			Fallbacks: c.fallbacks.Load(),
+		}
+	}
+	stats.Fallbacks = s.fallbacks.Load()
+	return stats
+}
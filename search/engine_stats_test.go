@@ -0,0 +1,87 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestStats_SearchAndFallback(t *testing.T) {
+	engines := map[string]SearchEngine{
+		"bing":  &mockSearchEngine{name: "bing", err: errors.New("engine down")},
+		"brave": &mockSearchEngine{name: "brave", results: []SearchResult{{Title: "Result", URL: "https://example.com", Engine: "brave"}}},
+	}
+	searcher := NewSearcherWithEngines(engines, nil)
+
+	if _, err := searcher.Search(context.Background(), "query", SearchOptions{MaxResults: 5, Engines: []string{"bing"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats := searcher.(EngineStatsReporter).Stats()
+	if got := stats.Engines["bing"]; got != (EngineStats{Requests: 1, Failures: 1, Fallbacks: 1}) {
+		t.Errorf("unexpected bing stats: %+v", got)
+	}
+	if got := stats.Engines["brave"]; got != (EngineStats{Requests: 1, Successes: 1}) {
+		t.Errorf("unexpected brave stats: %+v", got)
+	}
+	if stats.Fallbacks != 1 {
+		t.Errorf("expected 1 fallback, got %d", stats.Fallbacks)
+	}
+
+	// The snapshot doesn't change with later searches
+	if _, err := searcher.DeepSearch(context.Background(), "query", SearchOptions{MaxResults: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Engines["brave"].Requests != 1 {
+		t.Error("expected the earlier snapshot to be unaffected")
+	}
+
+	stats = searcher.(EngineStatsReporter).Stats()
+	if got := stats.Engines["bing"]; got.Requests != 2 || got.Failures != 2 || got.Fallbacks != 1 {
+		t.Errorf("expected deep search failures to count without a fallback, got %+v", got)
+	}
+	if got := stats.Engines["brave"]; got.Requests != 2 || got.Successes != 2 {
+		t.Errorf("unexpected brave stats after deep search: %+v", got)
+	}
+}
+
+// copyingEngine returns a copy of its results, so concurrent searches
+// don't annotate the same slice
+type copyingEngine struct {
+	mockSearchEngine
+}
+
+func (c *copyingEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	results, err := c.mockSearchEngine.Search(ctx, query, maxResults)
+	return append([]SearchResult(nil), results...), err
+}
+
+func TestStats_Concurrent(t *testing.T) {
+	engine := &copyingEngine{mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Result", URL: "https://example.com"}}}}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": engine}, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			searcher.Search(context.Background(), "query", SearchOptions{MaxResults: 1})
+			searcher.(EngineStatsReporter).Stats()
+		}()
+	}
+	wg.Wait()
+
+	if got := searcher.(EngineStatsReporter).Stats().Engines["bing"]; got.Requests != 20 || got.Successes != 20 {
+		t.Errorf("expected 20 successful requests, got %+v", got)
+	}
+}
+
+func TestStats_Hybrid(t *testing.T) {
+	var _ EngineStatsReporter = &HybridMultiEngineSearcher{}
+
+	// A searcher built without a constructor reports empty stats
+	if stats := (&HybridMultiEngineSearcher{}).Stats(); len(stats.Engines) != 0 || stats.Fallbacks != 0 {
+		t.Errorf("expected empty stats, got %+v", stats)
+	}
+}
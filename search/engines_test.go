@@ -1,7 +1,11 @@
 package search
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
+
+	"github.com/liliang-cn/mcp-websearch-server/extraction"
 )
 
 func TestBingSearchEngine_Name(t *testing.T) {
@@ -25,6 +29,25 @@ func TestDuckDuckGoSearchEngine_Name(t *testing.T) {
 	}
 }
 
+func TestWithRemoteChrome(t *testing.T) {
+	t.Setenv(extraction.RemoteChromeEnv, "ws://chrome:9222")
+
+	engine := NewBingSearchEngine().(*bingSearchEngine)
+	if engine.config.remoteURL != "ws://chrome:9222" {
+		t.Errorf("expected the remote URL from the environment, got %q", engine.config.remoteURL)
+	}
+
+	brave := NewBraveSearchEngine(WithRemoteChrome("ws://other:9222")).(*braveSearchEngine)
+	if brave.config.remoteURL != "ws://other:9222" {
+		t.Errorf("expected the option to override the environment, got %q", brave.config.remoteURL)
+	}
+
+	ddg := NewDuckDuckGoSearchEngine(WithRemoteChrome("")).(*duckDuckGoSearchEngine)
+	if ddg.config.remoteURL != "" {
+		t.Errorf("expected an empty URL to launch locally, got %q", ddg.config.remoteURL)
+	}
+}
+
 func TestNewMultiEngineSearcher(t *testing.T) {
 	searcher := NewMultiEngineSearcher()
 	if searcher == nil {
@@ -36,8 +59,8 @@ func TestNewMultiEngineSearcher(t *testing.T) {
 		t.Fatal("expected HybridMultiEngineSearcher type")
 	}
 
-	if len(ms.engines) != 3 {
-		t.Errorf("expected 3 engines, got %d", len(ms.engines))
+	if len(ms.engines) != 8 {
+		t.Errorf("expected 8 engines, got %d", len(ms.engines))
 	}
 
 	if ms.engines["bing"] == nil {
@@ -52,7 +75,84 @@ func TestNewMultiEngineSearcher(t *testing.T) {
 		t.Error("expected duckduckgo engine to be present")
 	}
 
+	if ms.engines["google"] == nil {
+		t.Error("expected google engine to be present")
+	}
+
 	if ms.extractor == nil {
 		t.Error("expected extractor to be non-nil")
 	}
 }
+
+func TestNewMultiEngineSearcher_HTTPExtraction(t *testing.T) {
+	ms, ok := NewMultiEngineSearcher(WithHTTPExtraction(true)).(*multiEngineSearcher)
+	if !ok {
+		t.Fatal("expected a browser-free multiEngineSearcher")
+	}
+
+	if _, ok := ms.extractor.(*extraction.HTTPExtractor); !ok {
+		t.Errorf("expected an HTTPExtractor, got %T", ms.extractor)
+	}
+}
+
+func TestBingGoQueryEngine_Sitelinks(t *testing.T) {
+	doc := mustParseHTML(t, `
+		<ol id="b_results">
+			<li class="b_algo">
+				<h2><a href="https://go.dev/">The Go Programming Language</a></h2>
+				<div class="b_caption"><p>Go is an open source programming language.</p></div>
+				<div class="b_deep">
+					<ul class="b_vList">
+						<li><div class="b_deephead"><h3><a href="https://go.dev/doc/">Documentation</a></h3></div><p>Learn how to install and use Go.</p></li>
+						<li><div class="b_deephead"><h3><a href="https://go.dev/dl/">Downloads</a></h3></div><p>Download Go for your platform.</p></li>
+						<li><div class="b_deephead"><h3><a href="https://go.dev/">Home</a></h3></div></li>
+					</ul>
+				</div>
+			</li>
+			<li class="b_algo">
+				<h2><a href="https://pkg.go.dev/">Go Packages</a></h2>
+				<div class="b_caption"><p>Discover packages.</p></div>
+				<div class="b_algoSlinks"><ul>
+					<li><a href="https://pkg.go.dev/std">Standard library</a></li>
+					<li><a href="https://pkg.go.dev/about">About</a></li>
+				</ul></div>
+			</li>
+			<li class="b_algo">
+				<h2><a href="https://example.com/plain">Plain Result</a></h2>
+				<div class="b_caption"><p>No sitelinks here.</p></div>
+			</li>
+		</ol>`)
+
+	results := NewBingGoQueryEngine().(*bingGoQueryEngine).parseResults(doc, 10)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	deep := results[0].SubResults
+	if len(deep) != 2 {
+		t.Fatalf("expected 2 sitelinks excluding the link back to the result, got %+v", deep)
+	}
+	if deep[0].Title != "Documentation" || deep[0].URL != "https://go.dev/doc/" || deep[0].Snippet != "Learn how to install and use Go." {
+		t.Errorf("unexpected first sitelink: %+v", deep[0])
+	}
+	if deep[1].URL != "https://go.dev/dl/" || deep[1].Engine != "bing" {
+		t.Errorf("unexpected second sitelink: %+v", deep[1])
+	}
+
+	inline := results[1].SubResults
+	if len(inline) != 2 || inline[0].Title != "Standard library" || inline[1].URL != "https://pkg.go.dev/about" {
+		t.Errorf("unexpected inline sitelinks: %+v", inline)
+	}
+
+	if results[2].SubResults != nil {
+		t.Errorf("expected no sub-results, got %+v", results[2].SubResults)
+	}
+
+	data, err := json.Marshal(results[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"sub_results":[{"title":"Documentation"`) {
+		t.Errorf("expected sub-results in the structured output, got %s", data)
+	}
+}
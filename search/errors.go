@@ -0,0 +1,195 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInsufficientResults is matched by errors.Is when a search returns fewer
+// results than SearchOptions.MinResults.
+var ErrInsufficientResults = errors.New("insufficient search results")
+
+// InsufficientResultsError carries the partial results of a search that fell
+// short of SearchOptions.MinResults, so callers can decide whether to use
+// them or broaden the query.
+type InsufficientResultsError struct {
+	Want    int
+	Results []SearchResult
+}
+
+func (e *InsufficientResultsError) Error() string {
+	return fmt.Sprintf("%v: got %d, want at least %d", ErrInsufficientResults, len(e.Results), e.Want)
+}
+
+func (e *InsufficientResultsError) Unwrap() error {
+	return ErrInsufficientResults
+}
+
+// ErrQuotaExceeded is matched by errors.Is when a search is rejected because
+// the searcher's query quota is used up.
+var ErrQuotaExceeded = errors.New("search quota exceeded")
+
+// QuotaExceededError reports how long the caller should wait before the
+// quota window has room for another search.
+type QuotaExceededError struct {
+	RetryAfter time.Duration
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%v: retry after %s", ErrQuotaExceeded, e.RetryAfter.Round(time.Second))
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// ErrUnknownEngine is matched by errors.Is when SearchOptions.Engines names
+// an engine that isn't registered, even after alias resolution.
+var ErrUnknownEngine = errors.New("unknown search engine")
+
+// UnknownEngineError lists the engine names that couldn't be resolved
+type UnknownEngineError struct {
+	Names []string
+}
+
+func (e *UnknownEngineError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrUnknownEngine, strings.Join(e.Names, ", "))
+}
+
+func (e *UnknownEngineError) Unwrap() error {
+	return ErrUnknownEngine
+}
+
+// ErrResponseTooLarge is matched by errors.Is when a JSON or feed response
+// is bigger than the engine's maximum body size. Larger HTML pages are
+// truncated instead.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// ResponseTooLargeError reports the page that exceeded the size limit
+type ResponseTooLargeError struct {
+	URL   string
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("%v: %s exceeds %d bytes", ErrResponseTooLarge, e.URL, e.Limit)
+}
+
+func (e *ResponseTooLargeError) Unwrap() error {
+	return ErrResponseTooLarge
+}
+
+// ErrQueryTooLong is matched by errors.Is when a query is longer than the
+// searcher's maximum query length. It is returned before any engine is
+// contacted.
+var ErrQueryTooLong = errors.New("query too long")
+
+// QueryTooLongError reports the query's length and the limit it exceeded,
+// both in characters
+type QueryTooLongError struct {
+	Length int
+	Max    int
+}
+
+func (e *QueryTooLongError) Error() string {
+	return fmt.Sprintf("%v: %d characters, maximum is %d", ErrQueryTooLong, e.Length, e.Max)
+}
+
+func (e *QueryTooLongError) Unwrap() error {
+	return ErrQueryTooLong
+}
+
+// ErrEmptyQuery is returned when a query is empty once control characters
+// and surrounding whitespace are removed
+var ErrEmptyQuery = errors.New("empty search query")
+
+// ErrBlocked is matched by errors.Is when an engine answered with an
+// anti-bot or captcha page instead of results
+var ErrBlocked = errors.New("search engine blocked the request")
+
+// BlockedError reports the engine and results page that were blocked
+type BlockedError struct {
+	Engine string
+	URL    string
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("%v: %s served no results page for %s", ErrBlocked, e.Engine, e.URL)
+}
+
+func (e *BlockedError) Unwrap() error {
+	return ErrBlocked
+}
+
+// ErrNoResults is reported by HealthCheck for an engine that answered
+// without results, which usually means its page layout changed or it served
+// a block page that wasn't recognized as one
+var ErrNoResults = errors.New("search engine returned no results")
+
+// ErrRateLimited is matched by errors.Is when an engine rejected the
+// request for exceeding its rate limit, with a 429 or a 503
+var ErrRateLimited = errors.New("search engine rate limit exceeded")
+
+// RateLimitedError reports the rate-limited engine and, when it said, how
+// long to wait before retrying
+type RateLimitedError struct {
+	Engine     string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%v: %s, retry after %s", ErrRateLimited, e.Engine, e.RetryAfter)
+	}
+	return fmt.Sprintf("%v: %s", ErrRateLimited, e.Engine)
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// ErrNetwork is matched by errors.Is when an engine couldn't be reached at
+// all, such as on a DNS failure, a refused connection or a timeout
+var ErrNetwork = errors.New("search engine unreachable")
+
+// NetworkError reports the engine that couldn't be reached and why. Both
+// ErrNetwork and the underlying error match it with errors.Is.
+type NetworkError struct {
+	Engine string
+	Err    error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("%v: %s: %v", ErrNetwork, e.Engine, e.Err)
+}
+
+func (e *NetworkError) Unwrap() []error {
+	return []error{ErrNetwork, e.Err}
+}
+
+// recoverable reports whether another engine might answer a search that
+// failed with err. Queries every engine would reject, and searches over
+// quota, are not worth retrying elsewhere.
+func recoverable(err error) bool {
+	return !errors.Is(err, ErrQueryTooLong) && !errors.Is(err, ErrEmptyQuery) && !errors.Is(err, ErrQuotaExceeded)
+}
+
+// ErrUnsupportedVertical is matched by errors.Is when a vertical search,
+// such as news, names an engine that has no such vertical
+var ErrUnsupportedVertical = errors.New("search engine has no such vertical")
+
+// UnsupportedVerticalError lists the engines that can't search Vertical
+type UnsupportedVerticalError struct {
+	Vertical string
+	Engines  []string
+}
+
+func (e *UnsupportedVerticalError) Error() string {
+	return fmt.Sprintf("%v: %s has no %s search", ErrUnsupportedVertical, strings.Join(e.Engines, ", "), e.Vertical)
+}
+
+func (e *UnsupportedVerticalError) Unwrap() error {
+	return ErrUnsupportedVertical
+}
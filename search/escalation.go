@@ -0,0 +1,31 @@
+package search
+
+import (
+	"context"
+	"fmt"
+)
+
+// browserEngines returns the chromedp-based engines, created with opts, in
+// the hybrid searcher's priority order
+func browserEngines(opts ...BrowserEngineOption) []SearchEngine {
+	return []SearchEngine{
+		NewDuckDuckGoSearchEngine(opts...),
+		NewBingSearchEngine(opts...),
+		NewBraveSearchEngine(opts...),
+	}
+}
+
+// escalateSearch runs q on each engine in turn and returns the first
+// non-empty results
+func escalateSearch(ctx context.Context, engines []SearchEngine, q EngineQuery) ([]SearchResult, error) {
+	for _, engine := range engines {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		results, err := searchEngine(ctx, engine, q)
+		if err == nil && len(results) > 0 {
+			return results, nil
+		}
+	}
+	return nil, fmt.Errorf("no results from browser engines")
+}
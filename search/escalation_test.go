@@ -0,0 +1,67 @@
+package search
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHybridSearcher_BrowserEscalation(t *testing.T) {
+	goquery := map[string]*mockSearchEngine{
+		"bing":       {name: "bing"},
+		"brave":      {name: "brave"},
+		"duckduckgo": {name: "duckduckgo"},
+	}
+	chromedp := &mockSearchEngine{
+		name:    "bing",
+		results: []SearchResult{{Title: "Rendered", URL: "http://rendered.com", Engine: "bing"}},
+	}
+
+	searcher := &HybridMultiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing":       goquery["bing"],
+			"brave":      goquery["brave"],
+			"duckduckgo": goquery["duckduckgo"],
+		},
+		escalation: []SearchEngine{chromedp},
+	}
+
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{MaxResults: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].URL != "http://rendered.com" {
+		t.Fatalf("expected results from the browser engine, got %v", results)
+	}
+	if chromedp.calls != 1 {
+		t.Errorf("expected the browser engine to be queried once, got %d", chromedp.calls)
+	}
+
+}
+
+func TestEscalateSearch_KeepsOffset(t *testing.T) {
+	browser := &mockSearchEngine{
+		name: "bing",
+		results: []SearchResult{
+			{Title: "First", URL: "http://first.com", Engine: "bing"},
+			{Title: "Second", URL: "http://second.com", Engine: "bing"},
+			{Title: "Third", URL: "http://third.com", Engine: "bing"},
+		},
+	}
+
+	results, err := escalateSearch(context.Background(), []SearchEngine{browser}, EngineQuery{Query: "test", MaxResults: 1, Offset: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].URL != "http://third.com" {
+		t.Errorf("expected the page at offset 2, got %v", results)
+	}
+}
+
+func TestHybridSearcher_NoEscalationByDefault(t *testing.T) {
+	if h := NewHybridSearcher().(*HybridMultiEngineSearcher); len(h.escalation) != 0 {
+		t.Error("expected escalation to be off by default")
+	}
+	if h := NewHybridSearcher(WithBrowserEscalation(true)).(*HybridMultiEngineSearcher); len(h.escalation) != 3 {
+		t.Errorf("expected 3 browser engines, got %d", len(h.escalation))
+	}
+}
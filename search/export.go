@@ -0,0 +1,52 @@
+package search
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// ExportJSONL writes results as JSON Lines, one result object per line,
+// flushing after each so a consumer can process them incrementally
+func ExportJSONL(w io.Writer, results []SearchResult) error {
+	enc := json.NewEncoder(w)
+	for _, r := range results {
+		if err := writeJSONLine(enc, w, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StreamJSONL writes results as JSON Lines as they arrive on the channel,
+// flushing after each, until the channel is closed. It returns the number
+// of results written. On a write error the channel is left undrained, so
+// producers should stop when their context is cancelled.
+func StreamJSONL(w io.Writer, results <-chan SearchResult) (int, error) {
+	enc := json.NewEncoder(w)
+	n := 0
+	for r := range results {
+		if err := writeJSONLine(enc, w, r); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// writeJSONLine encodes r as a single line and flushes w if it buffers
+func writeJSONLine(enc *json.Encoder, w io.Writer, r SearchResult) error {
+	// Encode terminates each value with a newline and never indents, so
+	// every result is exactly one line
+	if err := enc.Encode(r); err != nil {
+		return err
+	}
+
+	switch f := w.(type) {
+	case interface{ Flush() error }: // e.g. *bufio.Writer
+		return f.Flush()
+	case http.Flusher:
+		f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExportJSONL(t *testing.T) {
+	results := []SearchResult{
+		{Title: "First", URL: "https://a.example.com", Snippet: "line one\nline two"},
+		{Title: "Second", URL: "https://b.example.com", Content: "# Heading\n\nBody"},
+		{Title: "Third", URL: "https://c.example.com"},
+	}
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	if err := ExportJSONL(w, results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(results) {
+		t.Fatalf("expected %d lines, got %d", len(results), len(lines))
+	}
+
+	for i, line := range lines {
+		var r SearchResult
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i, err)
+		}
+		if r.URL != results[i].URL {
+			t.Errorf("line %d: expected %s, got %s", i, results[i].URL, r.URL)
+		}
+	}
+}
+
+func TestStreamJSONL(t *testing.T) {
+	ch := make(chan SearchResult)
+	go func() {
+		defer close(ch)
+		for _, u := range []string{"https://a.example.com", "https://b.example.com"} {
+			ch <- SearchResult{URL: u}
+		}
+	}()
+
+	var buf bytes.Buffer
+	n, err := StreamJSONL(&buf, ch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2 || strings.Count(buf.String(), "\n") != 2 {
+		t.Errorf("expected 2 lines, wrote %d: %q", n, buf.String())
+	}
+}
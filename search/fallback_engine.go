@@ -0,0 +1,55 @@
+package search
+
+import (
+	"context"
+	"fmt"
+)
+
+// fallbackEngine queries a fast primary engine and falls back to a heavier
+// secondary implementation of the same engine when the primary fails or
+// returns nothing, which usually means the page needed JavaScript
+type fallbackEngine struct {
+	primary   SearchEngine
+	secondary SearchEngine
+}
+
+// NewFallbackEngine wraps primary so that secondary is tried whenever
+// primary errors or returns zero results. The engine reports primary's name.
+func NewFallbackEngine(primary, secondary SearchEngine) SearchEngine {
+	return &fallbackEngine{
+		primary:   primary,
+		secondary: secondary,
+	}
+}
+
+func (f *fallbackEngine) Name() string {
+	return f.primary.Name()
+}
+
+func (f *fallbackEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	return f.SearchQuery(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+// SearchQuery passes the full query on to both engines
+func (f *fallbackEngine) SearchQuery(ctx context.Context, q EngineQuery) ([]SearchResult, error) {
+	results, err := searchEngine(ctx, f.primary, q)
+	if err == nil && len(results) > 0 {
+		return results, nil
+	}
+
+	// The browser-based engines have no verticals, and would answer with
+	// web results
+	if q.Vertical != "" {
+		return results, err
+	}
+
+	fallback, fallbackErr := searchEngine(ctx, f.secondary, q)
+	if fallbackErr != nil {
+		if err != nil {
+			return nil, fmt.Errorf("%s failed: %w (fallback: %v)", f.Name(), err, fallbackErr)
+		}
+		return nil, fallbackErr
+	}
+
+	return fallback, nil
+}
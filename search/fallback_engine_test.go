@@ -0,0 +1,74 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFallbackEngine_EmptyPrimary(t *testing.T) {
+	goquery := &mockSearchEngine{name: "bing"}
+	chromedp := &mockSearchEngine{
+		name:    "bing",
+		results: []SearchResult{{Title: "Rendered", URL: "http://rendered.com", Engine: "bing"}},
+	}
+
+	engine := NewFallbackEngine(goquery, chromedp)
+	results, err := engine.Search(context.Background(), "test", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 1 || results[0].URL != "http://rendered.com" {
+		t.Errorf("expected fallback results, got %v", results)
+	}
+	if goquery.calls != 1 || chromedp.calls != 1 {
+		t.Errorf("expected both engines to be queried once, got %d and %d", goquery.calls, chromedp.calls)
+	}
+}
+
+func TestFallbackEngine_PrimarySucceeds(t *testing.T) {
+	goquery := &mockSearchEngine{
+		name:    "bing",
+		results: []SearchResult{{Title: "Fast", URL: "http://fast.com"}},
+	}
+	chromedp := &mockSearchEngine{name: "bing"}
+
+	engine := NewFallbackEngine(goquery, chromedp)
+	results, err := engine.Search(context.Background(), "test", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 1 || results[0].URL != "http://fast.com" {
+		t.Errorf("expected primary results, got %v", results)
+	}
+	if chromedp.calls != 0 {
+		t.Error("expected fallback not to be queried when primary has results")
+	}
+}
+
+func TestFallbackEngine_BothFail(t *testing.T) {
+	engine := NewFallbackEngine(
+		&mockSearchEngine{name: "bing", err: errors.New("blocked")},
+		&mockSearchEngine{name: "bing", err: errors.New("browser crashed")},
+	)
+
+	if _, err := engine.Search(context.Background(), "test", 5); err == nil {
+		t.Error("expected an error when both variants fail")
+	}
+
+	if engine.Name() != "bing" {
+		t.Errorf("expected primary's name, got %s", engine.Name())
+	}
+}
+
+func TestNewHybridSearcherWithBrowserFallback(t *testing.T) {
+	searcher := NewHybridSearcherWithBrowserFallback().(*HybridMultiEngineSearcher)
+
+	for _, name := range []string{"bing", "brave", "duckduckgo"} {
+		if _, ok := searcher.engines[name].(*fallbackEngine); !ok {
+			t.Errorf("expected %s to be a fallback engine", name)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package search
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// parseLimitedBody reads at most limit bytes of an HTML response body and
+// parses it. A larger body is cut off at the limit, without being read any
+// further, and parsed as far as it got; the HTML parser copes with the
+// unclosed elements, and a warning is logged.
+func parseLimitedBody(body io.Reader, limit int64, url string) (*goquery.Document, error) {
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		log.Printf("warning: %s exceeds %d bytes, parsing the truncated page", url, limit)
+		data = data[:limit]
+	}
+	return goquery.NewDocumentFromReader(bytes.NewReader(data))
+}
+
+// readLimitedBody reads at most limit bytes of a response body, failing
+// with a *ResponseTooLargeError if there is more. It is for JSON and feed
+// responses, which can't be parsed once truncated.
+func readLimitedBody(body io.Reader, limit int64, url string) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, &ResponseTooLargeError{URL: url, Limit: limit}
+	}
+	return data, nil
+}
+
+// captchaMarkers match the anti-bot and captcha pages engines serve in
+// place of results
+const captchaMarkers = `form[action*="captcha"], #captcha, .g-recaptcha, iframe[src*="recaptcha"], iframe[src*="hcaptcha"], #challenge-form, .anomaly-modal__title`
+
+// fetchResultsPage sends an engine's results page request. A failure to
+// reach the engine is a *NetworkError, and a 429 or 503 a
+// *RateLimitedError; the caller closes the body of any other response.
+func fetchResultsPage(client *http.Client, req *http.Request, engine string) (*http.Response, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &NetworkError{Engine: engine, Err: err}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		resp.Body.Close()
+		return nil, &RateLimitedError{Engine: engine, RetryAfter: retryAfter(resp.Header.Get("Retry-After"))}
+	}
+	return resp, nil
+}
+
+// retryAfter parses a Retry-After header given in seconds, returning zero
+// if it is missing or an HTTP date
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(header))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// captchaPage reports whether doc is an anti-bot or captcha page rather
+// than results
+func captchaPage(doc *goquery.Document) bool {
+	return doc.Find(captchaMarkers).Length() > 0
+}
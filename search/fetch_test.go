@@ -0,0 +1,172 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLimitedBody_StopsAtLimit(t *testing.T) {
+	// Stream results forever; the reader must give up at the limit
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := []byte(strings.Repeat("<p>result</p>", 100))
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			default:
+			}
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	type parsed struct {
+		results int
+		err     error
+	}
+	done := make(chan parsed, 1)
+	go func() {
+		doc, err := parseLimitedBody(resp.Body, 64<<10, server.URL)
+		if err != nil {
+			done <- parsed{err: err}
+			return
+		}
+		done <- parsed{results: doc.Find("p").Length()}
+	}()
+
+	select {
+	case got := <-done:
+		if got.err != nil {
+			t.Fatalf("expected the truncated page to parse, got %v", got.err)
+		}
+		// The paragraph cut off at the limit still parses
+		if max := (64<<10)/len("<p>result</p>") + 1; got.results == 0 || got.results > max {
+			t.Errorf("expected at most %d paragraphs from the first 64KB, got %d", max, got.results)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("parseLimitedBody did not stop at the size limit")
+	}
+}
+
+func TestReadLimitedBody_TooLarge(t *testing.T) {
+	_, err := readLimitedBody(strings.NewReader(strings.Repeat("x", 2048)), 1024, "https://example.com/feed")
+
+	var tooLarge *ResponseTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("expected *ResponseTooLargeError, got %v", err)
+	}
+	if !errors.Is(err, ErrResponseTooLarge) || tooLarge.Limit != 1024 {
+		t.Errorf("unexpected error details: %v", err)
+	}
+
+	if data, err := readLimitedBody(strings.NewReader("small"), 1024, "test"); err != nil || string(data) != "small" {
+		t.Errorf("expected a body within the limit to be read whole, got %q, %v", data, err)
+	}
+}
+
+func TestParseLimitedBody_WithinLimit(t *testing.T) {
+	doc, err := parseLimitedBody(strings.NewReader(`<div class="b_algo"><h2><a href="https://example.com">Example</a></h2></div>`), 1024, "test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := NewBingGoQueryEngine().(*bingGoQueryEngine).parseResults(doc, 10)
+	if len(results) != 1 {
+		t.Errorf("expected 1 result, got %d", len(results))
+	}
+}
+
+func TestWithMaxBodySize(t *testing.T) {
+	if c := newEngineConfig(Selectors{}, nil); c.maxBodySize != defaultMaxBodySize {
+		t.Errorf("expected default limit %d, got %d", defaultMaxBodySize, c.maxBodySize)
+	}
+	if c := newEngineConfig(Selectors{}, []EngineOption{WithMaxBodySize(1 << 20)}); c.maxBodySize != 1<<20 {
+		t.Errorf("expected 1MB limit, got %d", c.maxBodySize)
+	}
+}
+
+// responseTransport answers every request with status, headers and body
+type responseTransport struct {
+	status int
+	header http.Header
+	body   string
+}
+
+func (s *responseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: s.status,
+		Header:     s.header,
+		Body:       io.NopCloser(strings.NewReader(s.body)),
+		Request:    req,
+	}, nil
+}
+
+// errTransport fails every request with err
+type errTransport struct {
+	err error
+}
+
+func (e errTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, e.err
+}
+
+func TestGoQueryEngines_ClassifyFailures(t *testing.T) {
+	engines := map[string]func(http.RoundTripper) SearchEngine{
+		"duckduckgo": func(tr http.RoundTripper) SearchEngine {
+			e := NewDuckDuckGoGoQueryEngine()
+			e.(*duckDuckGoGoQueryEngine).client.Transport = tr
+			return e
+		},
+		"bing": func(tr http.RoundTripper) SearchEngine {
+			e := NewBingGoQueryEngine()
+			e.(*bingGoQueryEngine).client.Transport = tr
+			return e
+		},
+	}
+	dnsErr := &net.DNSError{Err: "no such host", Name: "example.invalid"}
+
+	for name, newEngine := range engines {
+		t.Run(name, func(t *testing.T) {
+			_, err := newEngine(&responseTransport{status: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"12"}}}).Search(context.Background(), "golang", 5)
+			var limited *RateLimitedError
+			if !errors.As(err, &limited) || limited.RetryAfter != 12*time.Second || limited.Engine != name {
+				t.Errorf("expected a *RateLimitedError for a 429, got %v", err)
+			}
+
+			if _, err := newEngine(&responseTransport{status: http.StatusServiceUnavailable}).Search(context.Background(), "golang", 5); !errors.Is(err, ErrRateLimited) {
+				t.Errorf("expected ErrRateLimited for a 503, got %v", err)
+			}
+
+			captcha := `<html><body><form id="challenge-form" action="/captcha"><div class="g-recaptcha"></div></form></body></html>`
+			if _, err := newEngine(&responseTransport{status: http.StatusOK, body: captcha}).Search(context.Background(), "golang", 5); !errors.Is(err, ErrBlocked) {
+				t.Errorf("expected ErrBlocked for a captcha page, got %v", err)
+			}
+
+			_, err = newEngine(errTransport{dnsErr}).Search(context.Background(), "golang", 5)
+			if !errors.Is(err, ErrNetwork) || !errors.Is(err, dnsErr) {
+				t.Errorf("expected ErrNetwork wrapping the DNS error, got %v", err)
+			}
+
+			// An empty results page is not an error
+			if results, err := newEngine(&responseTransport{status: http.StatusOK, body: `<html><body></body></html>`}).Search(context.Background(), "golang", 5); err != nil || len(results) != 0 {
+				t.Errorf("expected no results and no error, got %v, %v", results, err)
+			}
+		})
+	}
+}
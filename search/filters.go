@@ -0,0 +1,156 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ResultFilters drops low-quality results before content is extracted
+type ResultFilters struct {
+	// MinSnippetLength drops results whose snippet has fewer characters
+	MinSnippetLength int
+	// ExcludeTitlePatterns drops results whose title matches any of these
+	// regular expressions
+	ExcludeTitlePatterns []string
+	// RelaxIfEmpty retries with looser filters when they would remove
+	// every result: first without MinSnippetLength, then also without
+	// ExcludeTitlePatterns. Results kept this way have FiltersRelaxed set.
+	RelaxIfEmpty bool
+}
+
+// DefaultFilterOvershoot is how many times MaxResults are fetched from the
+// engines when filters are set or content is required
+const DefaultFilterOvershoot = 2
+
+// overshootResults returns how many results to ask the engines for so that
+// maxResults are likely to survive opts' filters and RequireContent
+func overshootResults(maxResults int, opts SearchOptions, factor int) int {
+	if !(opts.Filters.active() || opts.RequireContent || filtersDomains(opts)) || factor <= 1 {
+		return maxResults
+	}
+	return maxResults * factor
+}
+
+// withContent returns the results whose content was extracted, live or
+// from the archive
+func withContent(results []SearchResult) []SearchResult {
+	var kept []SearchResult
+	for _, r := range results {
+		if strings.TrimSpace(r.Content) != "" {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// capResults truncates results to maxResults; zero means no cap
+func capResults(results []SearchResult, maxResults int) []SearchResult {
+	if maxResults > 0 && len(results) > maxResults {
+		return results[:maxResults]
+	}
+	return results
+}
+
+// active reports whether any filter is set
+func (f ResultFilters) active() bool {
+	return f.MinSnippetLength > 0 || len(f.ExcludeTitlePatterns) > 0
+}
+
+// filterResults applies f to results, relaxing it if allowed and needed.
+// It fails only on an invalid title pattern.
+func filterResults(results []SearchResult, f ResultFilters) ([]SearchResult, error) {
+	if !f.active() || len(results) == 0 {
+		return results, nil
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(f.ExcludeTitlePatterns))
+	for _, p := range f.ExcludeTitlePatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid title pattern %q: %w", p, err)
+		}
+		patterns = append(patterns, re)
+	}
+
+	kept := applyFilters(results, f.MinSnippetLength, patterns)
+	if len(kept) > 0 || !f.RelaxIfEmpty {
+		return kept, nil
+	}
+
+	// Drop the length filter, then the title filter, until something passes
+	for _, relaxed := range [][]*regexp.Regexp{patterns, nil} {
+		if kept = applyFilters(results, 0, relaxed); len(kept) > 0 {
+			break
+		}
+	}
+	for i := range kept {
+		kept[i].FiltersRelaxed = true
+	}
+	return kept, nil
+}
+
+// applyFilters returns the results with a snippet of at least minLength
+// characters and a title matching none of patterns
+func applyFilters(results []SearchResult, minLength int, patterns []*regexp.Regexp) []SearchResult {
+	var kept []SearchResult
+	for _, r := range results {
+		if len([]rune(strings.TrimSpace(r.Snippet))) < minLength || matchesAny(patterns, r.Title) {
+			continue
+		}
+		kept = append(kept, r)
+	}
+	return kept
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// filtersDomains reports whether opts restricts result domains
+func filtersDomains(opts SearchOptions) bool {
+	return len(opts.AllowDomains) > 0 || len(opts.BlockDomains) > 0
+}
+
+// filterDomains drops the results outside opts.AllowDomains, when set, and
+// those in opts.BlockDomains. A domain matches its subdomains too.
+func filterDomains(results []SearchResult, opts SearchOptions) []SearchResult {
+	if !filtersDomains(opts) {
+		return results
+	}
+
+	allow := normalizeDomains(opts.AllowDomains)
+	block := normalizeDomains(opts.BlockDomains)
+
+	var kept []SearchResult
+	for _, r := range results {
+		host := resultDomain(r.URL)
+		if host == "" || hostMatches(host, block) {
+			continue
+		}
+		if len(allow) > 0 && !hostMatches(host, allow) {
+			continue
+		}
+		kept = append(kept, r)
+	}
+	return kept
+}
+
+// normalizeDomains lowercases domains and strips the leading "www." or "."
+// that resultDomain leaves off hosts, skipping empty entries
+func normalizeDomains(domains []string) []string {
+	normalized := make([]string, 0, len(domains))
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		d = strings.TrimPrefix(strings.TrimPrefix(d, "."), "www.")
+		if d != "" {
+			normalized = append(normalized, d)
+		}
+	}
+	return normalized
+}
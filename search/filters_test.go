@@ -0,0 +1,226 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSearch_FiltersRelaxWhenEmpty(t *testing.T) {
+	engine := &mockSearchEngine{name: "bing", results: []SearchResult{
+		{Title: "Sponsored: Buy golang books", URL: "http://ads.example.com", Snippet: "Buy now", Engine: "bing"},
+		{Title: "Golang tour", URL: "http://tour.example.com", Snippet: "Short", Engine: "bing"},
+		{Title: "Sponsored: Go course", URL: "http://course.example.com", Snippet: "Learn Go in a weekend with our paid course", Engine: "bing"},
+	}}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": engine}, nil)
+
+	strict := ResultFilters{
+		MinSnippetLength:     50,
+		ExcludeTitlePatterns: []string{`^Sponsored:`},
+	}
+
+	// Without relaxation the strict filters leave nothing
+	results, err := searcher.Search(context.Background(), "golang", SearchOptions{MaxResults: 3, Filters: strict})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected strict filters to remove everything, got %d results", len(results))
+	}
+
+	// Relaxing drops the length filter but keeps excluding sponsored titles
+	strict.RelaxIfEmpty = true
+	results, err = searcher.Search(context.Background(), "golang", SearchOptions{MaxResults: 3, Filters: strict})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].URL != "http://tour.example.com" {
+		t.Fatalf("expected only the non-sponsored result, got %+v", results)
+	}
+	if !results[0].FiltersRelaxed {
+		t.Error("expected the result to be flagged as passing relaxed filters")
+	}
+}
+
+func TestFilterResults(t *testing.T) {
+	results := []SearchResult{
+		{Title: "Sponsored: ad", Snippet: "A long enough snippet about the topic"},
+		{Title: "Good result", Snippet: "A long enough snippet about the topic"},
+		{Title: "Thin result", Snippet: "Too short"},
+	}
+
+	kept, err := filterResults(results, ResultFilters{MinSnippetLength: 20, ExcludeTitlePatterns: []string{`^Sponsored`}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(kept) != 1 || kept[0].Title != "Good result" || kept[0].FiltersRelaxed {
+		t.Errorf("expected only the good result, unrelaxed, got %+v", kept)
+	}
+
+	// When both relaxation steps are needed, titles are no longer filtered
+	kept, _ = filterResults(results[:1], ResultFilters{MinSnippetLength: 100, ExcludeTitlePatterns: []string{`^Sponsored`}, RelaxIfEmpty: true})
+	if len(kept) != 1 || !kept[0].FiltersRelaxed {
+		t.Errorf("expected the fully relaxed filters to keep the ad, got %+v", kept)
+	}
+
+	if _, err := filterResults(results, ResultFilters{ExcludeTitlePatterns: []string{"("}}); err == nil {
+		t.Error("expected an error for an invalid title pattern")
+	}
+}
+
+func TestSearch_FilterOvershoot(t *testing.T) {
+	// Every other result has a snippet too short to pass the filter
+	var fetched []SearchResult
+	for i := 0; i < 10; i++ {
+		snippet := "A detailed snippet describing the page"
+		if i%2 == 1 {
+			snippet = "Short"
+		}
+		fetched = append(fetched, SearchResult{Title: fmt.Sprintf("Result %d", i), URL: fmt.Sprintf("http://example.com/%d", i), Snippet: snippet, Engine: "bing"})
+	}
+	filters := ResultFilters{MinSnippetLength: 20}
+
+	engine := &mockSearchEngine{name: "bing", results: fetched}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": engine}, nil)
+
+	results, err := searcher.Search(context.Background(), "golang", SearchOptions{MaxResults: 4, Filters: filters})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected over-fetching to still return 4 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Snippet == "Short" {
+			t.Errorf("expected %s to be filtered out", r.URL)
+		}
+	}
+
+	// Without over-fetching only half the requested results survive
+	exact := NewSearcherWithEngines(map[string]SearchEngine{"bing": engine}, nil, WithFilterOvershoot(1))
+	results, _ = exact.Search(context.Background(), "golang", SearchOptions{MaxResults: 4, Filters: filters})
+	if len(results) != 2 {
+		t.Errorf("expected 2 results without over-fetching, got %d", len(results))
+	}
+}
+
+// failingPathExtractor fails to extract pages whose URL contains "/fail"
+type failingPathExtractor struct{}
+
+func (failingPathExtractor) ExtractContent(ctx context.Context, url string) (string, error) {
+	if strings.Contains(url, "/fail") {
+		return "", errors.New("extraction failed")
+	}
+	return "content of " + url, nil
+}
+
+func TestDeepSearch_RequireContent(t *testing.T) {
+	engineWithResults := func(name string) *mockSearchEngine {
+		engine := &mockSearchEngine{name: name}
+		for i := 0; i < 4; i++ {
+			path := "ok"
+			if i%2 == 0 {
+				path = "fail"
+			}
+			engine.results = append(engine.results, SearchResult{Title: fmt.Sprintf("%s %d", name, i), URL: fmt.Sprintf("http://%s.example.com/%s/%d", name, path, i), Engine: name})
+		}
+		return engine
+	}
+
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{
+		"bing":  engineWithResults("bing"),
+		"brave": engineWithResults("brave"),
+	}, failingPathExtractor{})
+
+	results, err := searcher.DeepSearch(context.Background(), "test", SearchOptions{
+		MaxResults:     4,
+		Engines:        []string{"bing", "brave"},
+		RequireContent: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("expected over-fetching to still fill 4 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Content == "" || r.ExtractError != "" {
+			t.Errorf("expected only results with content, got %+v", r)
+		}
+	}
+
+	// Without the option, failed extractions are kept
+	results, _ = searcher.DeepSearch(context.Background(), "test", SearchOptions{
+		MaxResults:     4,
+		Engines:        []string{"bing", "brave"},
+		ExtractContent: true,
+	})
+	failed := 0
+	for _, r := range results {
+		if r.Content == "" {
+			failed++
+		}
+	}
+	if failed == 0 {
+		t.Error("expected failed extractions to be kept by default")
+	}
+}
+
+func TestFilterDomains(t *testing.T) {
+	results := []SearchResult{
+		{URL: "https://www.example.com/a"},
+		{URL: "https://news.example.com/b"},
+		{URL: "https://notexample.com/c"},
+		{URL: "https://www.pinterest.com/pin/1"},
+		{URL: "https://golang.org/doc"},
+	}
+
+	tests := []struct {
+		name string
+		opts SearchOptions
+		want []string
+	}{
+		{"none", SearchOptions{}, []string{"https://www.example.com/a", "https://news.example.com/b", "https://notexample.com/c", "https://www.pinterest.com/pin/1", "https://golang.org/doc"}},
+		{"block", SearchOptions{BlockDomains: []string{"pinterest.com"}}, []string{"https://www.example.com/a", "https://news.example.com/b", "https://notexample.com/c", "https://golang.org/doc"}},
+		{"allow", SearchOptions{AllowDomains: []string{"Example.com"}}, []string{"https://www.example.com/a", "https://news.example.com/b"}},
+		{"both", SearchOptions{AllowDomains: []string{"example.com", "golang.org"}, BlockDomains: []string{"news.example.com"}}, []string{"https://www.example.com/a", "https://golang.org/doc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, r := range filterDomains(results, tt.opts) {
+				got = append(got, r.URL)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSearch_DomainsFilteredBeforeExtraction(t *testing.T) {
+	engine := &mockSearchEngine{name: "bing", results: []SearchResult{
+		{Title: "Pin", URL: "https://www.pinterest.com/pin/1", Engine: "bing"},
+		{Title: "Docs", URL: "https://go.dev/doc", Engine: "bing"},
+	}}
+	extractor := &countingExtractor{}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": engine}, extractor)
+
+	results, err := searcher.Search(context.Background(), "query", SearchOptions{
+		MaxResults:     5,
+		ExtractContent: true,
+		BlockDomains:   []string{"pinterest.com"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].URL != "https://go.dev/doc" {
+		t.Errorf("expected only the go.dev result, got %v", results)
+	}
+	if len(extractor.urls) != 1 {
+		t.Errorf("expected the blocked page not to be extracted, got %v", extractor.urls)
+	}
+}
@@ -0,0 +1,90 @@
+package search
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PageType is a coarse classification of a result page, used to suggest how
+// long clients may cache it
+type PageType string
+
+const (
+	PageTypeNews      PageType = "news"
+	PageTypeReference PageType = "reference"
+	PageTypeGeneral   PageType = "general"
+)
+
+// defaultResultTTLs are the suggested cache lifetimes per page type. News
+// goes stale quickly, reference material rarely changes.
+var defaultResultTTLs = map[PageType]time.Duration{
+	PageTypeNews:      15 * time.Minute,
+	PageTypeGeneral:   6 * time.Hour,
+	PageTypeReference: 7 * 24 * time.Hour,
+}
+
+var (
+	newsHosts = []string{"reuters.com", "apnews.com", "bbc.com", "bbc.co.uk", "cnn.com", "nytimes.com", "theguardian.com"}
+
+	referenceHosts = []string{"wikipedia.org", "pkg.go.dev", "developer.mozilla.org", "docs.python.org"}
+
+	referencePaths = []string{"/docs/", "/doc/", "/wiki/", "/reference/", "/manual/", "/api/"}
+
+	// datedPath matches article URLs such as /2024/03/15/story
+	datedPath = regexp.MustCompile(`/(19|20)\d{2}/\d{1,2}/`)
+)
+
+// DetectPageType classifies a result as news, reference or general from its
+// URL
+func DetectPageType(r SearchResult) PageType {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return PageTypeGeneral
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	path := strings.ToLower(u.Path)
+
+	if strings.HasPrefix(host, "news.") || hostMatches(host, newsHosts) ||
+		strings.Contains(path, "/news/") || datedPath.MatchString(path) {
+		return PageTypeNews
+	}
+
+	if strings.HasPrefix(host, "docs.") || hostMatches(host, referenceHosts) {
+		return PageTypeReference
+	}
+	for _, p := range referencePaths {
+		if strings.Contains(path, p) {
+			return PageTypeReference
+		}
+	}
+
+	return PageTypeGeneral
+}
+
+// hostMatches reports whether host is one of domains or a subdomain of one
+func hostMatches(host string, domains []string) bool {
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// annotateFreshness stamps each result with its page type, fetch time and
+// suggested TTL. Page types missing from ttls use the defaults.
+func annotateFreshness(results []SearchResult, ttls map[PageType]time.Duration, fetchedAt time.Time) {
+	for i := range results {
+		pageType := DetectPageType(results[i])
+		ttl, ok := ttls[pageType]
+		if !ok {
+			ttl = defaultResultTTLs[pageType]
+		}
+
+		results[i].PageType = pageType
+		results[i].FetchedAt = fetchedAt
+		results[i].TTLSeconds = int(ttl / time.Second)
+	}
+}
@@ -0,0 +1,88 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDetectPageType(t *testing.T) {
+	tests := []struct {
+		url  string
+		want PageType
+	}{
+		{"https://www.reuters.com/markets/stocks", PageTypeNews},
+		{"https://news.ycombinator.com/item?id=1", PageTypeNews},
+		{"https://example.com/2024/03/15/launch", PageTypeNews},
+		{"https://en.wikipedia.org/wiki/Go_(programming_language)", PageTypeReference},
+		{"https://docs.example.com/start", PageTypeReference},
+		{"https://example.com/docs/install", PageTypeReference},
+		{"https://example.com/pricing", PageTypeGeneral},
+	}
+
+	for _, tt := range tests {
+		if got := DetectPageType(SearchResult{URL: tt.url}); got != tt.want {
+			t.Errorf("DetectPageType(%s) = %s, want %s", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSearch_TTLInStructuredOutput(t *testing.T) {
+	mockEngine := &mockSearchEngine{
+		name: "bing",
+		results: []SearchResult{
+			{Title: "Breaking", URL: "https://www.bbc.com/news/world-1", Engine: "bing"},
+			{Title: "Encyclopedia", URL: "https://en.wikipedia.org/wiki/Topic", Engine: "bing"},
+		},
+	}
+
+	searcher := &multiEngineSearcher{
+		engines:   map[string]SearchEngine{"bing": mockEngine},
+		extractor: &mockContentExtractor{},
+	}
+
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{MaxResults: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	var decoded []struct {
+		PageType   string    `json:"page_type"`
+		FetchedAt  time.Time `json:"fetched_at"`
+		TTLSeconds int       `json:"ttl_seconds"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	news, reference := decoded[0], decoded[1]
+	if news.PageType != "news" || reference.PageType != "reference" {
+		t.Fatalf("unexpected page types %q and %q", news.PageType, reference.PageType)
+	}
+	if news.TTLSeconds >= reference.TTLSeconds {
+		t.Errorf("expected news TTL (%d) to be shorter than reference TTL (%d)", news.TTLSeconds, reference.TTLSeconds)
+	}
+	if news.FetchedAt.IsZero() {
+		t.Error("expected fetched_at to be set")
+	}
+}
+
+func TestWithResultTTL(t *testing.T) {
+	o := applySearcherOptions([]SearcherOption{WithResultTTL(PageTypeNews, time.Minute)})
+
+	results := []SearchResult{{URL: "https://apnews.com/article/x"}, {URL: "https://example.com/"}}
+	annotateFreshness(results, o.ttls, time.Now())
+
+	if results[0].TTLSeconds != 60 {
+		t.Errorf("expected overridden news TTL of 60s, got %d", results[0].TTLSeconds)
+	}
+	if results[1].TTLSeconds != int(defaultResultTTLs[PageTypeGeneral]/time.Second) {
+		t.Errorf("expected default general TTL, got %d", results[1].TTLSeconds)
+	}
+}
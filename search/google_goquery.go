@@ -0,0 +1,169 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type googleGoQueryEngine struct {
+	client *http.Client
+	config engineConfig
+}
+
+// DefaultGoogleSelectors are the selectors used to parse Google results
+// pages. .yuRUbf wraps the organic result link and .VwiC3b holds the
+// snippet; the plain fallbacks match the no-JavaScript layout.
+var DefaultGoogleSelectors = Selectors{
+	Result:  "div.g",
+	Title:   []string{"h3"},
+	Link:    []string{".yuRUbf a", "a"},
+	Snippet: []string{".VwiC3b", ".IsZvec", ".st"},
+
+	TotalCount: "#result-stats",
+}
+
+func NewGoogleGoQueryEngine(opts ...EngineOption) SearchEngine {
+	config := newEngineConfig(DefaultGoogleSelectors, opts)
+	return &googleGoQueryEngine{
+		client: config.applyProxy(&http.Client{
+			Timeout: 10 * time.Second,
+		}),
+		config: config,
+	}
+}
+
+func (g *googleGoQueryEngine) Name() string {
+	return "google"
+}
+
+func (g *googleGoQueryEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	return g.SearchQuery(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+// SearchQuery is Search with the full set of per-search parameters
+func (g *googleGoQueryEngine) SearchQuery(ctx context.Context, q EngineQuery) ([]SearchResult, error) {
+	results, _, err := g.search(ctx, q)
+	return results, err
+}
+
+// SearchWithStats is Search that also reports the total-results estimate
+// shown on the results page
+func (g *googleGoQueryEngine) SearchWithStats(ctx context.Context, query string, maxResults int) ([]SearchResult, SearchStats, error) {
+	return g.search(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+func (g *googleGoQueryEngine) search(ctx context.Context, q EngineQuery) ([]SearchResult, SearchStats, error) {
+	if g.config.err != nil {
+		return nil, SearchStats{}, g.config.err
+	}
+
+	hl := "en"
+	if q.Language != "" {
+		hl = strings.ToLower(q.Language)
+	}
+	searchURL := fmt.Sprintf("https://www.google.com/search?q=%s&hl=%s", url.QueryEscape(q.Query), url.QueryEscape(hl))
+	if q.Region != "" {
+		searchURL += "&gl=" + url.QueryEscape(strings.ToLower(q.Region))
+	}
+	// Google has no moderate level; its default is left in place
+	switch q.safeSearch() {
+	case SafeSearchOff:
+		searchURL += "&safe=off"
+	case SafeSearchStrict:
+		searchURL += "&safe=active"
+	}
+	if q.Offset > 0 {
+		searchURL += fmt.Sprintf("&start=%d", q.Offset)
+	}
+	if q.hasDateRange() {
+		// A custom date range, with dates in M/D/YYYY form
+		from, to := q.dateRange()
+		searchURL += "&tbs=" + url.QueryEscape("cdr:1,cd_min:"+from.Format("1/2/2006")+",cd_max:"+to.Format("1/2/2006"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return nil, SearchStats{}, err
+	}
+
+	// Google serves a consent or captcha page to clients that don't look
+	// like a browser
+	req.Header.Set("User-Agent", g.config.userAgent())
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8")
+	req.Header.Set("Accept-Language", q.acceptLanguage("en-US,en;q=0.9"))
+	req.Header.Set("Referer", "https://www.google.com/")
+
+	resp, err := fetchResultsPage(g.client, req, g.Name())
+	if err != nil {
+		return nil, SearchStats{}, err
+	}
+	defer resp.Body.Close()
+
+	doc, err := parseLimitedBody(resp.Body, g.config.maxBodySize, searchURL)
+	if err != nil {
+		return nil, SearchStats{}, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	results := g.parseResults(doc, q.MaxResults)
+	if len(results) == 0 && captchaPage(doc) {
+		return nil, SearchStats{}, &BlockedError{Engine: g.Name(), URL: searchURL}
+	}
+	return results, parseSearchStats(doc, g.config.selectors), nil
+}
+
+func (g *googleGoQueryEngine) parseResults(doc *goquery.Document, maxResults int) []SearchResult {
+	sel := g.config.selectors
+	var results []SearchResult
+
+	doc.Find(sel.Result).Each(func(i int, s *goquery.Selection) {
+		if len(results) >= maxResults || i < g.config.skipFirst {
+			return
+		}
+
+		title := firstText(s, sel.Title)
+		link := cleanGoogleURL(firstAttr(s, sel.Link, "href"))
+		snippet := firstText(s, sel.Snippet)
+
+		if link != "" && title != "" {
+			results = append(results, SearchResult{
+				Title:      title,
+				URL:        link,
+				Snippet:    snippet,
+				Engine:     g.Name(),
+				SubResults: parseSitelinks(s, sel.Sitelinks, link, g.Name()),
+			})
+		}
+	})
+
+	return results
+}
+
+// cleanGoogleURL unwraps Google's /url?q= redirect links to the target URL.
+// Links to Google's own pages, such as related searches, return "".
+func cleanGoogleURL(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	host := strings.TrimPrefix(u.Hostname(), "www.")
+	if u.Path == "/url" && (host == "" || host == "google.com") {
+		for _, param := range []string{"q", "url"} {
+			if target := u.Query().Get(param); strings.HasPrefix(target, "http") {
+				return target
+			}
+		}
+		return ""
+	}
+
+	if host == "" || host == "google.com" {
+		return ""
+	}
+	return link
+}
@@ -0,0 +1,72 @@
+package search
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGoogleGoQueryEngine_DefaultSelectors(t *testing.T) {
+	doc := mustParseHTML(t, `
+		<div id="search">
+			<div class="g">
+				<div class="yuRUbf"><a href="https://go.dev/doc/tutorial"><h3>Tutorial: Get started with Go</h3></a></div>
+				<div class="VwiC3b">In this tutorial, you'll get a brief introduction to Go.</div>
+			</div>
+			<div class="g">
+				<a href="/url?q=https://gobyexample.com/&amp;sa=U&amp;ved=abc"><h3>Go by Example</h3></a>
+				<div class="VwiC3b">Go by Example is a hands-on introduction.</div>
+			</div>
+			<div class="g">
+				<a href="/search?q=related+searches"><h3>Related searches</h3></a>
+			</div>
+		</div>`)
+
+	engine := NewGoogleGoQueryEngine().(*googleGoQueryEngine)
+	results := engine.parseResults(doc, 10)
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %+v", len(results), results)
+	}
+	if results[0].Title != "Tutorial: Get started with Go" || results[0].URL != "https://go.dev/doc/tutorial" ||
+		results[0].Snippet != "In this tutorial, you'll get a brief introduction to Go." {
+		t.Errorf("unexpected first result: %+v", results[0])
+	}
+	if results[1].URL != "https://gobyexample.com/" {
+		t.Errorf("expected the /url?q= redirect to be unwrapped, got %s", results[1].URL)
+	}
+	if results[0].Engine != "google" {
+		t.Errorf("expected engine google, got %s", results[0].Engine)
+	}
+}
+
+func TestCleanGoogleURL(t *testing.T) {
+	tests := map[string]string{
+		"https://example.com/page":                             "https://example.com/page",
+		"/url?q=https://example.com/a%3Fb%3D1&sa=U":            "https://example.com/a?b=1",
+		"https://www.google.com/url?url=https://example.com/x": "https://example.com/x",
+		"/search?q=golang&tbm=isch":                            "",
+		"https://www.google.com/preferences":                   "",
+	}
+
+	for in, want := range tests {
+		if got := cleanGoogleURL(in); got != want {
+			t.Errorf("cleanGoogleURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSearch_GoogleEngine(t *testing.T) {
+	google := &mockSearchEngine{name: "google", results: []SearchResult{{Title: "Go", URL: "https://go.dev", Engine: "google"}}}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{
+		"bing":   &mockSearchEngine{name: "bing"},
+		"google": google,
+	}, nil)
+
+	results, err := searcher.Search(context.Background(), "golang", SearchOptions{MaxResults: 1, Engines: []string{"google"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Engine != "google" {
+		t.Errorf("expected the google engine to be selected, got %+v", results)
+	}
+}
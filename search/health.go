@@ -0,0 +1,51 @@
+package search
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HealthCheckQuery is the query HealthCheck sends to each engine
+const HealthCheckQuery = "test"
+
+// healthCheckTimeout bounds each engine's health check
+const healthCheckTimeout = 10 * time.Second
+
+// HealthChecker is implemented by searchers that can probe whether each of
+// their engines is answering with results
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) map[string]error
+}
+
+// checkEngineHealth sends HealthCheckQuery to every engine at once, each
+// with its own timeout, and returns each engine's error keyed by name: nil
+// for an engine that returned results, ErrNoResults for one that answered
+// with none.
+func checkEngineHealth(ctx context.Context, engines map[string]SearchEngine) map[string]error {
+	health := make(map[string]error, len(engines))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for name, engine := range engines {
+		wg.Add(1)
+		go func(name string, engine SearchEngine) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			results, err := searchEngine(ctx, engine, EngineQuery{Query: HealthCheckQuery, MaxResults: 1})
+			if err == nil && len(results) == 0 {
+				err = ErrNoResults
+			}
+
+			mu.Lock()
+			health[name] = err
+			mu.Unlock()
+		}(name, engine)
+	}
+
+	wg.Wait()
+	return health
+}
@@ -0,0 +1,54 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealthCheck(t *testing.T) {
+	blocked := &BlockedError{Engine: "yandex", URL: "https://yandex.com/search/?text=test"}
+	engines := map[string]SearchEngine{
+		"bing":   &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Test", URL: "https://example.com"}}},
+		"brave":  &mockSearchEngine{name: "brave"},
+		"yandex": &mockSearchEngine{name: "yandex", err: blocked},
+		"google": &slowEngine{mockSearchEngine: mockSearchEngine{name: "google"}, delay: time.Minute},
+	}
+	searcher := NewSearcherWithEngines(engines, nil)
+	searcher.(*multiEngineSearcher).DisableEngine("brave")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	health := searcher.(HealthChecker).HealthCheck(ctx)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the slow engine to be cut off by the context, took %v", elapsed)
+	}
+
+	if len(health) != 4 {
+		t.Fatalf("expected every engine to be reported, got %v", health)
+	}
+	if err := health["bing"]; err != nil {
+		t.Errorf("expected bing to be healthy, got %v", err)
+	}
+	if err := health["brave"]; !errors.Is(err, ErrNoResults) {
+		t.Errorf("expected ErrNoResults for the disabled engine without results, got %v", err)
+	}
+	if err := health["yandex"]; !errors.Is(err, ErrBlocked) {
+		t.Errorf("expected yandex to report ErrBlocked, got %v", err)
+	}
+	if err := health["google"]; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected google to time out, got %v", err)
+	}
+}
+
+func TestHealthCheck_Hybrid(t *testing.T) {
+	searcher := &HybridMultiEngineSearcher{engines: map[string]SearchEngine{
+		"bing": &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Test", URL: "https://example.com"}}},
+	}}
+	if health := searcher.HealthCheck(context.Background()); len(health) != 1 || health["bing"] != nil {
+		t.Errorf("expected bing to be healthy, got %v", health)
+	}
+}
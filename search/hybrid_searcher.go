@@ -3,199 +3,420 @@ package search
 import (
 	"context"
 	"fmt"
-	"sync"
 	"time"
-
-	"github.com/liliang-cn/mcp-websearch-server/extraction"
 )
 
 // HybridMultiEngineSearcher combines goquery search with chromedp extraction
 type HybridMultiEngineSearcher struct {
 	engines   map[string]SearchEngine
-	extractor *extraction.HybridExtractor
+	extractor pageExtractor
+	quota     *queryQuota
+	ttls      map[PageType]time.Duration
+	picker    *enginePicker
+	// archiveBase is the Wayback Machine prefix for archive fallback; empty
+	// disables it
+	archiveBase string
+	blocklist   engineBlocklist
+	translator  *snippetTranslator
+	// maxQueryLength limits query length; zero means
+	// DefaultMaxQueryLength and negative disables the limit
+	maxQueryLength int
+	// overshoot multiplies the results fetched from engines when filters
+	// are set
+	overshoot int
+	// escalation holds the browser engines tried as a last resort when the
+	// goquery engines return nothing; empty disables escalation
+	escalation []SearchEngine
+	// extractConcurrency is the WithExtractConcurrency setting, zero for
+	// the default
+	extractConcurrency int
+	// stats counts engine requests, failures and fallbacks
+	stats *searchStats
+	// priority is the engine priority order; nil means hybridPriority
+	priority []string
+	// defaultTimeout is the WithDefaultTimeout setting, zero for the
+	// default
+	defaultTimeout time.Duration
 }
 
+// hybridPriority is the hybrid searcher's default engine priority order
+var hybridPriority = []string{"searxng", "duckduckgo", "bing", "brave", "mojeek", "startpage", "google", "yandex", "qwant"}
+
 // NewHybridSearcher creates a new hybrid searcher
-func NewHybridSearcher() MultiEngineSearcher {
-	return &HybridMultiEngineSearcher{
-		engines: map[string]SearchEngine{
-			"bing":       NewBingGoQueryEngine(),
-			"brave":      NewBraveGoQueryEngine(),
-			"duckduckgo": NewDuckDuckGoGoQueryEngine(),
-		},
-		extractor: extraction.NewHybridExtractor(),
+func NewHybridSearcher(opts ...SearcherOption) MultiEngineSearcher {
+	o := applySearcherOptions(opts)
+	engines := o.engineSet(func() map[string]SearchEngine {
+		engineOpts := o.engineOptions()
+		return map[string]SearchEngine{
+			"bing":       NewBingGoQueryEngine(engineOpts...),
+			"brave":      NewBraveGoQueryEngine(engineOpts...),
+			"duckduckgo": NewDuckDuckGoGoQueryEngine(engineOpts...),
+			"mojeek":     NewMojeekGoQueryEngine(engineOpts...),
+			"startpage":  NewStartpageGoQueryEngine(engineOpts...),
+			"google":     NewGoogleGoQueryEngine(engineOpts...),
+			"yandex":     NewYandexGoQueryEngine(engineOpts...),
+			"qwant":      NewQwantEngine(engineOpts...),
+		}
+	})
+	h := &HybridMultiEngineSearcher{
+		engines:     engines,
+		extractor:   newPageExtractor(o.extractor, o.hybridOptions()...),
+		quota:       o.quota,
+		ttls:        o.ttls,
+		picker:      newEnginePicker(o.seed, o.weights),
+		archiveBase: o.archiveBase(),
+		translator:  o.translator,
+
+		maxQueryLength: o.maxQueryLength,
+		overshoot:      o.overshoot,
+
+		extractConcurrency: o.extractConcurrency,
+		stats:              newSearchStats(),
+		priority:           o.enginePriority(engines, hybridPriority),
+		defaultTimeout:     o.defaultTimeout,
+	}
+	if o.browserEscalation {
+		h.escalation = browserEngines(o.browserEngineOptions()...)
+	}
+	return h
+}
+
+// NewHybridSearcherWithBrowserFallback creates a hybrid searcher whose
+// engines fall back to their chromedp implementation when the goquery
+// scraper returns no results
+func NewHybridSearcherWithBrowserFallback(opts ...SearcherOption) MultiEngineSearcher {
+	o := applySearcherOptions(opts)
+	h := NewHybridSearcher(opts...).(*HybridMultiEngineSearcher)
+	h.engines = o.engineSet(func() map[string]SearchEngine {
+		engineOpts, browserOpts := o.engineOptions(), o.browserEngineOptions()
+		return map[string]SearchEngine{
+			"bing":       NewFallbackEngine(NewBingGoQueryEngine(engineOpts...), NewBingSearchEngine(browserOpts...)),
+			"brave":      NewFallbackEngine(NewBraveGoQueryEngine(engineOpts...), NewBraveSearchEngine(browserOpts...)),
+			"duckduckgo": NewFallbackEngine(NewDuckDuckGoGoQueryEngine(engineOpts...), NewDuckDuckGoSearchEngine(browserOpts...)),
+			"mojeek":     NewMojeekGoQueryEngine(engineOpts...),
+			"startpage":  NewStartpageGoQueryEngine(engineOpts...),
+			"google":     NewGoogleGoQueryEngine(engineOpts...),
+			"yandex":     NewYandexGoQueryEngine(engineOpts...),
+			"qwant":      NewQwantEngine(engineOpts...),
+		}
+	})
+	return h
+}
+
+// pipeline returns the search pipeline over h's engines, extracting content
+// with the hybrid extractor and escalating to the browser engines
+func (h *HybridMultiEngineSearcher) pipeline() searchPipeline {
+	return searchPipeline{
+		router:         h,
+		engines:        h.engines,
+		quota:          h.quota,
+		picker:         h.picker,
+		maxQueryLength: h.maxQueryLength,
+		overshoot:      h.overshoot,
+		ttls:           h.ttls,
+		translator:     h.translator,
+		stats:          h.stats,
+		defaultTimeout: h.defaultTimeout,
+
+		extract:            h.extractResult,
+		extractConcurrency: h.extractConcurrency,
+		// Limit concurrent browser instances
+		defaultConcurrency: 2,
+		escalation:         h.escalation,
+		// Always extract content for deep search
+		alwaysExtract: true,
 	}
 }
 
 // Search performs a search and optionally extracts content
 func (h *HybridMultiEngineSearcher) Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
-	if opts.Timeout == 0 {
-		opts.Timeout = 30 * time.Second
-	}
+	return h.pipeline().search(ctx, query, opts)
+}
 
-	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
-	defer cancel()
+// DeepSearch performs search across multiple engines with content extraction
+func (h *HybridMultiEngineSearcher) DeepSearch(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	_, results, err := h.DeepSearchDetailed(ctx, query, opts)
+	return results, err
+}
 
-	// Select and use search engine
-	engine := h.selectEngine(opts.Engines)
-	if engine == nil {
-		return nil, fmt.Errorf("no search engine available")
+// DeepSearchDetailed is DeepSearch that also returns each queried engine's
+// raw results, keyed by engine name, from before they were merged, filtered
+// and ranked. Engines that failed map to nil.
+func (h *HybridMultiEngineSearcher) DeepSearchDetailed(ctx context.Context, query string, opts SearchOptions) (map[string][]SearchResult, []SearchResult, error) {
+	return h.pipeline().deepSearch(ctx, query, opts)
+}
+
+// SearchStream is DeepSearch that sends each result as soon as its engine
+// answers and, if opts.ExtractContent is set, its content is extracted, so
+// a slow engine doesn't hold back the others. Results are deduplicated and
+// capped at opts.MaxResults but not ranked. The result channel is closed
+// when the search is done; the error channel then yields the first error,
+// if any, and is closed. Cancel ctx to stop early.
+func (h *HybridMultiEngineSearcher) SearchStream(ctx context.Context, query string, opts SearchOptions) (<-chan SearchResult, <-chan error) {
+	return h.pipeline().stream(ctx, query, opts)
+}
+
+// extractResult fills in r's content, status and dates from its page
+func (h *HybridMultiEngineSearcher) extractResult(ctx context.Context, r *SearchResult) {
+	// Use the hybrid extractor for better content
+	page, err := h.extractor.ExtractPage(ctx, r.URL)
+	if err != nil {
+		r.ExtractError = err.Error()
+	} else {
+		r.Content = truncateContent(page.Content, maxResultContent)
+		r.ExtractedAt = time.Now()
+		r.HTTPStatus = page.StatusCode
+		r.FinalURL = page.FinalURL
+		r.ContentSource = ContentSourceLive
+		r.Citations = page.Citations
+		r.ContentHash = page.ContentHash
+		r.PublishedAt = page.PublishedAt
+		// Fall back to the Last-Modified header when the page has no
+		// better date
+		if r.Date().IsZero() {
+			r.LastModified = page.LastModified
+		}
 	}
 
-	// Get search results using goquery (fast)
-	results, err := engine.Search(ctx, query, opts.MaxResults)
+	// Without a page, a HEAD request still records the status and dates
 	if err != nil {
-		// Try fallback engines
-		results, err = h.fallbackSearch(ctx, query, opts.MaxResults, engine.Name())
-		if err != nil {
-			return nil, fmt.Errorf("all search engines failed: %w", err)
+		if info, err := h.extractor.PageInfo(ctx, r.URL); err == nil {
+			applyPageInfo(r, info)
 		}
 	}
 
-	// Extract content if requested (using chromedp)
-	if opts.ExtractContent && len(results) > 0 {
-		h.extractContentIntelligently(ctx, results)
+	// Read paywalled or broken pages from the archive instead
+	if h.archiveBase != "" && needsArchive(*r) {
+		if page, err := h.extractor.ExtractPage(ctx, h.archiveBase+r.URL); err == nil {
+			applyArchiveContent(r, truncateContent(page.Content, maxResultContent))
+		}
 	}
 
-	return results, nil
+	r.Language = detectLanguage(r.Content)
 }
 
-// DeepSearch performs search across multiple engines with content extraction
-func (h *HybridMultiEngineSearcher) DeepSearch(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
-	if opts.Timeout == 0 {
-		opts.Timeout = 60 * time.Second
+// DisableEngine stops the named engine from being used for selection,
+// fallback and deep search until EnableEngine is called. It is safe to call
+// while searches are running. A name that isn't a registered engine is an
+// *UnknownEngineError.
+func (h *HybridMultiEngineSearcher) DisableEngine(name string) error {
+	if err := checkEngineNames(h.engines, []string{name}); err != nil {
+		return err
 	}
+	h.blocklist.disable(name)
+	return nil
+}
 
-	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
-	defer cancel()
-
-	var allResults []SearchResult
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-
-	engines := h.getEngines(opts.Engines)
-	if len(engines) == 0 {
-		return nil, fmt.Errorf("no search engines available")
+// EnableEngine restores an engine disabled by DisableEngine. A name that
+// isn't a registered engine is an *UnknownEngineError.
+func (h *HybridMultiEngineSearcher) EnableEngine(name string) error {
+	if err := checkEngineNames(h.engines, []string{name}); err != nil {
+		return err
 	}
+	h.blocklist.enable(name)
+	return nil
+}
 
-	resultsPerEngine := opts.MaxResults / len(engines)
-	if resultsPerEngine < 1 {
-		resultsPerEngine = 1
+// SearchImages searches for images with the first enabled engine in
+// ImageEngines that supports it
+func (h *HybridMultiEngineSearcher) SearchImages(ctx context.Context, query string, maxResults int) ([]ImageResult, error) {
+	query, err := sanitizeQuery(query, h.maxQueryLength)
+	if err != nil {
+		return nil, err
 	}
 
-	// Search with all engines concurrently
-	for _, engine := range engines {
-		wg.Add(1)
-		go func(eng SearchEngine) {
-			defer wg.Done()
-
-			results, err := eng.Search(ctx, query, resultsPerEngine)
-			if err != nil {
-				fmt.Printf("Engine %s failed: %v\n", eng.Name(), err)
-				return
-			}
-
-			mu.Lock()
-			allResults = append(allResults, results...)
-			mu.Unlock()
-		}(engine)
+	if err := h.quota.acquire(); err != nil {
+		return nil, err
 	}
 
-	wg.Wait()
-
-	if len(allResults) == 0 {
-		return nil, fmt.Errorf("no results from any search engine")
+	for _, name := range ImageEngines {
+		engine, ok := h.blocklist.lookup(h.engines, name)
+		if !ok {
+			continue
+		}
+		if images, ok := imageSearcher(engine); ok {
+			return images.SearchImages(ctx, query, maxResults)
+		}
 	}
+	return nil, fmt.Errorf("no image search engine available")
+}
 
-	// Always extract content for deep search
-	h.extractContentIntelligently(ctx, allResults)
+// Suggest returns completions for a partly typed query from the first
+// enabled engine in SuggestEngines that answers. It makes one plain HTTP
+// request per engine tried and counts against the query quota.
+func (h *HybridMultiEngineSearcher) Suggest(ctx context.Context, prefix string) ([]string, error) {
+	prefix, err := sanitizeQuery(prefix, h.maxQueryLength)
+	if err != nil {
+		return nil, err
+	}
 
-	// Limit final results
-	if len(allResults) > opts.MaxResults {
-		allResults = allResults[:opts.MaxResults]
+	if err := h.quota.acquire(); err != nil {
+		return nil, err
 	}
 
-	return allResults, nil
+	return suggest(ctx, h.engines, &h.blocklist, prefix)
 }
 
-// extractContentIntelligently uses chromedp to extract real content
-func (h *HybridMultiEngineSearcher) extractContentIntelligently(ctx context.Context, results []SearchResult) {
-	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, 2) // Limit concurrent browser instances
+// QueryCount returns the number of searches attempted, including ones
+// rejected by the query quota
+func (h *HybridMultiEngineSearcher) QueryCount() int64 {
+	return h.quota.count()
+}
 
-	for i := range results {
-		wg.Add(1)
-		go func(idx int) {
-			defer wg.Done()
+// HealthCheck sends a trivial query to every registered engine, including
+// disabled ones, concurrently and with a short timeout each, and returns
+// each engine's error keyed by name; nil means the engine returned results.
+// It doesn't stop at the first failure, so it suits startup and readiness
+// probes that need to spot, say, one engine serving captchas.
+func (h *HybridMultiEngineSearcher) HealthCheck(ctx context.Context) map[string]error {
+	return checkEngineHealth(ctx, h.engines)
+}
 
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
+// Stats returns a snapshot of each engine's request, success, failure and
+// fallback counts. It is safe to call while searches are running.
+func (h *HybridMultiEngineSearcher) Stats() Stats {
+	return h.stats.snapshot()
+}
 
-			// Use the hybrid extractor for better content
-			content, err := h.extractor.ExtractSummary(ctx, results[idx].URL, 3000)
-			if err == nil {
-				results[idx].Content = content
-				results[idx].ExtractedAt = time.Now()
-			}
-		}(i)
-	}
+// Warmup launches the extraction browser so the first search doesn't pay
+// Chrome's start-up cost
+func (h *HybridMultiEngineSearcher) Warmup(ctx context.Context) error {
+	return h.extractor.Warmup(ctx)
+}
 
-	wg.Wait()
+// Close releases resources held by the searcher. It is safe to call more
+// than once.
+func (h *HybridMultiEngineSearcher) Close() error {
+	return h.extractor.Close()
 }
 
 // SearchAndAggregate searches and returns aggregated content ready for summarization
 func (h *HybridMultiEngineSearcher) SearchAndAggregate(ctx context.Context, query string, maxResults int) (string, error) {
+	return h.SearchAndAggregateWithOptions(ctx, query, AggregateOptions{MaxResults: maxResults})
+}
+
+// SearchAndAggregateWithBudget is SearchAndAggregate sized to fit
+// maxTokens tokens, as estimated by EstimateTokens. Each result's content
+// gets a share of the budget in proportion to its length and is trimmed at
+// a sentence boundary.
+func (h *HybridMultiEngineSearcher) SearchAndAggregateWithBudget(ctx context.Context, query string, maxResults, maxTokens int) (string, error) {
+	if maxTokens <= 0 {
+		return "", fmt.Errorf("token budget must be positive, got %d", maxTokens)
+	}
+	return h.SearchAndAggregateWithOptions(ctx, query, AggregateOptions{MaxResults: maxResults, MaxTokens: maxTokens})
+}
+
+// SearchAndAggregateWithOptions is SearchAndAggregate with pinned URLs, a
+// choice of section order and an optional token budget
+func (h *HybridMultiEngineSearcher) SearchAndAggregateWithOptions(ctx context.Context, query string, opts AggregateOptions) (string, error) {
+	if _, err := sortForAggregate(nil, opts.SortBy); err != nil {
+		return "", err
+	}
+	if opts.MaxTokens < 0 {
+		return "", fmt.Errorf("token budget must not be negative, got %d", opts.MaxTokens)
+	}
+
 	results, err := h.Search(ctx, query, SearchOptions{
-		MaxResults:     maxResults,
+		MaxResults:     opts.MaxResults,
 		ExtractContent: true,
 		Timeout:        45 * time.Second,
+		RequireContent: opts.RequireContent,
 	})
 	if err != nil {
 		return "", err
 	}
 
+	if opts.FetchMissingPinned {
+		for _, pinned := range missingURLs(results, opts.PinnedURLs) {
+			if page, err := h.extractor.ExtractPage(ctx, pinned); err == nil {
+				results = append(results, SearchResult{
+					Title:       page.Title,
+					URL:         pinned,
+					Content:     truncateContent(page.Content, maxResultContent),
+					Engine:      "pinned",
+					ExtractedAt: time.Now(),
+					HTTPStatus:  page.StatusCode,
+					FinalURL:    page.FinalURL,
+					ContentHash: page.ContentHash,
+				})
+			}
+		}
+	}
+
+	results, err = sortForAggregate(results, opts.SortBy)
+	if err != nil {
+		return "", err
+	}
+
+	results = pinResults(results, opts.PinnedURLs)
+	if opts.MaxTokens > 0 {
+		count := opts.TokenCounter
+		if count == nil {
+			count = EstimateTokens
+		}
+		return formatAggregateWithBudget(query, results, opts.MaxTokens, count), nil
+	}
+	return formatAggregate(query, results), nil
+}
+
+// formatAggregate renders results as markdown for summarization
+func formatAggregate(query string, results []SearchResult) string {
 	// Aggregate all content
-	var aggregated string
-	aggregated += fmt.Sprintf("# Search Results for: %s\n\n", query)
-	
+	aggregated := aggregateHeader(query)
+
 	for i, result := range results {
-		aggregated += fmt.Sprintf("## %d. %s\n", i+1, result.Title)
-		aggregated += fmt.Sprintf("**Source:** %s\n", result.URL)
-		aggregated += fmt.Sprintf("**Engine:** %s\n\n", result.Engine)
-		
-		// Always include snippet as it often contains the key fact (zero-click info)
-		if result.Snippet != "" {
-			aggregated += fmt.Sprintf("**Snippet:** %s\n\n", result.Snippet)
-		}
-		
-		if result.Content != "" {
-			// Limit content per result
-			content := result.Content
-			if len(content) > 1500 {
-				content = content[:1500] + "..."
-			}
-			aggregated += fmt.Sprintf("**Extracted Content:**\n%s", content)
+		// Limit content per result
+		content := result.Content
+		if len(content) > 1500 {
+			content = content[:1500] + "..."
 		}
-		
-		aggregated += "\n\n---\n\n"
+		aggregated += aggregateSection(i, result, content)
 	}
 
-	return aggregated, nil
+	return aggregated
+}
+
+// aggregateHeader is the heading that starts an aggregate
+func aggregateHeader(query string) string {
+	return fmt.Sprintf("# Search Results for: %s\n\n", query)
+}
+
+// aggregateSection renders the i-th result of an aggregate with content in
+// place of its extracted content, leaving it out when empty
+func aggregateSection(i int, result SearchResult, content string) string {
+	var aggregated string
+	aggregated += fmt.Sprintf("## %d. %s\n", i+1, result.Title)
+	aggregated += fmt.Sprintf("**Source:** %s\n", result.URL)
+	if result.SourceCategory != "" {
+		aggregated += fmt.Sprintf("**Source type:** %s\n", result.SourceCategory)
+	}
+	aggregated += fmt.Sprintf("**Engine:** %s\n\n", result.Engine)
+
+	// Always include snippet as it often contains the key fact (zero-click info)
+	if result.Snippet != "" {
+		aggregated += fmt.Sprintf("**Snippet:** %s\n\n", result.Snippet)
+	}
+
+	if content != "" {
+		aggregated += fmt.Sprintf("**Extracted Content:**\n%s", content)
+	}
+
+	aggregated += "\n\n---\n\n"
+	return aggregated
 }
 
 func (h *HybridMultiEngineSearcher) selectEngine(preferred []string) SearchEngine {
 	if len(preferred) > 0 {
 		for _, name := range preferred {
-			if engine, ok := h.engines[name]; ok {
+			if engine, ok := h.blocklist.lookup(h.engines, name); ok {
 				return engine
 			}
 		}
 	}
 
 	// Default priority
-	priorityOrder := []string{"duckduckgo", "bing", "brave"}
-	for _, name := range priorityOrder {
-		if engine, ok := h.engines[name]; ok {
+	for _, name := range h.priorityOrder() {
+		if engine, ok := h.blocklist.lookup(h.engines, name); ok {
 			return engine
 		}
 	}
@@ -203,36 +424,76 @@ func (h *HybridMultiEngineSearcher) selectEngine(preferred []string) SearchEngin
 	return nil
 }
 
-func (h *HybridMultiEngineSearcher) fallbackSearch(ctx context.Context, query string, maxResults int, failedEngine string) ([]SearchResult, error) {
-	priorityOrder := []string{"duckduckgo", "bing", "brave"}
-
-	for _, name := range priorityOrder {
+// fallbackSearch tries the other engines in priority order until one
+// answers. It gives up early when the search is cancelled or fails in a way
+// no other engine would avoid, and otherwise wraps the last engine's error.
+func (h *HybridMultiEngineSearcher) fallbackSearch(ctx context.Context, q EngineQuery, failedEngine string) ([]SearchResult, error) {
+	var lastErr error
+	for _, name := range h.priorityOrder() {
 		if name == failedEngine {
 			continue
 		}
 
-		if engine, ok := h.engines[name]; ok {
-			results, err := engine.Search(ctx, query, maxResults)
+		if engine, ok := h.blocklist.lookup(h.engines, name); ok {
+			results, err := h.stats.search(ctx, engine, q)
 			if err == nil {
 				return results, nil
 			}
+			if !recoverable(err) || ctx.Err() != nil {
+				return nil, err
+			}
+			lastErr = err
 		}
 	}
 
+	if lastErr != nil {
+		return nil, fmt.Errorf("all fallback engines failed: %w", lastErr)
+	}
 	return nil, fmt.Errorf("all fallback engines failed")
 }
 
+// priorityOrder returns the engine names in priority order
+func (h *HybridMultiEngineSearcher) priorityOrder() []string {
+	if h.priority == nil {
+		return hybridPriority
+	}
+	return h.priority
+}
+
+// hasEngine reports whether name is a registered engine
+func (h *HybridMultiEngineSearcher) hasEngine(name string) bool {
+	_, ok := h.engines[normalizeEngineName(name)]
+	return ok
+}
+
 func (h *HybridMultiEngineSearcher) getEngines(names []string) []SearchEngine {
 	if len(names) == 0 {
-		names = []string{"duckduckgo", "bing", "brave"}
+		names = h.priorityOrder()
 	}
 
 	var engines []SearchEngine
 	for _, name := range names {
-		if engine, ok := h.engines[name]; ok {
+		if engine, ok := h.blocklist.lookup(h.engines, name); ok {
 			engines = append(engines, engine)
 		}
 	}
 
 	return engines
-}
\ No newline at end of file
+}
+
+// otherEngines returns the default engines that did not contribute to
+// results, in priority order.
+func (h *HybridMultiEngineSearcher) otherEngines(results []SearchResult) []SearchEngine {
+	used := make(map[string]bool)
+	for _, r := range results {
+		used[r.Engine] = true
+	}
+
+	var engines []SearchEngine
+	for _, engine := range h.getEngines(nil) {
+		if !used[engine.Name()] {
+			engines = append(engines, engine)
+		}
+	}
+	return engines
+}
@@ -0,0 +1,82 @@
+package search
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Query intents reported in SearchResult.QueryIntent
+const (
+	IntentInformational = "informational"
+	IntentNavigational  = "navigational"
+	IntentTransactional = "transactional"
+	IntentNews          = "news"
+)
+
+var (
+	newsWords          = []string{"latest", "today", "todays", "tonight", "yesterday", "breaking", "news", "headlines", "update", "updates"}
+	transactionalWords = []string{"buy", "price", "prices", "pricing", "cheap", "cheapest", "deal", "deals", "discount", "coupon", "order", "purchase", "cost", "sale", "shop", "subscribe", "download"}
+	questionWords      = []string{"what", "how", "why", "who", "when", "where", "which", "whats", "hows", "is", "are", "can", "does", "do", "should"}
+	navigationalWords  = []string{"login", "log", "signin", "homepage", "website", "official", "site"}
+	brands             = []string{"google", "youtube", "facebook", "github", "amazon", "twitter", "reddit", "wikipedia", "gmail", "netflix", "linkedin", "instagram", "stackoverflow"}
+
+	domainToken = regexp.MustCompile(`^[a-z0-9\-]+(\.[a-z0-9\-]+)*\.(com|org|net|io|dev|gov|edu|co|ai|app)$`)
+)
+
+// classifyIntent labels a query as news, transactional, navigational or
+// informational from its words. Questions and anything unrecognized are
+// informational.
+func classifyIntent(query string) string {
+	var words []string
+	for _, w := range strings.Fields(strings.ToLower(query)) {
+		w = strings.Trim(w, "?!,;:\"'()")
+		w = strings.ReplaceAll(w, "'", "")
+		if w != "" {
+			words = append(words, w)
+		}
+	}
+	if len(words) == 0 {
+		return IntentInformational
+	}
+
+	switch {
+	case containsAny(words, newsWords):
+		return IntentNews
+	case containsAny(words, transactionalWords):
+		return IntentTransactional
+	case containsAny(words[:1], questionWords) || strings.HasSuffix(strings.TrimSpace(query), "?"):
+		return IntentInformational
+	case containsAny(words, navigationalWords) || isNavigationalTarget(words):
+		return IntentNavigational
+	}
+	return IntentInformational
+}
+
+// isNavigationalTarget reports whether the query names a site: it contains
+// a domain, or is little more than a well-known brand
+func isNavigationalTarget(words []string) bool {
+	for _, w := range words {
+		if domainToken.MatchString(w) {
+			return true
+		}
+	}
+	return len(words) <= 2 && containsAny(words, brands)
+}
+
+func containsAny(words, set []string) bool {
+	for _, w := range words {
+		for _, s := range set {
+			if w == s {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setQueryIntent records the query's intent on each result
+func setQueryIntent(results []SearchResult, intent string) {
+	for i := range results {
+		results[i].QueryIntent = intent
+	}
+}
@@ -0,0 +1,53 @@
+package search
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClassifyIntent(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"what is a goroutine", IntentInformational},
+		{"How do I reverse a slice in Go?", IntentInformational},
+		{"go memory model explained", IntentInformational},
+		{"github", IntentNavigational},
+		{"github login", IntentNavigational},
+		{"pkg.go.dev", IntentNavigational},
+		{"golang official site", IntentNavigational},
+		{"buy mechanical keyboard", IntentTransactional},
+		{"iphone 16 price", IntentTransactional},
+		{"cheapest flights to tokyo", IntentTransactional},
+		{"latest go release", IntentNews},
+		{"election results today", IntentNews},
+		{"breaking news earthquake", IntentNews},
+		{"", IntentInformational},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := classifyIntent(tt.query); got != tt.want {
+				t.Errorf("classifyIntent(%q) = %s, want %s", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearch_QueryIntentInOutput(t *testing.T) {
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing": &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Store", URL: "http://store.example.com"}}},
+		},
+		extractor: &mockContentExtractor{},
+	}
+
+	results, err := searcher.Search(context.Background(), "buy a laptop", SearchOptions{MaxResults: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].QueryIntent != IntentTransactional {
+		t.Errorf("expected transactional intent, got %q", results[0].QueryIntent)
+	}
+}
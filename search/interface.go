@@ -3,15 +3,86 @@ package search
 import (
 	"context"
 	"time"
+
+	"github.com/liliang-cn/mcp-websearch-server/extraction"
 )
 
+// SearchResult is a single search hit. It doubles as the structured output
+// format, so the engine snippet and the extracted content are always
+// serialized as separate fields.
 type SearchResult struct {
-	Title       string    `json:"title"`
-	URL         string    `json:"url"`
-	Snippet     string    `json:"snippet"`
-	Content     string    `json:"content,omitempty"`
-	Engine      string    `json:"engine"`
-	ExtractedAt time.Time `json:"extracted_at,omitempty"`
+	Title        string    `json:"title"`
+	URL          string    `json:"url"`
+	Snippet      string    `json:"snippet"`
+	Content      string    `json:"content"`
+	ExtractError string    `json:"extract_error,omitempty"`
+	Engine       string    `json:"engine"`
+	ExtractedAt  time.Time `json:"extracted_at,omitzero"`
+	LastModified time.Time `json:"last_modified,omitzero"`
+	HTTPStatus   int       `json:"http_status,omitempty"`
+	FinalURL     string    `json:"final_url,omitempty"`
+	// PublishedAt is the publication date from the page's metadata, set
+	// during content extraction; zero when the page doesn't state one
+	PublishedAt time.Time `json:"published_at,omitzero"`
+	// ContentSource is "live" or, when the page had to be read from the
+	// Wayback Machine, "archive"
+	ContentSource string `json:"content_source,omitempty"`
+	// PageType, FetchedAt and TTLSeconds let clients decide how long a
+	// result may be cached
+	PageType   PageType  `json:"page_type,omitempty"`
+	FetchedAt  time.Time `json:"fetched_at"`
+	TTLSeconds int       `json:"ttl_seconds"`
+	// SourceCategory is a coarse credibility hint: news, blog, forum,
+	// official or wiki
+	SourceCategory string `json:"source_category,omitempty"`
+	// TranslatedSnippet is the snippet in the searcher's target language,
+	// set when a snippet translator is configured
+	TranslatedSnippet string `json:"translated_snippet,omitempty"`
+	// QueryIntent is the coarse intent of the query that found the result:
+	// informational, navigational, transactional or news
+	QueryIntent string `json:"query_intent,omitempty"`
+	// Confidence is a 0–1 estimate of how much to trust the result,
+	// combining relevance, engine agreement, extraction success and source
+	// category (see relevanceShare for the formula)
+	Confidence float64 `json:"confidence"`
+	// Citations are the links found in the page's main content, set when
+	// content is extracted by an extractor that reports them
+	Citations []extraction.Citation `json:"citations,omitempty"`
+	// SubResults are the sitelinks the engine showed under this result,
+	// pointing at the site's key sub-pages
+	SubResults []SearchResult `json:"sub_results,omitempty"`
+	// FiltersRelaxed is set when the result only passed after
+	// SearchOptions.Filters were loosened because they removed everything
+	FiltersRelaxed bool `json:"filters_relaxed,omitempty"`
+	// ContentHash is a hash of the extracted content, so a page can be
+	// checked for changes without comparing the full text
+	ContentHash string `json:"content_hash,omitempty"`
+	// Engines lists every engine that returned the page when DeepSearch
+	// merged duplicates; Engine is the first of them
+	Engines []string `json:"engines,omitempty"`
+	// Summary is a one or two sentence extractive summary of the content,
+	// set when SearchOptions.Summarize is
+	Summary string `json:"summary,omitempty"`
+	// Source is the publisher a news engine credits the story to
+	Source string `json:"source,omitempty"`
+	// Language is the ISO 639-1 code of the language the extracted content
+	// is written in, such as "en"; empty when it wasn't extracted or the
+	// language couldn't be detected confidently
+	Language string `json:"language,omitempty"`
+	// TotalEstimate is the "About N results" figure the engine showed for
+	// the query, a rough signal of how popular or ambiguous it is; zero
+	// when the engine doesn't report one
+	TotalEstimate int64 `json:"total_estimate,omitempty"`
+}
+
+// Date returns the best known date for the result, for date-based sorting:
+// the publication date, else the Last-Modified header. It is zero when no
+// date is known.
+func (r SearchResult) Date() time.Time {
+	if !r.PublishedAt.IsZero() {
+		return r.PublishedAt
+	}
+	return r.LastModified
 }
 
 type SearchOptions struct {
@@ -19,6 +90,100 @@ type SearchOptions struct {
 	ExtractContent bool
 	Engines        []string
 	Timeout        time.Duration
+	// MaxEngines caps how many engines DeepSearch actually queries, taken in
+	// priority order. Zero queries every requested engine.
+	MaxEngines int
+	// MinResults is the fewest results a search may return before it is
+	// treated as a failure. Single-engine searches top up from the other
+	// engines first; if still short, an *InsufficientResultsError is
+	// returned alongside the partial results.
+	MinResults int
+	// MaxConsecutiveSameDomain reorders single-engine Search results so that
+	// no more than this many results from one domain appear in a row. Zero
+	// leaves the engine's order untouched.
+	MaxConsecutiveSameDomain int
+	// Strategy controls how single-engine Search picks its engine when none
+	// is requested explicitly. StrategyRandom spreads searches over the
+	// engines by weight; the default uses the fixed priority order.
+	Strategy string
+	// Rank orders DeepSearch results by relevance to the query, merging URLs
+	// returned by several engines and boosting them for the agreement; see
+	// RankResults for the scoring. The ranking uses titles and snippets
+	// only, so with MaxResults set content is extracted just for the
+	// results that are kept.
+	Rank bool
+	// RedactPII masks email addresses and phone numbers in snippets and
+	// extracted content
+	RedactPII bool
+	// CollapseDuplicateTitles merges DeepSearch results with identical
+	// titles but different URLs, as happens when engines link to different
+	// copies of one article. The result with the richer snippet is kept.
+	CollapseDuplicateTitles bool
+	// ReplaceTranslatedSnippets puts translated snippets in Snippet instead
+	// of alongside it in TranslatedSnippet. It has no effect unless the
+	// searcher has a snippet translator.
+	ReplaceTranslatedSnippets bool
+	// Offset skips that many leading results, for paging: MaxResults 10
+	// with Offset 10 returns results 11-20. DeepSearch splits it across
+	// the engines it queries, like MaxResults.
+	Offset int
+	// Filters drops short-snippet and unwanted-title results before
+	// content is extracted
+	Filters ResultFilters
+	// AllowDomains keeps only results from these domains and BlockDomains
+	// drops results from these, both as soon as each engine returns and
+	// before content is extracted. A domain also matches its subdomains,
+	// so "example.com" covers "news.example.com".
+	AllowDomains []string
+	BlockDomains []string
+	// Summarize sets each result's Summary to the most keyword-dense
+	// sentences of its extracted content. It has no effect unless content
+	// is extracted.
+	Summarize bool
+	// RequireContent drops results whose content couldn't be extracted,
+	// even from the archive, from the final set. It implies
+	// ExtractContent, and more results are fetched so that MaxResults can
+	// still be met.
+	RequireContent bool
+	// After and Before restrict results to pages published in that time
+	// window, using each engine's own date filter; a zero time leaves that
+	// end open. Startpage, SearXNG and the browser-based engines can't
+	// express a date range and ignore them.
+	After  time.Time
+	Before time.Time
+	// Language (e.g. "de") and Region (e.g. "DE") target results in that
+	// language and country through each engine's locale parameters and the
+	// Accept-Language header. Empty keeps each engine's default, which is
+	// mostly English. SearXNG and the browser-based engines ignore them.
+	Language string
+	Region   string
+	// SafeSearch filters explicit content: SafeSearchOff, SafeSearchModerate
+	// (the default, matching most engines) or SafeSearchStrict. It maps to
+	// Bing's adlt, DuckDuckGo's kp, Brave's and Qwant's safesearch and
+	// Google's safe parameters; Startpage and SearXNG keep their own
+	// defaults.
+	SafeSearch SafeSearchLevel
+	// Vertical selects an engine index other than the web, currently only
+	// VerticalNews. Engines without that vertical ignore it, so prefer
+	// SearchNews, which only queries engines that have one.
+	Vertical string
+	// ExtractConcurrency is how many pages are extracted at once, capped
+	// at MaxExtractConcurrency. Zero uses the searcher's setting; see
+	// WithExtractConcurrency.
+	ExtractConcurrency int
+}
+
+// ImageResult is a single image search hit
+type ImageResult struct {
+	Title        string `json:"title"`
+	ImageURL     string `json:"image_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	// SourceURL is the page the image appears on
+	SourceURL string `json:"source_url"`
+	// Width and Height are the full image's size in pixels, zero when the
+	// engine doesn't show it
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
 }
 
 type SearchEngine interface {
@@ -26,11 +191,35 @@ type SearchEngine interface {
 	Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error)
 }
 
+// ImageSearcher is implemented by engines and searchers that can search
+// for images
+type ImageSearcher interface {
+	SearchImages(ctx context.Context, query string, maxResults int) ([]ImageResult, error)
+}
+
 type ContentExtractor interface {
 	ExtractContent(ctx context.Context, url string) (string, error)
 }
 
+// pageInfoFetcher is implemented by extractors that can report a page's
+// status, final URL and Last-Modified header without extracting it
+type pageInfoFetcher interface {
+	PageInfo(ctx context.Context, url string) (*extraction.PageInfo, error)
+}
+
 type MultiEngineSearcher interface {
 	Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error)
 	DeepSearch(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error)
 }
+
+// StreamSearcher is implemented by searchers that can send results as each
+// engine returns them
+type StreamSearcher interface {
+	SearchStream(ctx context.Context, query string, opts SearchOptions) (<-chan SearchResult, <-chan error)
+}
+
+// DetailedSearcher is implemented by searchers that can report which engine
+// returned which results in a deep search
+type DetailedSearcher interface {
+	DeepSearchDetailed(ctx context.Context, query string, opts SearchOptions) (map[string][]SearchResult, []SearchResult, error)
+}
@@ -1,6 +1,7 @@
 package search
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 )
@@ -52,3 +53,75 @@ func TestSearchOptions(t *testing.T) {
 		t.Errorf("expected Timeout=30s, got %v", opts.Timeout)
 	}
 }
+
+func TestSearchResult_StructuredJSON(t *testing.T) {
+	result := SearchResult{
+		Title:        "Test Title",
+		URL:          "http://example.com",
+		Snippet:      "Engine snippet",
+		Content:      "Extracted lead paragraph",
+		ExtractError: "",
+		Engine:       "test",
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to marshal result: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+
+	if decoded["snippet"] != "Engine snippet" {
+		t.Errorf("expected snippet field, got %v", decoded["snippet"])
+	}
+	if decoded["content"] != "Extracted lead paragraph" {
+		t.Errorf("expected content field, got %v", decoded["content"])
+	}
+
+	// Content is kept even when empty so consumers can tell a failed
+	// extraction apart from a missing field.
+	result.Content = ""
+	result.ExtractError = "timeout"
+	data, _ = json.Marshal(result)
+	decoded = nil
+	json.Unmarshal(data, &decoded)
+
+	if _, ok := decoded["content"]; !ok {
+		t.Error("expected content field to be present when empty")
+	}
+	if decoded["extract_error"] != "timeout" {
+		t.Errorf("expected extract_error field, got %v", decoded["extract_error"])
+	}
+	if decoded["snippet"] != "Engine snippet" {
+		t.Errorf("expected snippet to be unaffected, got %v", decoded["snippet"])
+	}
+
+	// Unknown dates are left out rather than sent as the zero time
+	for _, field := range []string{"published_at", "last_modified", "extracted_at"} {
+		if _, ok := decoded[field]; ok {
+			t.Errorf("expected %s to be omitted when zero, got %v", field, decoded[field])
+		}
+	}
+	result.PublishedAt = time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	data, _ = json.Marshal(result)
+	decoded = nil
+	json.Unmarshal(data, &decoded)
+	if decoded["published_at"] != "2024-01-10T00:00:00Z" {
+		t.Errorf("expected published_at when set, got %v", decoded["published_at"])
+	}
+}
+
+func TestSearchResult_DatePrefersPublishedAt(t *testing.T) {
+	published := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	modified := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	if d := (SearchResult{PublishedAt: published, LastModified: modified}).Date(); !d.Equal(published) {
+		t.Errorf("expected the publication date, got %v", d)
+	}
+	if d := (SearchResult{LastModified: modified}).Date(); !d.Equal(modified) {
+		t.Errorf("expected Last-Modified as fallback, got %v", d)
+	}
+}
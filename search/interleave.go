@@ -0,0 +1,50 @@
+package search
+
+import "strings"
+
+// interleaveResults merges per-engine result lists round-robin, so the top
+// result of every engine comes before any engine's second result. With
+// collapseTitles, results whose titles are identical are merged into the
+// one with the richer content or snippet, kept at the earlier position.
+func interleaveResults(perEngine [][]SearchResult, collapseTitles bool) []SearchResult {
+	var merged []SearchResult
+	byTitle := make(map[string]int)
+
+	for rank := 0; ; rank++ {
+		added := false
+		for _, results := range perEngine {
+			if rank >= len(results) {
+				continue
+			}
+			added = true
+			r := results[rank]
+
+			if collapseTitles {
+				key := titleKey(r.Title)
+				if i, ok := byTitle[key]; ok && key != "" {
+					if richness(r) > richness(merged[i]) {
+						merged[i] = r
+					}
+					continue
+				}
+				byTitle[key] = len(merged)
+			}
+			merged = append(merged, r)
+		}
+		if !added {
+			return merged
+		}
+	}
+}
+
+// titleKey normalizes whitespace and case so that the same title scraped
+// from different engines compares equal
+func titleKey(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
+
+// richness prefers results with more extracted content, then longer
+// snippets
+func richness(r SearchResult) int {
+	return len(r.Content)*2 + len(r.Snippet)
+}
@@ -0,0 +1,65 @@
+package search
+
+import (
+	"context"
+	"testing"
+)
+
+func TestInterleaveResults(t *testing.T) {
+	perEngine := [][]SearchResult{
+		{{URL: "http://a1.com"}, {URL: "http://a2.com"}, {URL: "http://a3.com"}},
+		{{URL: "http://b1.com"}},
+		nil,
+		{{URL: "http://c1.com"}, {URL: "http://c2.com"}},
+	}
+
+	merged := interleaveResults(perEngine, false)
+
+	expected := []string{"http://a1.com", "http://b1.com", "http://c1.com", "http://a2.com", "http://c2.com", "http://a3.com"}
+	if len(merged) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(merged))
+	}
+	for i, url := range expected {
+		if merged[i].URL != url {
+			t.Errorf("position %d: expected %s, got %s", i, url, merged[i].URL)
+		}
+	}
+}
+
+func TestMultiEngineSearcher_DeepSearchCollapseDuplicateTitles(t *testing.T) {
+	newSearcher := func() *multiEngineSearcher {
+		return &multiEngineSearcher{
+			engines: map[string]SearchEngine{
+				"bing": &mockSearchEngine{name: "bing", results: []SearchResult{
+					{Title: "Go 1.24 Released", URL: "http://mirror.example.com/go124", Snippet: "Short", Engine: "bing"},
+				}},
+				"brave": &mockSearchEngine{name: "brave", results: []SearchResult{
+					{Title: "Go 1.24  released", URL: "http://go.dev/blog/go1.24", Snippet: "A much longer and richer snippet", Engine: "brave"},
+				}},
+			},
+			extractor: &mockContentExtractor{},
+		}
+	}
+
+	opts := SearchOptions{MaxResults: 10, Engines: []string{"bing", "brave"}}
+
+	results, err := newSearcher().DeepSearch(context.Background(), "go", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("expected same-title results to remain when the option is off, got %d", len(results))
+	}
+
+	opts.CollapseDuplicateTitles = true
+	results, err = newSearcher().DeepSearch(context.Background(), "go", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected same-title results to collapse to one, got %d", len(results))
+	}
+	if results[0].URL != "http://go.dev/blog/go1.24" {
+		t.Errorf("expected the result with the richer snippet to be kept, got %s", results[0].URL)
+	}
+}
@@ -0,0 +1,40 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/abadojack/whatlanggo"
+)
+
+// detectLanguage returns the ISO 639-1 code of the language text is
+// written in, such as "en", or the ISO 639-3 code for a language without
+// one. It returns "" when the detector isn't confident.
+func detectLanguage(text string) string {
+	if strings.TrimSpace(text) == "" {
+		return ""
+	}
+
+	info := whatlanggo.Detect(text)
+	if !info.IsReliable() {
+		return ""
+	}
+	if code := info.Lang.Iso6391(); code != "" {
+		return code
+	}
+	return info.Lang.Iso6393()
+}
+
+// FilterByLanguage returns the results whose content was detected as lang,
+// an ISO 639-1 code such as "en". A regional tag such as "en-US" matches
+// on its language. Results whose language wasn't detected are dropped.
+func FilterByLanguage(results []SearchResult, lang string) []SearchResult {
+	lang, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(lang)), "-")
+
+	var kept []SearchResult
+	for _, r := range results {
+		if r.Language != "" && r.Language == lang {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
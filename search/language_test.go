@@ -0,0 +1,58 @@
+package search
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "The quick brown fox jumps over the lazy dog while the farmer watches from the porch of his house.", "en"},
+		{"german", "Der schnelle braune Fuchs springt über den faulen Hund, während der Bauer von der Veranda seines Hauses zusieht.", "de"},
+		{"empty", "   ", ""},
+		{"too short to tell", "ok", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectLanguage(tt.text); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFilterByLanguage(t *testing.T) {
+	results := []SearchResult{
+		{URL: "https://a.example", Language: "en"},
+		{URL: "https://b.example", Language: "de"},
+		{URL: "https://c.example"},
+		{URL: "https://d.example", Language: "en"},
+	}
+
+	kept := FilterByLanguage(results, "en-US")
+	if len(kept) != 2 || kept[0].URL != "https://a.example" || kept[1].URL != "https://d.example" {
+		t.Errorf("expected the two English results, got %+v", kept)
+	}
+	if kept := FilterByLanguage(results, "fr"); len(kept) != 0 {
+		t.Errorf("expected no French results, got %+v", kept)
+	}
+}
+
+func TestExtraction_SetsLanguage(t *testing.T) {
+	engine := &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Page", URL: "https://example.com", Engine: "bing"}}}
+	extractor := &mockContentExtractor{content: "Ceci est un article assez long, écrit en français, pour que la langue soit détectée avec confiance."}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": engine}, extractor)
+
+	results, err := searcher.Search(context.Background(), "query", SearchOptions{MaxResults: 1, ExtractContent: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Language != "fr" {
+		t.Errorf("expected the content's language to be detected as fr, got %+v", results)
+	}
+}
@@ -0,0 +1,123 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type mojeekGoQueryEngine struct {
+	client *http.Client
+	config engineConfig
+}
+
+// DefaultMojeekSelectors are the selectors used to parse Mojeek results
+// pages. Mojeek runs its own crawler, so its results don't derive from
+// Bing or Google.
+var DefaultMojeekSelectors = Selectors{
+	Result:  "ul.results-standard li",
+	Title:   []string{".title a", "h2 a"},
+	Link:    []string{".title a", "h2 a", "a.ob"},
+	Snippet: []string{"p.s"},
+}
+
+func NewMojeekGoQueryEngine(opts ...EngineOption) SearchEngine {
+	config := newEngineConfig(DefaultMojeekSelectors, opts)
+	return &mojeekGoQueryEngine{
+		client: config.applyProxy(&http.Client{
+			Timeout: 10 * time.Second,
+		}),
+		config: config,
+	}
+}
+
+func (m *mojeekGoQueryEngine) Name() string {
+	return "mojeek"
+}
+
+func (m *mojeekGoQueryEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	return m.SearchQuery(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+// SearchQuery is Search with the full set of per-search parameters. Mojeek
+// biases results towards Language and Region rather than restricting them;
+// After, Before and SafeSearch are ignored.
+func (m *mojeekGoQueryEngine) SearchQuery(ctx context.Context, q EngineQuery) ([]SearchResult, error) {
+	if m.config.err != nil {
+		return nil, m.config.err
+	}
+
+	searchURL := fmt.Sprintf("https://www.mojeek.com/search?q=%s", url.QueryEscape(q.Query))
+	if q.Offset > 0 {
+		// s is the 1-based position of the first result on the page
+		searchURL += fmt.Sprintf("&s=%d", q.Offset+1)
+	}
+	if q.Language != "" {
+		searchURL += "&lb=" + url.QueryEscape(strings.ToLower(q.Language))
+	}
+	if q.Region != "" {
+		searchURL += "&arc=" + url.QueryEscape(strings.ToLower(q.Region))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", m.config.userAgent())
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8")
+	req.Header.Set("Accept-Language", q.acceptLanguage("en-US,en;q=0.9"))
+
+	resp, err := fetchResultsPage(m.client, req, m.Name())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Mojeek answers clients it takes for automated traffic with a 403
+	if resp.StatusCode == http.StatusForbidden {
+		return nil, &BlockedError{Engine: m.Name(), URL: searchURL}
+	}
+
+	doc, err := parseLimitedBody(resp.Body, m.config.maxBodySize, searchURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	results := m.parseResults(doc, q.MaxResults)
+	if len(results) == 0 && captchaPage(doc) {
+		return nil, &BlockedError{Engine: m.Name(), URL: searchURL}
+	}
+	return results, nil
+}
+
+func (m *mojeekGoQueryEngine) parseResults(doc *goquery.Document, maxResults int) []SearchResult {
+	sel := m.config.selectors
+	var results []SearchResult
+
+	doc.Find(sel.Result).Each(func(i int, r *goquery.Selection) {
+		if len(results) >= maxResults || i < m.config.skipFirst {
+			return
+		}
+
+		title := firstText(r, sel.Title)
+		link := firstAttr(r, sel.Link, "href")
+		snippet := firstText(r, sel.Snippet)
+
+		if link != "" && title != "" {
+			results = append(results, SearchResult{
+				Title:   title,
+				URL:     link,
+				Snippet: snippet,
+				Engine:  m.Name(),
+			})
+		}
+	})
+
+	return results
+}
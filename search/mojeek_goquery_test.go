@@ -0,0 +1,65 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const mojeekFixture = `
+	<html><body><ul class="results-standard">
+		<li>
+			<h2 class="title"><a href="https://go.dev/">The Go Programming Language</a></h2>
+			<p class="s">Go is an open source programming language.</p>
+		</li>
+		<li>
+			<h2 class="title"><a href="https://gobyexample.com/">Go by Example</a></h2>
+			<p class="s">Hands-on introduction to Go.</p>
+		</li>
+	</ul></body></html>`
+
+func TestMojeekGoQueryEngine_ParseResults(t *testing.T) {
+	engine := NewMojeekGoQueryEngine().(*mojeekGoQueryEngine)
+	transport := &fixtureTransport{body: mojeekFixture}
+	engine.client.Transport = transport
+
+	results, err := engine.SearchQuery(context.Background(), EngineQuery{Query: "golang", MaxResults: 10, Offset: 10, Language: "de"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %+v", len(results), results)
+	}
+	if results[0].Title != "The Go Programming Language" || results[0].URL != "https://go.dev/" ||
+		results[0].Snippet != "Go is an open source programming language." || results[0].Engine != "mojeek" {
+		t.Errorf("unexpected first result: %+v", results[0])
+	}
+	if want := "https://www.mojeek.com/search?q=golang&s=11&lb=de"; transport.urls[0] != want {
+		t.Errorf("expected request URL %s, got %s", want, transport.urls[0])
+	}
+}
+
+// statusTransport answers every request with an empty page and a fixed
+// status
+type statusTransport int
+
+func (s statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: int(s),
+		Body:       io.NopCloser(strings.NewReader("<html><body>Sorry, automated queries</body></html>")),
+		Request:    req,
+	}, nil
+}
+
+func TestMojeekGoQueryEngine_Forbidden(t *testing.T) {
+	engine := NewMojeekGoQueryEngine().(*mojeekGoQueryEngine)
+	engine.client.Transport = statusTransport(http.StatusForbidden)
+
+	if _, err := engine.Search(context.Background(), "golang", 10); !errors.Is(err, ErrBlocked) {
+		t.Fatalf("expected ErrBlocked, got %v", err)
+	}
+}
@@ -3,7 +3,6 @@ package search
 import (
 	"context"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/liliang-cn/mcp-websearch-server/extraction"
@@ -12,121 +11,266 @@ import (
 type multiEngineSearcher struct {
 	engines   map[string]SearchEngine
 	extractor ContentExtractor
+	quota     *queryQuota
+	ttls      map[PageType]time.Duration
+	picker    *enginePicker
+	// archiveBase is the Wayback Machine prefix for archive fallback; empty
+	// disables it
+	archiveBase string
+	blocklist   engineBlocklist
+	translator  *snippetTranslator
+	// maxQueryLength limits query length; zero means
+	// DefaultMaxQueryLength and negative disables the limit
+	maxQueryLength int
+	// overshoot multiplies the results fetched from engines when filters
+	// are set
+	overshoot int
+	// extractConcurrency is the WithExtractConcurrency setting, zero for
+	// the default
+	extractConcurrency int
+	// stats counts engine requests, failures and fallbacks
+	stats *searchStats
+	// priority is the engine priority order; nil means basicPriority
+	priority []string
+	// defaultTimeout is the WithDefaultTimeout setting, zero for the
+	// default
+	defaultTimeout time.Duration
 }
 
-func NewMultiEngineSearcher() MultiEngineSearcher {
-	// Use the hybrid approach by default (goquery + chromedp)
-	return NewHybridSearcher()
-}
+// basicPriority is the basic searcher's default engine priority order
+var basicPriority = []string{"searxng", "bing", "brave", "duckduckgo", "mojeek", "startpage", "google", "yandex", "qwant"}
 
-// NewBasicMultiEngineSearcher creates a basic searcher without chromedp
-func NewBasicMultiEngineSearcher() MultiEngineSearcher {
-	return &multiEngineSearcher{
-		engines: map[string]SearchEngine{
-			"bing":       NewBingGoQueryEngine(),
-			"brave":      NewBraveGoQueryEngine(),
-			"duckduckgo": NewDuckDuckGoGoQueryEngine(),
-		},
-		extractor: extraction.NewChromedpExtractor(),
+func NewMultiEngineSearcher(opts ...SearcherOption) MultiEngineSearcher {
+	// Search and extract over plain HTTP when the browser is opted out of
+	if o := applySearcherOptions(opts); o.httpExtraction {
+		m := NewBasicMultiEngineSearcher(opts...).(*multiEngineSearcher)
+		if o.extractor == nil {
+			m.extractor = extraction.NewHTTPExtractor(o.httpOptions()...)
+		}
+		return m
 	}
+
+	// Use the hybrid approach by default (goquery + chromedp)
+	return NewHybridSearcher(opts...)
 }
 
-func (m *multiEngineSearcher) Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
-	if opts.Timeout == 0 {
-		opts.Timeout = 30 * time.Second
+// NewBasicMultiEngineSearcher creates a basic searcher without chromedp
+func NewBasicMultiEngineSearcher(opts ...SearcherOption) MultiEngineSearcher {
+	o := applySearcherOptions(opts)
+	engines := o.engineSet(func() map[string]SearchEngine {
+		engineOpts := o.engineOptions()
+		return map[string]SearchEngine{
+			"bing":       NewBingGoQueryEngine(engineOpts...),
+			"brave":      NewBraveGoQueryEngine(engineOpts...),
+			"duckduckgo": NewDuckDuckGoGoQueryEngine(engineOpts...),
+			"mojeek":     NewMojeekGoQueryEngine(engineOpts...),
+			"startpage":  NewStartpageGoQueryEngine(engineOpts...),
+			"google":     NewGoogleGoQueryEngine(engineOpts...),
+			"yandex":     NewYandexGoQueryEngine(engineOpts...),
+			"qwant":      NewQwantEngine(engineOpts...),
+		}
+	})
+	var extractor ContentExtractor = extraction.NewChromedpExtractor(o.chromedpOptions()...)
+	if o.extractor != nil {
+		extractor = o.extractor
 	}
-
-	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
-	defer cancel()
-
-	engine := m.selectEngine(opts.Engines)
-	if engine == nil {
-		return nil, fmt.Errorf("no search engine available")
+	m := &multiEngineSearcher{
+		engines:     engines,
+		extractor:   extractor,
+		quota:       o.quota,
+		ttls:        o.ttls,
+		picker:      newEnginePicker(o.seed, o.weights),
+		archiveBase: o.archiveBase(),
+		translator:  o.translator,
+
+		maxQueryLength: o.maxQueryLength,
+		overshoot:      o.overshoot,
+
+		extractConcurrency: o.extractConcurrency,
+		stats:              newSearchStats(),
+		priority:           o.enginePriority(engines, basicPriority),
+		defaultTimeout:     o.defaultTimeout,
 	}
+	return m
+}
 
-	results, err := engine.Search(ctx, query, opts.MaxResults)
-	if err != nil {
-		results, err = m.fallbackSearch(ctx, query, opts.MaxResults, engine.Name())
-		if err != nil {
-			return nil, fmt.Errorf("all search engines failed: %w", err)
+// NewSearcherWithEngines creates a searcher from the given engines, keyed by
+// name, and extractor. It lets callers, tests in particular, build a
+// searcher from their own implementations. extractor may be nil if content
+// extraction is never requested. WithEngines and WithExtractor override
+// engines and extractor.
+func NewSearcherWithEngines(engines map[string]SearchEngine, extractor ContentExtractor, opts ...SearcherOption) MultiEngineSearcher {
+	o := applySearcherOptions(opts)
+	named := o.engineSet(func() map[string]SearchEngine {
+		defaults := make(map[string]SearchEngine, len(engines))
+		for name, engine := range engines {
+			defaults[normalizeEngineName(name)] = engine
 		}
+		return defaults
+	})
+	if o.extractor != nil {
+		extractor = o.extractor
 	}
+	return &multiEngineSearcher{
+		engines:     named,
+		extractor:   extractor,
+		quota:       o.quota,
+		ttls:        o.ttls,
+		picker:      newEnginePicker(o.seed, o.weights),
+		archiveBase: o.archiveBase(),
+		translator:  o.translator,
+
+		maxQueryLength: o.maxQueryLength,
+		overshoot:      o.overshoot,
+
+		extractConcurrency: o.extractConcurrency,
+		stats:              newSearchStats(),
+		priority:           o.enginePriority(named, basicPriority),
+		defaultTimeout:     o.defaultTimeout,
+	}
+}
 
-	if opts.ExtractContent && len(results) > 0 {
-		m.extractContentConcurrently(ctx, results)
+// pipeline returns the search pipeline over m's engines, extracting content
+// with m's extractor
+func (m *multiEngineSearcher) pipeline() searchPipeline {
+	return searchPipeline{
+		router:         m,
+		engines:        m.engines,
+		quota:          m.quota,
+		picker:         m.picker,
+		maxQueryLength: m.maxQueryLength,
+		overshoot:      m.overshoot,
+		ttls:           m.ttls,
+		translator:     m.translator,
+		stats:          m.stats,
+		defaultTimeout: m.defaultTimeout,
+
+		extract:            m.extractResult,
+		extractConcurrency: m.extractConcurrency,
+		defaultConcurrency: 3,
 	}
+}
 
-	return results, nil
+func (m *multiEngineSearcher) Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	return m.pipeline().search(ctx, query, opts)
 }
 
 func (m *multiEngineSearcher) DeepSearch(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
-	if opts.Timeout == 0 {
-		opts.Timeout = 60 * time.Second
-	}
+	_, results, err := m.DeepSearchDetailed(ctx, query, opts)
+	return results, err
+}
 
-	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
-	defer cancel()
+// DeepSearchDetailed is DeepSearch that also returns each queried engine's
+// raw results, keyed by engine name, from before they were merged, filtered
+// and ranked. Engines that failed map to nil.
+func (m *multiEngineSearcher) DeepSearchDetailed(ctx context.Context, query string, opts SearchOptions) (map[string][]SearchResult, []SearchResult, error) {
+	return m.pipeline().deepSearch(ctx, query, opts)
+}
 
-	var allResults []SearchResult
-	var mu sync.Mutex
-	var wg sync.WaitGroup
+// SearchStream is DeepSearch that sends each result as soon as its engine
+// answers and, if opts.ExtractContent is set, its content is extracted, so
+// a slow engine doesn't hold back the others. Results are deduplicated and
+// capped at opts.MaxResults but not ranked. The result channel is closed
+// when the search is done; the error channel then yields the first error,
+// if any, and is closed. Cancel ctx to stop early.
+func (m *multiEngineSearcher) SearchStream(ctx context.Context, query string, opts SearchOptions) (<-chan SearchResult, <-chan error) {
+	return m.pipeline().stream(ctx, query, opts)
+}
 
-	engines := m.getEngines(opts.Engines)
-	if len(engines) == 0 {
-		return nil, fmt.Errorf("no search engines available")
+// DisableEngine stops the named engine from being used for selection,
+// fallback and deep search until EnableEngine is called. It is safe to call
+// while searches are running. A name that isn't a registered engine is an
+// *UnknownEngineError.
+func (m *multiEngineSearcher) DisableEngine(name string) error {
+	if err := checkEngineNames(m.engines, []string{name}); err != nil {
+		return err
 	}
+	m.blocklist.disable(name)
+	return nil
+}
 
-	resultsPerEngine := opts.MaxResults / len(engines)
-	if resultsPerEngine < 1 {
-		resultsPerEngine = 1
+// EnableEngine restores an engine disabled by DisableEngine. A name that
+// isn't a registered engine is an *UnknownEngineError.
+func (m *multiEngineSearcher) EnableEngine(name string) error {
+	if err := checkEngineNames(m.engines, []string{name}); err != nil {
+		return err
 	}
+	m.blocklist.enable(name)
+	return nil
+}
 
-	for _, engine := range engines {
-		wg.Add(1)
-		go func(eng SearchEngine) {
-			defer wg.Done()
-
-			results, err := eng.Search(ctx, query, resultsPerEngine)
-			if err != nil {
-				fmt.Printf("Engine %s failed: %v\n", eng.Name(), err)
-				return
-			}
-
-			mu.Lock()
-			allResults = append(allResults, results...)
-			mu.Unlock()
-		}(engine)
+// SearchImages searches for images with the first enabled engine in
+// ImageEngines that supports it
+func (m *multiEngineSearcher) SearchImages(ctx context.Context, query string, maxResults int) ([]ImageResult, error) {
+	query, err := sanitizeQuery(query, m.maxQueryLength)
+	if err != nil {
+		return nil, err
 	}
 
-	wg.Wait()
+	if err := m.quota.acquire(); err != nil {
+		return nil, err
+	}
 
-	if len(allResults) == 0 {
-		return nil, fmt.Errorf("no results from any search engine")
+	for _, name := range ImageEngines {
+		engine, ok := m.blocklist.lookup(m.engines, name)
+		if !ok {
+			continue
+		}
+		if images, ok := imageSearcher(engine); ok {
+			return images.SearchImages(ctx, query, maxResults)
+		}
 	}
+	return nil, fmt.Errorf("no image search engine available")
+}
 
-	if opts.ExtractContent {
-		m.extractContentConcurrently(ctx, allResults)
+// Suggest returns completions for a partly typed query from the first
+// enabled engine in SuggestEngines that answers. It makes one plain HTTP
+// request per engine tried and counts against the query quota.
+func (m *multiEngineSearcher) Suggest(ctx context.Context, prefix string) ([]string, error) {
+	prefix, err := sanitizeQuery(prefix, m.maxQueryLength)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(allResults) > opts.MaxResults {
-		allResults = allResults[:opts.MaxResults]
+	if err := m.quota.acquire(); err != nil {
+		return nil, err
 	}
 
-	return allResults, nil
+	return suggest(ctx, m.engines, &m.blocklist, prefix)
+}
+
+// QueryCount returns the number of searches attempted, including ones
+// rejected by the query quota
+func (m *multiEngineSearcher) QueryCount() int64 {
+	return m.quota.count()
+}
+
+// HealthCheck sends a trivial query to every registered engine, including
+// disabled ones, concurrently and with a short timeout each, and returns
+// each engine's error keyed by name; nil means the engine returned results.
+// It doesn't stop at the first failure, so it suits startup and readiness
+// probes that need to spot, say, one engine serving captchas.
+func (m *multiEngineSearcher) HealthCheck(ctx context.Context) map[string]error {
+	return checkEngineHealth(ctx, m.engines)
+}
+
+// Stats returns a snapshot of each engine's request, success, failure and
+// fallback counts. It is safe to call while searches are running.
+func (m *multiEngineSearcher) Stats() Stats {
+	return m.stats.snapshot()
 }
 
 func (m *multiEngineSearcher) selectEngine(preferred []string) SearchEngine {
 	if len(preferred) > 0 {
 		for _, name := range preferred {
-			if engine, ok := m.engines[name]; ok {
+			if engine, ok := m.blocklist.lookup(m.engines, name); ok {
 				return engine
 			}
 		}
 	}
 
-	priorityOrder := []string{"bing", "brave", "duckduckgo"}
-	for _, name := range priorityOrder {
-		if engine, ok := m.engines[name]; ok {
+	for _, name := range m.priorityOrder() {
+		if engine, ok := m.blocklist.lookup(m.engines, name); ok {
 			return engine
 		}
 	}
@@ -134,33 +278,56 @@ func (m *multiEngineSearcher) selectEngine(preferred []string) SearchEngine {
 	return nil
 }
 
-func (m *multiEngineSearcher) fallbackSearch(ctx context.Context, query string, maxResults int, failedEngine string) ([]SearchResult, error) {
-	priorityOrder := []string{"bing", "brave", "duckduckgo"}
-
-	for _, name := range priorityOrder {
+// fallbackSearch tries the other engines in priority order until one
+// answers. It gives up early when the search is cancelled or fails in a way
+// no other engine would avoid, and otherwise wraps the last engine's error.
+func (m *multiEngineSearcher) fallbackSearch(ctx context.Context, q EngineQuery, failedEngine string) ([]SearchResult, error) {
+	var lastErr error
+	for _, name := range m.priorityOrder() {
 		if name == failedEngine {
 			continue
 		}
 
-		if engine, ok := m.engines[name]; ok {
-			results, err := engine.Search(ctx, query, maxResults)
+		if engine, ok := m.blocklist.lookup(m.engines, name); ok {
+			results, err := m.stats.search(ctx, engine, q)
 			if err == nil {
 				return results, nil
 			}
+			if !recoverable(err) || ctx.Err() != nil {
+				return nil, err
+			}
+			lastErr = err
 		}
 	}
 
+	if lastErr != nil {
+		return nil, fmt.Errorf("all fallback engines failed: %w", lastErr)
+	}
 	return nil, fmt.Errorf("all fallback engines failed")
 }
 
+// priorityOrder returns the engine names in priority order
+func (m *multiEngineSearcher) priorityOrder() []string {
+	if m.priority == nil {
+		return basicPriority
+	}
+	return m.priority
+}
+
+// hasEngine reports whether name is a registered engine
+func (m *multiEngineSearcher) hasEngine(name string) bool {
+	_, ok := m.engines[normalizeEngineName(name)]
+	return ok
+}
+
 func (m *multiEngineSearcher) getEngines(names []string) []SearchEngine {
 	if len(names) == 0 {
-		names = []string{"bing", "brave", "duckduckgo"}
+		names = m.priorityOrder()
 	}
 
 	var engines []SearchEngine
 	for _, name := range names {
-		if engine, ok := m.engines[name]; ok {
+		if engine, ok := m.blocklist.lookup(m.engines, name); ok {
 			engines = append(engines, engine)
 		}
 	}
@@ -168,25 +335,62 @@ func (m *multiEngineSearcher) getEngines(names []string) []SearchEngine {
 	return engines
 }
 
-func (m *multiEngineSearcher) extractContentConcurrently(ctx context.Context, results []SearchResult) {
-	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, 3)
-
-	for i := range results {
-		wg.Add(1)
-		go func(idx int) {
-			defer wg.Done()
+// extractResult fills in r's content, status and dates from its page
+func (m *multiEngineSearcher) extractResult(ctx context.Context, r *SearchResult) {
+	reader, detailed := m.extractor.(pageReader)
+	if !detailed {
+		reader = contentPageExtractor{m.extractor}
+	}
 
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
+	page, err := reader.ExtractPage(ctx, r.URL)
+	if err != nil {
+		r.ExtractError = err.Error()
+	} else {
+		r.Content = page.Content
+		r.ContentHash = page.ContentHash
+		r.ExtractedAt = time.Now()
+		r.ContentSource = ContentSourceLive
+		r.HTTPStatus = page.StatusCode
+		r.FinalURL = page.FinalURL
+		// Fall back to the Last-Modified header when the page has no
+		// better date
+		if r.Date().IsZero() {
+			r.LastModified = page.LastModified
+		}
+	}
 
-			content, err := m.extractor.ExtractContent(ctx, results[idx].URL)
-			if err == nil {
-				results[idx].Content = content
-				results[idx].ExtractedAt = time.Now()
+	// Without a page, or from an extractor that only returns content, a
+	// HEAD request still records the status and dates
+	if err != nil || !detailed {
+		if fetcher, ok := m.extractor.(pageInfoFetcher); ok {
+			if info, err := fetcher.PageInfo(ctx, r.URL); err == nil {
+				applyPageInfo(r, info)
 			}
-		}(i)
+		}
 	}
 
-	wg.Wait()
+	if m.archiveBase != "" && needsArchive(*r) {
+		if content, err := m.extractor.ExtractContent(ctx, m.archiveBase+r.URL); err == nil {
+			applyArchiveContent(r, content)
+		}
+	}
+
+	r.Language = detectLanguage(r.Content)
+}
+
+// otherEngines returns the default engines that did not contribute to
+// results, in priority order.
+func (m *multiEngineSearcher) otherEngines(results []SearchResult) []SearchEngine {
+	used := make(map[string]bool)
+	for _, r := range results {
+		used[r.Engine] = true
+	}
+
+	var engines []SearchEngine
+	for _, engine := range m.getEngines(nil) {
+		if !used[engine.Name()] {
+			engines = append(engines, engine)
+		}
+	}
+	return engines
 }
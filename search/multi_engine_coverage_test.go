@@ -3,7 +3,15 @@ package search
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/liliang-cn/mcp-websearch-server/extraction"
 )
 
 func TestMultiEngineSearcher_SearchWithContent(t *testing.T) {
@@ -124,7 +132,7 @@ func TestMultiEngineSearcher_FallbackAllFail(t *testing.T) {
 		extractor: &mockContentExtractor{},
 	}
 
-	_, err := searcher.fallbackSearch(context.Background(), "test", 10, "primary")
+	_, err := searcher.fallbackSearch(context.Background(), EngineQuery{Query: "test", MaxResults: 10}, "primary")
 	if err == nil {
 		t.Error("expected error when all engines fail")
 	}
@@ -205,25 +213,27 @@ func TestMultiEngineSearcher_ExtractorFailure(t *testing.T) {
 	if !results[0].ExtractedAt.IsZero() {
 		t.Error("ExtractedAt should not be set when extraction fails")
 	}
+
+	if results[0].ExtractError != "extraction failed" {
+		t.Errorf("expected ExtractError to record the failure, got %q", results[0].ExtractError)
+	}
 }
 
 func TestMultiEngineSearcher_LimitResults(t *testing.T) {
-	mockEngine := &mockSearchEngine{
-		name: "test",
-		results: []SearchResult{
-			{Title: "Result 1", URL: "http://1.com"},
-			{Title: "Result 2", URL: "http://2.com"},
-			{Title: "Result 3", URL: "http://3.com"},
-			{Title: "Result 4", URL: "http://4.com"},
-			{Title: "Result 5", URL: "http://5.com"},
-		},
+	// Each engine returns distinct pages so deduplication doesn't merge them
+	engineWithResults := func(name string) *mockSearchEngine {
+		engine := &mockSearchEngine{name: name}
+		for i := 1; i <= 5; i++ {
+			engine.results = append(engine.results, SearchResult{Title: fmt.Sprintf("Result %d", i), URL: fmt.Sprintf("http://%s.com/%d", name, i)})
+		}
+		return engine
 	}
 
 	searcher := &multiEngineSearcher{
 		engines: map[string]SearchEngine{
-			"test":  mockEngine,
-			"bing":  mockEngine,
-			"brave": mockEngine,
+			"test":  engineWithResults("test"),
+			"bing":  engineWithResults("bing"),
+			"brave": engineWithResults("brave"),
 		},
 		extractor: &mockContentExtractor{},
 	}
@@ -242,3 +252,218 @@ func TestMultiEngineSearcher_LimitResults(t *testing.T) {
 		t.Errorf("expected results to be limited to 2, got %d", len(results))
 	}
 }
+
+type pageInfoExtractor struct {
+	mockContentExtractor
+	client *http.Client
+}
+
+func (e *pageInfoExtractor) PageInfo(ctx context.Context, url string) (*extraction.PageInfo, error) {
+	return extraction.FetchPageInfo(ctx, e.client, url)
+}
+
+func TestMultiEngineSearcher_LastModifiedFallback(t *testing.T) {
+	modified := time.Date(2024, 3, 15, 8, 30, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+	}))
+	defer server.Close()
+
+	mockEngine := &mockSearchEngine{
+		name:    "bing",
+		results: []SearchResult{{Title: "Result", URL: server.URL + "/page"}},
+	}
+
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{"bing": mockEngine},
+		extractor: &pageInfoExtractor{
+			mockContentExtractor: mockContentExtractor{content: "content"},
+			client:               server.Client(),
+		},
+	}
+
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{
+		MaxResults:     1,
+		ExtractContent: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !results[0].LastModified.Equal(modified) {
+		t.Errorf("expected LastModified %v, got %v", modified, results[0].LastModified)
+	}
+
+	if !results[0].Date().Equal(modified) {
+		t.Errorf("expected Date() to fall back to LastModified, got %v", results[0].Date())
+	}
+}
+
+// datedPageExtractor returns pages carrying a Last-Modified date and counts
+// the HEAD requests it is asked for
+type datedPageExtractor struct {
+	mockContentExtractor
+	lastModified time.Time
+	infoCalls    int32
+}
+
+func (e *datedPageExtractor) ExtractPage(ctx context.Context, url string) (*extraction.Page, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return &extraction.Page{URL: url, FinalURL: url, StatusCode: http.StatusOK, Content: e.content, LastModified: e.lastModified}, nil
+}
+
+func (e *datedPageExtractor) PageInfo(ctx context.Context, url string) (*extraction.PageInfo, error) {
+	atomic.AddInt32(&e.infoCalls, 1)
+	return &extraction.PageInfo{StatusCode: http.StatusNotFound, FinalURL: url, LastModified: e.lastModified}, nil
+}
+
+func (e *datedPageExtractor) Warmup(ctx context.Context) error { return nil }
+func (e *datedPageExtractor) Close() error                     { return nil }
+
+func TestHybridSearcher_LastModifiedFromPage(t *testing.T) {
+	modified := time.Date(2024, 3, 15, 8, 30, 0, 0, time.UTC)
+	extractor := &datedPageExtractor{mockContentExtractor: mockContentExtractor{content: "content"}, lastModified: modified}
+	searcher := &HybridMultiEngineSearcher{
+		engines:   map[string]SearchEngine{"bing": &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Result", URL: "http://a.com"}}}},
+		extractor: extractor,
+	}
+
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{MaxResults: 1, ExtractContent: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].LastModified.Equal(modified) || results[0].HTTPStatus != http.StatusOK {
+		t.Errorf("expected the page's status and Last-Modified date, got %+v", results[0])
+	}
+	if n := atomic.LoadInt32(&extractor.infoCalls); n != 0 {
+		t.Errorf("expected no HEAD request after a successful extraction, got %d", n)
+	}
+
+	// A failed extraction still records the status with a HEAD request
+	extractor.err = errors.New("render failed")
+	searcher.engines["bing"] = &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Result", URL: "http://b.com"}}}
+	results, _ = searcher.Search(context.Background(), "another test", SearchOptions{MaxResults: 1, ExtractContent: true})
+	if results[0].HTTPStatus != http.StatusNotFound || atomic.LoadInt32(&extractor.infoCalls) != 1 {
+		t.Errorf("expected the status from a HEAD request, got %d", results[0].HTTPStatus)
+	}
+}
+
+func TestMultiEngineSearcher_LastModifiedFromPage(t *testing.T) {
+	modified := time.Date(2024, 3, 15, 8, 30, 0, 0, time.UTC)
+	extractor := &datedPageExtractor{mockContentExtractor: mockContentExtractor{content: "content"}, lastModified: modified}
+	searcher := &multiEngineSearcher{
+		engines:   map[string]SearchEngine{"bing": &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Result", URL: "http://a.com"}}}},
+		extractor: extractor,
+	}
+
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{MaxResults: 1, ExtractContent: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].LastModified.Equal(modified) || results[0].HTTPStatus != http.StatusOK || results[0].FinalURL != "http://a.com" {
+		t.Errorf("expected the page's status, final URL and Last-Modified date, got %+v", results[0])
+	}
+	if n := atomic.LoadInt32(&extractor.infoCalls); n != 0 {
+		t.Errorf("expected no HEAD request after a successful extraction, got %d", n)
+	}
+
+	// A failed extraction still records the status with a HEAD request
+	extractor.err = errors.New("fetch failed")
+	searcher.engines["bing"] = &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Result", URL: "http://b.com"}}}
+	results, _ = searcher.Search(context.Background(), "another test", SearchOptions{MaxResults: 1, ExtractContent: true})
+	if results[0].HTTPStatus != http.StatusNotFound || atomic.LoadInt32(&extractor.infoCalls) != 1 {
+		t.Errorf("expected the status from a HEAD request, got %d", results[0].HTTPStatus)
+	}
+}
+
+func TestMultiEngineSearcher_HTTPStatusAndFinalURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusFound)
+		case "/new":
+			w.WriteHeader(http.StatusNonAuthoritativeInfo)
+		}
+	}))
+	defer server.Close()
+
+	mockEngine := &mockSearchEngine{
+		name:    "bing",
+		results: []SearchResult{{Title: "Result", URL: server.URL + "/old"}},
+	}
+
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{"bing": mockEngine},
+		extractor: &pageInfoExtractor{
+			mockContentExtractor: mockContentExtractor{err: errors.New("extraction failed")},
+			client:               server.Client(),
+		},
+	}
+
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{
+		MaxResults:     1,
+		ExtractContent: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Status and final URL are recorded even when extraction fails
+	if results[0].HTTPStatus != http.StatusNonAuthoritativeInfo {
+		t.Errorf("expected status 203, got %d", results[0].HTTPStatus)
+	}
+	if results[0].FinalURL != server.URL+"/new" {
+		t.Errorf("expected final URL %s/new, got %s", server.URL, results[0].FinalURL)
+	}
+	if results[0].URL != server.URL+"/old" {
+		t.Errorf("expected original URL to be kept, got %s", results[0].URL)
+	}
+}
+
+type countingExtractor struct {
+	mu   sync.Mutex
+	urls []string
+}
+
+func (e *countingExtractor) ExtractContent(ctx context.Context, url string) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.urls = append(e.urls, url)
+	return "content", nil
+}
+
+func TestMultiEngineSearcher_DeepSearchRanksBeforeExtracting(t *testing.T) {
+	extractor := &countingExtractor{}
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing":       &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Cooking recipes", URL: "http://a.com", Engine: "bing"}}},
+			"brave":      &mockSearchEngine{name: "brave", results: []SearchResult{{Title: "Golang tutorial", URL: "http://b.com", Engine: "brave"}}},
+			"duckduckgo": &mockSearchEngine{name: "duckduckgo", results: []SearchResult{{Title: "Golang basics", URL: "http://c.com", Engine: "duckduckgo"}}},
+		},
+		extractor: extractor,
+	}
+
+	results, err := searcher.DeepSearch(context.Background(), "golang tutorial", SearchOptions{
+		MaxResults:     2,
+		ExtractContent: true,
+		Rank:           true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 2 || results[0].URL != "http://b.com" || results[1].URL != "http://c.com" {
+		t.Fatalf("unexpected ranked results: %+v", results)
+	}
+
+	if len(extractor.urls) != 2 {
+		t.Fatalf("expected 2 extractions, got %d: %v", len(extractor.urls), extractor.urls)
+	}
+	for _, u := range extractor.urls {
+		if u == "http://a.com" {
+			t.Error("expected the result dropped by ranking not to be extracted")
+		}
+	}
+}
@@ -3,6 +3,7 @@ package search
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -11,6 +12,7 @@ type mockSearchEngine struct {
 	name    string
 	results []SearchResult
 	err     error
+	calls   int32
 }
 
 func (m *mockSearchEngine) Name() string {
@@ -18,6 +20,7 @@ func (m *mockSearchEngine) Name() string {
 }
 
 func (m *mockSearchEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	atomic.AddInt32(&m.calls, 1)
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -164,6 +167,49 @@ func TestMultiEngineSearcher_DeepSearch(t *testing.T) {
 	}
 }
 
+func TestMultiEngineSearcher_DeepSearchMaxEngines(t *testing.T) {
+	engines := map[string]*mockSearchEngine{
+		"bing":       {name: "bing", results: []SearchResult{{Title: "Bing", URL: "http://bing.example.com", Engine: "bing"}}},
+		"brave":      {name: "brave", results: []SearchResult{{Title: "Brave", URL: "http://brave.example.com", Engine: "brave"}}},
+		"duckduckgo": {name: "duckduckgo", results: []SearchResult{{Title: "DDG", URL: "http://ddg.example.com", Engine: "duckduckgo"}}},
+	}
+
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing":       engines["bing"],
+			"brave":      engines["brave"],
+			"duckduckgo": engines["duckduckgo"],
+		},
+		extractor: &mockContentExtractor{},
+	}
+
+	results, err := searcher.DeepSearch(context.Background(), "test", SearchOptions{
+		MaxResults: 10,
+		MaxEngines: 2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Errorf("expected 2 results, got %d", len(results))
+	}
+
+	invoked := 0
+	for _, e := range engines {
+		if atomic.LoadInt32(&e.calls) > 0 {
+			invoked++
+		}
+	}
+	if invoked != 2 {
+		t.Errorf("expected 2 engines to be queried, got %d", invoked)
+	}
+
+	if engines["duckduckgo"].calls != 0 {
+		t.Error("expected lowest-priority engine to be skipped")
+	}
+}
+
 func TestMultiEngineSearcher_SelectEngine(t *testing.T) {
 	searcher := &multiEngineSearcher{
 		engines: map[string]SearchEngine{
@@ -260,7 +306,7 @@ func TestMultiEngineSearcher_FallbackSearch(t *testing.T) {
 		extractor: &mockContentExtractor{content: "content"},
 	}
 
-	results, err := searcher.fallbackSearch(context.Background(), "test", 10, "failing")
+	results, err := searcher.fallbackSearch(context.Background(), EngineQuery{Query: "test", MaxResults: 10}, "failing")
 	if err != nil {
 		t.Errorf("expected fallback to succeed, got error: %v", err)
 	}
@@ -274,6 +320,28 @@ func TestMultiEngineSearcher_FallbackSearch(t *testing.T) {
 	}
 }
 
+func TestMultiEngineSearcher_FallbackSearchErrors(t *testing.T) {
+	limited := &mockSearchEngine{name: "bing", err: &RateLimitedError{Engine: "bing"}}
+	blocked := &mockSearchEngine{name: "brave", err: &BlockedError{Engine: "brave"}}
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{"bing": limited, "brave": blocked},
+	}
+
+	// The last engine's error is kept for errors.Is
+	_, err := searcher.fallbackSearch(context.Background(), EngineQuery{Query: "test", MaxResults: 10}, "duckduckgo")
+	if !errors.Is(err, ErrBlocked) {
+		t.Errorf("expected the last engine's ErrBlocked, got %v", err)
+	}
+
+	// An error no other engine would avoid stops the fallback
+	limited.err = &QueryTooLongError{Length: 500, Max: 400}
+	blocked.calls = 0
+	_, err = searcher.fallbackSearch(context.Background(), EngineQuery{Query: "test", MaxResults: 10}, "duckduckgo")
+	if !errors.Is(err, ErrQueryTooLong) || blocked.calls != 0 {
+		t.Errorf("expected ErrQueryTooLong without trying brave, got %v after %d calls", err, blocked.calls)
+	}
+}
+
 func TestMultiEngineSearcher_GetEngines(t *testing.T) {
 	searcher := &multiEngineSearcher{
 		engines: map[string]SearchEngine{
@@ -331,7 +399,7 @@ func TestMultiEngineSearcher_ExtractContentConcurrently(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	searcher.extractContentConcurrently(ctx, results)
+	extractResults(ctx, results, 3, searcher.extractResult)
 
 	for _, r := range results {
 		if r.Content != "extracted content" {
@@ -342,3 +410,84 @@ func TestMultiEngineSearcher_ExtractContentConcurrently(t *testing.T) {
 		}
 	}
 }
+
+func TestNewSearcherWithEngines(t *testing.T) {
+	var searcher MultiEngineSearcher = NewSearcherWithEngines(map[string]SearchEngine{
+		"Bing": &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Fake", URL: "http://fake.com", Engine: "bing"}}},
+	}, &mockContentExtractor{content: "fake content"})
+
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{
+		MaxResults:     1,
+		ExtractContent: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 1 || results[0].URL != "http://fake.com" {
+		t.Fatalf("expected result from the fake engine, got %v", results)
+	}
+	if results[0].Content != "fake content" {
+		t.Errorf("expected content from the fake extractor, got %q", results[0].Content)
+	}
+}
+
+func TestMultiEngineSearcher_DeepSearchDetailed(t *testing.T) {
+	var _ DetailedSearcher = &HybridMultiEngineSearcher{}
+
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing": &mockSearchEngine{name: "bing", results: []SearchResult{
+				{Title: "Bing 1", URL: "http://one.com", Engine: "bing"},
+				{Title: "Bing 2", URL: "http://two.com", Engine: "bing"},
+			}},
+			"brave": &mockSearchEngine{name: "brave", results: []SearchResult{
+				{Title: "Brave 1", URL: "http://three.com", Engine: "brave"},
+				{Title: "Bing 1 again", URL: "http://ONE.com/?utm_source=brave", Engine: "brave"},
+			}},
+			"duckduckgo": &mockSearchEngine{name: "duckduckgo", err: errors.New("blocked")},
+		},
+		extractor: &mockContentExtractor{},
+	}
+
+	var detailed DetailedSearcher = searcher
+	raw, merged, err := detailed.DeepSearchDetailed(context.Background(), "test", SearchOptions{
+		MaxResults: 6,
+		Engines:    []string{"bing", "brave", "duckduckgo"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(raw) != 3 {
+		t.Fatalf("expected an entry per queried engine, got %v", raw)
+	}
+	for _, name := range []string{"bing", "brave", "duckduckgo"} {
+		if _, ok := raw[name]; !ok {
+			t.Errorf("expected raw results for %s", name)
+		}
+	}
+	if len(raw["bing"]) != 2 || len(raw["brave"]) != 2 || raw["duckduckgo"] != nil {
+		t.Errorf("unexpected per-engine results: %v", raw)
+	}
+
+	// The merged set is the union of the raw results with duplicates merged
+	urls := map[string]bool{}
+	for _, r := range merged {
+		key := normalizeURL(r.URL)
+		if urls[key] {
+			t.Errorf("expected %s once in the merged results", r.URL)
+		}
+		urls[key] = true
+	}
+	for _, results := range raw {
+		for _, r := range results {
+			if !urls[normalizeURL(r.URL)] {
+				t.Errorf("expected %s in the merged results", r.URL)
+			}
+		}
+	}
+	if len(merged) != 3 {
+		t.Errorf("expected 3 distinct results, got %d", len(merged))
+	}
+}
@@ -0,0 +1,109 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// VerticalNews is the SearchOptions.Vertical value that searches the
+// engines' news indexes instead of the web
+const VerticalNews = "news"
+
+// NewsEngines are the engines with a news vertical, in the order
+// SearchNews queries them by default
+var NewsEngines = []string{"bing", "duckduckgo"}
+
+// SearchNews searches the news verticals of the searcher's NewsEngines, or
+// of opts.Engines if set, and returns the merged results newest first.
+// Results carry PublishedAt and Source, and a story returned by more than
+// one engine appears once. Naming an engine without a news vertical fails
+// with an *UnsupportedVerticalError.
+func SearchNews(ctx context.Context, searcher MultiEngineSearcher, query string, opts SearchOptions) ([]SearchResult, error) {
+	if len(opts.Engines) == 0 {
+		opts.Engines = availableNewsEngines(searcher)
+	}
+
+	var unsupported []string
+	for _, name := range opts.Engines {
+		if !hasNewsVertical(normalizeEngineName(name)) {
+			unsupported = append(unsupported, name)
+		}
+	}
+	if len(unsupported) > 0 {
+		return nil, &UnsupportedVerticalError{Vertical: VerticalNews, Engines: unsupported}
+	}
+
+	opts.Vertical = VerticalNews
+	results, err := searcher.DeepSearch(ctx, query, opts)
+	if err != nil {
+		return results, err
+	}
+
+	sortByRecency(results)
+	return results, nil
+}
+
+// engineRegistry is implemented by searchers that can report which
+// engines they were built with
+type engineRegistry interface {
+	hasEngine(name string) bool
+}
+
+// availableNewsEngines returns the NewsEngines searcher has, or all of them
+// if it can't tell
+func availableNewsEngines(searcher MultiEngineSearcher) []string {
+	registry, ok := searcher.(engineRegistry)
+	if !ok {
+		return NewsEngines
+	}
+
+	var names []string
+	for _, name := range NewsEngines {
+		if registry.hasEngine(name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return NewsEngines
+	}
+	return names
+}
+
+// hasNewsVertical reports whether the named engine can search news
+func hasNewsVertical(name string) bool {
+	for _, news := range NewsEngines {
+		if name == news {
+			return true
+		}
+	}
+	return false
+}
+
+// sortByRecency orders results newest first by Date, keeping undated
+// results last in their original order
+func sortByRecency(results []SearchResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		di, dj := results[i].Date(), results[j].Date()
+		if di.IsZero() || dj.IsZero() {
+			return !di.IsZero() && dj.IsZero()
+		}
+		return di.After(dj)
+	})
+}
+
+// inDateRange reports whether published falls in q's date window. News
+// feeds only offer coarse "past day/week" filters, so the window is
+// applied to the dated results instead; undated results are kept.
+func (q EngineQuery) inDateRange(published time.Time) bool {
+	if published.IsZero() {
+		return true
+	}
+	if !q.After.IsZero() && published.Before(q.After) {
+		return false
+	}
+	if !q.Before.IsZero() && published.After(q.Before) {
+		return false
+	}
+	return true
+}
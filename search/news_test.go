@@ -0,0 +1,140 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const bingNewsFixture = `<?xml version="1.0" encoding="utf-8" ?>
+<rss version="2.0" xmlns:News="https://www.bing.com:443/news/search?q=golang&amp;format=rss">
+<channel>
+<title>golang - BingNews</title>
+<item>
+<title>Go 1.24 released</title>
+<link>http://www.bing.com/news/apiclick.aspx?ref=FexRss&amp;aid=&amp;tid=abc&amp;url=https%3a%2f%2fnews.example.com%2fgo-124&amp;c=123&amp;mkt=en-us</link>
+<description>The Go team released Go 1.24.</description>
+<pubDate>Tue, 11 Feb 2025 18:00:00 GMT</pubDate>
+<News:Source>Example News</News:Source>
+</item>
+<item>
+<title>Old Go news</title>
+<link>https://old.example.com/go</link>
+<description>From long ago.</description>
+<pubDate>Mon, 02 Jan 2023 09:00:00 GMT</pubDate>
+<News:Source>Old Times</News:Source>
+</item>
+</channel>
+</rss>`
+
+func TestParseBingNews(t *testing.T) {
+	results, err := parseBingNews([]byte(bingNewsFixture), EngineQuery{MaxResults: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	r := results[0]
+	if r.URL != "https://news.example.com/go-124" {
+		t.Errorf("expected the click-tracking link to be unwrapped, got %s", r.URL)
+	}
+	if r.Source != "Example News" {
+		t.Errorf("expected source Example News, got %q", r.Source)
+	}
+	if want := time.Date(2025, 2, 11, 18, 0, 0, 0, time.UTC); !r.PublishedAt.Equal(want) {
+		t.Errorf("expected PublishedAt %v, got %v", want, r.PublishedAt)
+	}
+
+	// The date window is applied to the feed's dates
+	results, _ = parseBingNews([]byte(bingNewsFixture), EngineQuery{MaxResults: 10, After: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+	if len(results) != 1 || results[0].Title != "Go 1.24 released" {
+		t.Errorf("expected only the recent story, got %+v", results)
+	}
+}
+
+func TestParseDuckDuckGoNews(t *testing.T) {
+	data := `{"results":[{"date":1739296800,"excerpt":"The <b>Go</b> team released Go 1.24.","source":"Example News","title":"Go 1.24 released","url":"https://news.example.com/go-124"},{"date":0,"title":"","url":"https://empty.example.com"}]}`
+
+	results, err := parseDuckDuckGoNews([]byte(data), EngineQuery{MaxResults: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected the untitled item to be dropped, got %d results", len(results))
+	}
+	r := results[0]
+	if r.Snippet != "The Go team released Go 1.24." || r.Source != "Example News" || r.Engine != "duckduckgo" {
+		t.Errorf("unexpected result: %+v", r)
+	}
+	if !r.PublishedAt.Equal(time.Unix(1739296800, 0)) {
+		t.Errorf("unexpected PublishedAt %v", r.PublishedAt)
+	}
+}
+
+func TestBingGoQuery_NewsVertical(t *testing.T) {
+	engine := NewBingGoQueryEngine()
+	transport := &fixtureTransport{body: bingNewsFixture}
+	engine.(*bingGoQueryEngine).client.Transport = transport
+
+	results, err := searchEngine(context.Background(), engine, EngineQuery{Query: "golang", MaxResults: 10, Vertical: VerticalNews})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("expected 2 news results, got %d", len(results))
+	}
+	if len(transport.urls) != 1 || !strings.HasPrefix(transport.urls[0], "https://www.bing.com/news/search?q=golang&format=rss") {
+		t.Errorf("expected a Bing News feed request, got %v", transport.urls)
+	}
+}
+
+func TestSearchNews(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 12, 0, 0, 0, time.UTC) }
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{
+		"bing": &mockSearchEngine{name: "bing", results: []SearchResult{
+			{Title: "Older story", URL: "https://a.example.com/older", Engine: "bing", PublishedAt: day(1)},
+			{Title: "Shared story", URL: "https://b.example.com/shared", Engine: "bing", PublishedAt: day(3)},
+		}},
+		"duckduckgo": &mockSearchEngine{name: "duckduckgo", results: []SearchResult{
+			{Title: "Shared story", URL: "https://b.example.com/shared/", Engine: "duckduckgo", PublishedAt: day(3)},
+			{Title: "Newest story", URL: "https://c.example.com/newest", Engine: "duckduckgo", PublishedAt: day(5)},
+		}},
+		"brave": &mockSearchEngine{name: "brave"},
+	}, nil)
+
+	results, err := SearchNews(context.Background(), searcher, "golang", SearchOptions{MaxResults: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var titles []string
+	for _, r := range results {
+		titles = append(titles, r.Title)
+	}
+	if got := strings.Join(titles, ", "); got != "Newest story, Shared story, Older story" {
+		t.Errorf("expected deduplicated results newest first, got %s", got)
+	}
+
+	_, err = SearchNews(context.Background(), searcher, "golang", SearchOptions{Engines: []string{"bing", "brave"}})
+	var unsupported *UnsupportedVerticalError
+	if !errors.As(err, &unsupported) || !errors.Is(err, ErrUnsupportedVertical) || unsupported.Engines[0] != "brave" {
+		t.Errorf("expected an *UnsupportedVerticalError for brave, got %v", err)
+	}
+}
+
+func TestFallbackEngine_NoFallbackForVerticals(t *testing.T) {
+	primary := &mockSearchEngine{name: "bing", err: errors.New("feed unavailable")}
+	secondary := &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Web result", URL: "https://example.com"}}}
+
+	_, err := NewFallbackEngine(primary, secondary).(QueryEngine).SearchQuery(context.Background(), EngineQuery{Query: "golang", MaxResults: 10, Vertical: VerticalNews})
+	if err == nil {
+		t.Error("expected the news search to fail rather than return web results")
+	}
+	if secondary.calls != 0 {
+		t.Errorf("expected the browser engine not to be tried, got %d calls", secondary.calls)
+	}
+}
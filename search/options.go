@@ -0,0 +1,333 @@
+package search
+
+import (
+	"sort"
+	"time"
+
+	"github.com/liliang-cn/mcp-websearch-server/extraction"
+)
+
+// SearcherOption configures a multi-engine searcher
+type SearcherOption func(*searcherOptions)
+
+type searcherOptions struct {
+	quota *queryQuota
+	ttls  map[PageType]time.Duration
+
+	seed    int64
+	weights map[string]int
+
+	archiveFallback bool
+
+	translator *snippetTranslator
+
+	browserEscalation bool
+
+	maxQueryLength int
+
+	searxngURL string
+
+	httpExtraction bool
+
+	overshoot int
+
+	extractConcurrency int
+
+	engines   map[string]SearchEngine
+	extractor ContentExtractor
+
+	defaultTimeout time.Duration
+
+	priorityOrder []string
+
+	proxyURL string
+}
+
+// WithQueryQuota limits the searcher to n searches per rolling minute.
+// Searches over the limit fail with a *QuotaExceededError. Zero or a
+// negative n disables the limit.
+func WithQueryQuota(n int) SearcherOption {
+	return func(o *searcherOptions) {
+		o.quota = newQueryQuota(n, time.Minute)
+	}
+}
+
+// WithResultTTL overrides the suggested cache TTL reported for results of
+// the given page type
+func WithResultTTL(pageType PageType, ttl time.Duration) SearcherOption {
+	return func(o *searcherOptions) {
+		if o.ttls == nil {
+			o.ttls = make(map[PageType]time.Duration)
+		}
+		o.ttls[pageType] = ttl
+	}
+}
+
+// WithEngineWeights sets the relative weight of each engine for the random
+// strategy. Engines left out weigh 1; a weight of zero excludes the engine.
+func WithEngineWeights(weights map[string]int) SearcherOption {
+	return func(o *searcherOptions) {
+		o.weights = make(map[string]int, len(weights))
+		for name, w := range weights {
+			o.weights[normalizeEngineName(name)] = w
+		}
+	}
+}
+
+// WithRandomSeed seeds the random strategy's generator, making its engine
+// choices reproducible
+func WithRandomSeed(seed int64) SearcherOption {
+	return func(o *searcherOptions) {
+		o.seed = seed
+	}
+}
+
+// WithArchiveFallback makes extraction retry pages that fail or are refused
+// (e.g. paywalled) against their Wayback Machine snapshot
+func WithArchiveFallback(enabled bool) SearcherOption {
+	return func(o *searcherOptions) {
+		o.archiveFallback = enabled
+	}
+}
+
+// archiveBase returns the archive URL prefix, or "" when the fallback is
+// disabled
+func (o searcherOptions) archiveBase() string {
+	if o.archiveFallback {
+		return defaultArchiveBase
+	}
+	return ""
+}
+
+// WithSnippetTranslator translates result snippets into targetLang for
+// display. Titles, URLs and extracted content are left untouched.
+func WithSnippetTranslator(t Translator, targetLang string) SearcherOption {
+	return func(o *searcherOptions) {
+		o.translator = &snippetTranslator{translator: t, targetLang: targetLang}
+	}
+}
+
+// WithBrowserEscalation makes the hybrid searcher rerun a query on the
+// heavier chromedp-based engines when its goquery engines find nothing,
+// which usually means they are being blocked
+func WithBrowserEscalation(enabled bool) SearcherOption {
+	return func(o *searcherOptions) {
+		o.browserEscalation = enabled
+	}
+}
+
+// WithMaxQueryLength sets the longest query, in characters, the searcher
+// accepts; longer queries fail with a *QueryTooLongError. The default is
+// DefaultMaxQueryLength and a negative n disables the check.
+func WithMaxQueryLength(n int) SearcherOption {
+	return func(o *searcherOptions) {
+		o.maxQueryLength = n
+	}
+}
+
+// WithSearXNG registers a SearXNG engine, under the name "searxng", that
+// searches through the instance at instanceURL. It takes priority over the
+// built-in engines, so searches are routed through the instance unless it
+// fails. An empty instanceURL registers nothing.
+func WithSearXNG(instanceURL string) SearcherOption {
+	return func(o *searcherOptions) {
+		o.searxngURL = instanceURL
+	}
+}
+
+// WithHTTPExtraction makes NewMultiEngineSearcher extract content with
+// plain HTTP requests instead of a headless browser, so no Chrome is
+// needed. Pages that only render with JavaScript then fail to extract.
+func WithHTTPExtraction(enabled bool) SearcherOption {
+	return func(o *searcherOptions) {
+		o.httpExtraction = enabled
+	}
+}
+
+// WithFilterOvershoot sets how many times MaxResults are requested from the
+// engines when SearchOptions.Filters or RequireContent are set, so that
+// enough results are left after filtering. The default is DefaultFilterOvershoot; 1 disables
+// over-fetching.
+func WithFilterOvershoot(factor int) SearcherOption {
+	return func(o *searcherOptions) {
+		if factor > 0 {
+			o.overshoot = factor
+		}
+	}
+}
+
+// WithExtractConcurrency sets how many result pages the searcher extracts
+// at once, unless SearchOptions.ExtractConcurrency overrides it per search.
+// The default is 2 for the hybrid searcher, which opens a browser tab per
+// page, and 3 otherwise; values above MaxExtractConcurrency are capped.
+func WithExtractConcurrency(n int) SearcherOption {
+	return func(o *searcherOptions) {
+		if n > 0 {
+			o.extractConcurrency = n
+		}
+	}
+}
+
+// WithEngines replaces the searcher's built-in engines with engines, keyed
+// by name. Engines registered by other options, such as WithSearXNG, are
+// still added.
+func WithEngines(engines map[string]SearchEngine) SearcherOption {
+	return func(o *searcherOptions) {
+		o.engines = make(map[string]SearchEngine, len(engines))
+		for name, engine := range engines {
+			o.engines[normalizeEngineName(name)] = engine
+		}
+	}
+}
+
+// WithExtractor replaces the searcher's content extractor. The hybrid
+// searcher uses the extractor's ExtractPage, PageInfo, Warmup and Close
+// methods when it has them, as *extraction.HybridExtractor does, and only
+// its content otherwise.
+func WithExtractor(extractor ContentExtractor) SearcherOption {
+	return func(o *searcherOptions) {
+		o.extractor = extractor
+	}
+}
+
+// WithDefaultTimeout sets the timeout of searches that don't set
+// SearchOptions.Timeout. The default is 30 seconds for Search and 60 for
+// DeepSearch and SearchStream.
+func WithDefaultTimeout(d time.Duration) SearcherOption {
+	return func(o *searcherOptions) {
+		if d > 0 {
+			o.defaultTimeout = d
+		}
+	}
+}
+
+// WithSearchProxy sends the requests of the searcher's built-in engines,
+// its SearXNG engine, browser escalation and default content extractor
+// through the proxy at proxyURL, which may use the http, https or socks5
+// scheme. Engines and extractors given with WithEngines or WithExtractor
+// keep their own settings. An invalid URL makes every search and
+// extraction fail rather than connect directly.
+func WithSearchProxy(proxyURL string) SearcherOption {
+	return func(o *searcherOptions) {
+		o.proxyURL = proxyURL
+	}
+}
+
+// WithPriorityOrder sets the order, by name, in which engines are chosen
+// for a search, tried as fallbacks and queried by DeepSearch. Registered
+// engines left out follow in alphabetical order.
+func WithPriorityOrder(names []string) SearcherOption {
+	return func(o *searcherOptions) {
+		o.priorityOrder = make([]string, len(names))
+		for i, name := range names {
+			o.priorityOrder[i] = normalizeEngineName(name)
+		}
+	}
+}
+
+// engineSet returns the engines configured by WithEngines, or defaults
+// when there are none, with the optionally configured engines added
+func (o searcherOptions) engineSet(defaults func() map[string]SearchEngine) map[string]SearchEngine {
+	engines := make(map[string]SearchEngine)
+	if o.engines != nil {
+		for name, engine := range o.engines {
+			engines[name] = engine
+		}
+	} else {
+		engines = defaults()
+	}
+	o.registerEngines(engines)
+	return engines
+}
+
+// enginePriority returns the priority order over engines: the
+// WithPriorityOrder order, or defaults when it isn't set, followed by the
+// registered engines it leaves out in alphabetical order
+func (o searcherOptions) enginePriority(engines map[string]SearchEngine, defaults []string) []string {
+	order := defaults
+	if o.priorityOrder != nil {
+		order = o.priorityOrder
+	}
+
+	listed := make(map[string]bool, len(order))
+	for _, name := range order {
+		listed[name] = true
+	}
+	var rest []string
+	for name := range engines {
+		if !listed[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(append([]string(nil), order...), rest...)
+}
+
+// searchTimeout returns the WithDefaultTimeout setting configured, or
+// fallback when it is unset
+func searchTimeout(configured, fallback time.Duration) time.Duration {
+	if configured > 0 {
+		return configured
+	}
+	return fallback
+}
+
+// registerEngines adds the optionally configured engines to engines
+func (o searcherOptions) registerEngines(engines map[string]SearchEngine) {
+	if o.searxngURL != "" {
+		engines["searxng"] = NewSearXNGEngine(o.searxngURL, o.engineOptions()...)
+	}
+}
+
+// engineOptions returns the options the searcher's own HTTP engines are
+// created with, routing them through the WithSearchProxy proxy if set
+func (o searcherOptions) engineOptions() []EngineOption {
+	if o.proxyURL == "" {
+		return nil
+	}
+	return []EngineOption{WithProxy(o.proxyURL)}
+}
+
+// browserEngineOptions is engineOptions for the chromedp-based engines
+func (o searcherOptions) browserEngineOptions() []BrowserEngineOption {
+	if o.proxyURL == "" {
+		return nil
+	}
+	return []BrowserEngineOption{WithBrowserProxy(o.proxyURL)}
+}
+
+// hybridOptions, chromedpOptions and httpOptions are engineOptions for the
+// default content extractors
+func (o searcherOptions) hybridOptions() []extraction.HybridOption {
+	if o.proxyURL == "" {
+		return nil
+	}
+	return []extraction.HybridOption{extraction.WithProxyServer(o.proxyURL)}
+}
+
+func (o searcherOptions) chromedpOptions() []extraction.ChromedpOption {
+	if o.proxyURL == "" {
+		return nil
+	}
+	return []extraction.ChromedpOption{extraction.WithChromedpProxyServer(o.proxyURL)}
+}
+
+func (o searcherOptions) httpOptions() []extraction.HTTPOption {
+	if o.proxyURL == "" {
+		return nil
+	}
+	return []extraction.HTTPOption{extraction.WithHTTPProxy(o.proxyURL)}
+}
+
+func applySearcherOptions(opts []SearcherOption) searcherOptions {
+	o := searcherOptions{
+		quota:     newQueryQuota(0, time.Minute),
+		seed:      time.Now().UnixNano(),
+		overshoot: DefaultFilterOvershoot,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,99 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewHybridSearcher_WithEnginesAndExtractor(t *testing.T) {
+	alpha := &mockSearchEngine{name: "alpha", results: []SearchResult{{Title: "Alpha", URL: "https://alpha.example", Engine: "alpha"}}}
+	bing := &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Bing", URL: "https://bing.example", Engine: "bing"}}}
+
+	searcher := NewHybridSearcher(
+		WithEngines(map[string]SearchEngine{"Alpha": alpha, "bing": bing}),
+		WithExtractor(&mockContentExtractor{content: "Page text."}),
+		WithPriorityOrder([]string{"alpha", "bing"}),
+	)
+	h := searcher.(*HybridMultiEngineSearcher)
+	if len(h.engines) != 2 {
+		t.Fatalf("expected only the given engines, got %d", len(h.engines))
+	}
+
+	results, err := searcher.Search(context.Background(), "query", SearchOptions{MaxResults: 5, ExtractContent: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Engine != "alpha" {
+		t.Fatalf("expected the first engine in the priority order to be used, got %v", results)
+	}
+	if results[0].Content != "Page text." {
+		t.Errorf("expected content from the given extractor, got %q", results[0].Content)
+	}
+	if bing.calls != 0 {
+		t.Errorf("expected bing not to be queried, got %d calls", bing.calls)
+	}
+	if err := searcher.(interface{ Close() error }).Close(); err != nil {
+		t.Errorf("unexpected error closing: %v", err)
+	}
+}
+
+func TestWithPriorityOrder_Fallback(t *testing.T) {
+	failing := &mockSearchEngine{name: "bing", err: errors.New("blocked")}
+	second := &mockSearchEngine{name: "yandex", results: []SearchResult{{Title: "Yandex", URL: "https://yandex.example", Engine: "yandex"}}}
+	last := &mockSearchEngine{name: "brave", results: []SearchResult{{Title: "Brave", URL: "https://brave.example", Engine: "brave"}}}
+
+	searcher := NewSearcherWithEngines(
+		map[string]SearchEngine{"bing": failing, "yandex": second, "brave": last},
+		nil,
+		WithPriorityOrder([]string{"bing", "yandex"}),
+	)
+
+	results, err := searcher.Search(context.Background(), "query", SearchOptions{MaxResults: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Engine != "yandex" {
+		t.Errorf("expected the fallback to follow the priority order, got %v", results)
+	}
+	if last.calls != 0 {
+		t.Errorf("expected brave, which was left out of the order, to come last, got %d calls", last.calls)
+	}
+}
+
+func TestEnginePriority(t *testing.T) {
+	engines := map[string]SearchEngine{"bing": nil, "zeta": nil, "alpha": nil}
+
+	o := applySearcherOptions(nil)
+	got := o.enginePriority(engines, []string{"searxng", "bing"})
+	if want := []string{"searxng", "bing", "alpha", "zeta"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	o = applySearcherOptions([]SearcherOption{WithPriorityOrder([]string{"Zeta"})})
+	got = o.enginePriority(engines, []string{"searxng", "bing"})
+	if want := []string{"zeta", "alpha", "bing"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestWithDefaultTimeout(t *testing.T) {
+	slow := &slowEngine{
+		mockSearchEngine: mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Late", URL: "https://late.example"}}},
+		delay:            time.Minute,
+	}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": slow}, nil, WithDefaultTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	if _, err := searcher.Search(context.Background(), "query", SearchOptions{MaxResults: 5}); err == nil {
+		t.Fatal("expected the search to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the default timeout to apply, took %v", elapsed)
+	}
+	if _, err := searcher.DeepSearch(context.Background(), "query", SearchOptions{MaxResults: 5}); err == nil {
+		t.Error("expected the deep search to time out")
+	}
+}
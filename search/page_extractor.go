@@ -0,0 +1,75 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/liliang-cn/mcp-websearch-server/extraction"
+)
+
+// pageExtractor is what the hybrid searcher extracts pages with, as
+// implemented by *extraction.HybridExtractor
+type pageExtractor interface {
+	ExtractPage(ctx context.Context, url string) (*extraction.Page, error)
+	PageInfo(ctx context.Context, url string) (*extraction.PageInfo, error)
+	Warmup(ctx context.Context) error
+	Close() error
+}
+
+// pageReader is implemented by extractors that report a page's status,
+// final URL and Last-Modified header along with its content, such as
+// *extraction.ChromedpExtractor and *extraction.HTTPExtractor
+type pageReader interface {
+	ExtractPage(ctx context.Context, url string) (*extraction.Page, error)
+}
+
+// newPageExtractor returns extractor as a pageExtractor, adapting it if it
+// only extracts content, or a new hybrid extractor created with opts when
+// it is nil
+func newPageExtractor(extractor ContentExtractor, opts ...extraction.HybridOption) pageExtractor {
+	if extractor == nil {
+		return extraction.NewHybridExtractor(opts...)
+	}
+	if pe, ok := extractor.(pageExtractor); ok {
+		return pe
+	}
+	return contentPageExtractor{extractor}
+}
+
+// contentPageExtractor adapts a ContentExtractor to pageExtractor. Pages
+// have only their URL and content, PageInfo works if the extractor is a
+// pageInfoFetcher, and Close closes it if it is an io.Closer.
+type contentPageExtractor struct {
+	ContentExtractor
+}
+
+func (c contentPageExtractor) ExtractPage(ctx context.Context, url string) (*extraction.Page, error) {
+	content, err := c.ExtractContent(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return &extraction.Page{
+		URL:         url,
+		Content:     content,
+		ContentHash: extraction.ContentHash(content),
+	}, nil
+}
+
+func (c contentPageExtractor) PageInfo(ctx context.Context, url string) (*extraction.PageInfo, error) {
+	if fetcher, ok := c.ContentExtractor.(pageInfoFetcher); ok {
+		return fetcher.PageInfo(ctx, url)
+	}
+	return nil, fmt.Errorf("page info not supported by %T", c.ContentExtractor)
+}
+
+func (c contentPageExtractor) Warmup(ctx context.Context) error {
+	return nil
+}
+
+func (c contentPageExtractor) Close() error {
+	if closer, ok := c.ContentExtractor.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
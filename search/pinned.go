@@ -0,0 +1,53 @@
+package search
+
+// pinResults returns results with those matching pinned moved to the front
+// in pinned order. The remaining results keep their order.
+func pinResults(results []SearchResult, pinned []string) []SearchResult {
+	if len(pinned) == 0 {
+		return results
+	}
+
+	used := make([]bool, len(results))
+	ordered := make([]SearchResult, 0, len(results))
+	for _, p := range pinned {
+		for i, r := range results {
+			if !used[i] && resultMatchesURL(r, p) {
+				used[i] = true
+				ordered = append(ordered, r)
+				break
+			}
+		}
+	}
+
+	for i, r := range results {
+		if !used[i] {
+			ordered = append(ordered, r)
+		}
+	}
+	return ordered
+}
+
+// missingURLs returns the pinned URLs that no result matches
+func missingURLs(results []SearchResult, pinned []string) []string {
+	var missing []string
+	for _, p := range pinned {
+		found := false
+		for _, r := range results {
+			if resultMatchesURL(r, p) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
+
+// resultMatchesURL reports whether r's URL or post-redirect URL is u once
+// both are normalized
+func resultMatchesURL(r SearchResult, u string) bool {
+	key := normalizeURL(u)
+	return normalizeURL(r.URL) == key || (r.FinalURL != "" && normalizeURL(r.FinalURL) == key)
+}
@@ -0,0 +1,36 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPinResults(t *testing.T) {
+	results := []SearchResult{
+		{Title: "A", URL: "https://a.com/"},
+		{Title: "B", URL: "https://b.com/post"},
+		{Title: "C", URL: "https://c.com/old", FinalURL: "https://c.com/new"},
+		{Title: "D", URL: "https://d.com"},
+	}
+
+	pinned := []string{"https://C.com/new", "https://missing.com", "https://b.com/post/"}
+	ordered := pinResults(results, pinned)
+
+	var titles []string
+	for _, r := range ordered {
+		titles = append(titles, r.Title)
+	}
+	if got := strings.Join(titles, ","); got != "C,B,A,D" {
+		t.Fatalf("expected pinned results to lead in pinned order, got %s", got)
+	}
+
+	aggregated := formatAggregate("query", ordered)
+	if !strings.Contains(aggregated, "## 1. C") || !strings.Contains(aggregated, "## 2. B") {
+		t.Errorf("expected pinned results first in the aggregation:\n%s", aggregated)
+	}
+
+	missing := missingURLs(results, pinned)
+	if len(missing) != 1 || missing[0] != "https://missing.com" {
+		t.Errorf("expected only the unmatched pin to be missing, got %v", missing)
+	}
+}
@@ -0,0 +1,338 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// engineRouter picks the engines a searcher sends a query to
+type engineRouter interface {
+	selectEngine(preferred []string) SearchEngine
+	getEngines(names []string) []SearchEngine
+	otherEngines(results []SearchResult) []SearchEngine
+	fallbackSearch(ctx context.Context, q EngineQuery, failedEngine string) ([]SearchResult, error)
+	priorityOrder() []string
+}
+
+// searchPipeline is the searcher state Search, DeepSearchDetailed and
+// SearchStream run with. Both searchers share it and differ only in how a
+// result's content is extracted and whether browser engines are escalated
+// to.
+type searchPipeline struct {
+	router  engineRouter
+	engines map[string]SearchEngine
+	quota   *queryQuota
+	picker  *enginePicker
+	// maxQueryLength limits query length; zero means
+	// DefaultMaxQueryLength and negative disables the limit
+	maxQueryLength int
+	// overshoot is the searcher's filter overshoot factor
+	overshoot  int
+	ttls       map[PageType]time.Duration
+	translator *snippetTranslator
+	stats      *searchStats
+	// defaultTimeout is the WithDefaultTimeout setting, zero for the
+	// default
+	defaultTimeout time.Duration
+
+	// extract fills in a result's content
+	extract func(ctx context.Context, r *SearchResult)
+	// extractConcurrency is the WithExtractConcurrency setting, and
+	// defaultConcurrency applies when neither it nor the search sets one
+	extractConcurrency int
+	defaultConcurrency int
+	// escalation holds the browser engines tried as a last resort when
+	// the other engines return nothing; empty disables escalation
+	escalation []SearchEngine
+	// alwaysExtract makes DeepSearch extract content even when
+	// opts.ExtractContent is not set
+	alwaysExtract bool
+}
+
+// prepare sanitizes query and checks it and opts before a search, counting
+// it against the quota
+func (p searchPipeline) prepare(query string, opts *SearchOptions) (string, error) {
+	query, err := sanitizeQuery(query, p.maxQueryLength)
+	if err != nil {
+		return "", err
+	}
+
+	if err := p.quota.acquire(); err != nil {
+		return "", err
+	}
+
+	if err := checkEngineNames(p.engines, opts.Engines); err != nil {
+		return "", err
+	}
+
+	if err := checkSafeSearch(opts.SafeSearch); err != nil {
+		return "", err
+	}
+
+	if err := checkExtractConcurrency(opts.ExtractConcurrency); err != nil {
+		return "", err
+	}
+
+	if opts.RequireContent {
+		opts.ExtractContent = true
+	}
+	return query, nil
+}
+
+// search queries a single engine, falling back to the others when it fails
+func (p searchPipeline) search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	query, err := p.prepare(query, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Timeout == 0 {
+		opts.Timeout = searchTimeout(p.defaultTimeout, 30*time.Second)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	engine := p.router.selectEngine(opts.Engines)
+	if opts.Strategy == StrategyRandom {
+		engine = p.picker.pick(p.router.getEngines(opts.Engines))
+	}
+	if engine == nil {
+		return nil, fmt.Errorf("no search engine available")
+	}
+
+	// Over-fetch when filters will drop some of the results
+	q := engineQuery(query, opts)
+	q.MaxResults = overshootResults(opts.MaxResults, opts, p.overshoot)
+
+	results, err := p.stats.search(ctx, engine, q)
+	if err != nil && recoverable(err) && ctx.Err() == nil {
+		p.stats.fallback(engine.Name())
+		results, err = p.router.fallbackSearch(ctx, q, engine.Name())
+	}
+
+	// As a last resort, rerun the query on the browser engines
+	if len(results) == 0 && len(p.escalation) > 0 && recoverable(err) {
+		if escalated, escErr := escalateSearch(ctx, p.escalation, engineQuery(query, opts)); escErr == nil {
+			results, err = escalated, nil
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("all search engines failed: %w", err)
+	}
+
+	// Drop unwanted domains so a top-up counts only the results kept
+	results = filterDomains(results, opts)
+
+	// Top up from the remaining engines if we're short of MinResults
+	if len(results) < opts.MinResults {
+		results = filterDomains(topUpResults(ctx, p.stats, p.router.otherEngines(results), engineQuery(query, opts), results, max(opts.MinResults, opts.MaxResults)), opts)
+		if err := checkMinResults(results, opts.MinResults); err != nil {
+			return results, err
+		}
+	}
+
+	results, err = filterResults(results, opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+	// Spare results stand in for failed extractions when content is required
+	if !opts.RequireContent {
+		results = capResults(results, opts.MaxResults)
+	}
+
+	// Spread same-domain results out if requested
+	results = diversifyDomains(results, opts.MaxConsecutiveSameDomain)
+
+	if opts.ExtractContent && len(results) > 0 {
+		p.extractAll(ctx, results, opts)
+	}
+
+	if opts.RequireContent {
+		results = capResults(withContent(results), opts.MaxResults)
+	}
+
+	p.annotate(ctx, query, opts, results, engineAgreement(results))
+
+	// Filters and RequireContent may have dropped results since the
+	// top-up, so MinResults is checked against the final set
+	return results, checkMinResults(results, opts.MinResults)
+}
+
+// deepSearch queries the engines concurrently and merges their results,
+// returning each engine's raw results alongside, keyed by engine name
+func (p searchPipeline) deepSearch(ctx context.Context, query string, opts SearchOptions) (map[string][]SearchResult, []SearchResult, error) {
+	query, err := p.prepare(query, &opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.Timeout == 0 {
+		opts.Timeout = searchTimeout(p.defaultTimeout, 60*time.Second)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+
+	engines := p.router.getEngines(opts.Engines)
+	if len(engines) == 0 {
+		return nil, nil, fmt.Errorf("no search engines available")
+	}
+
+	if opts.MaxEngines > 0 && len(engines) > opts.MaxEngines {
+		engines = engines[:opts.MaxEngines]
+	}
+
+	perEngine := make([][]SearchResult, len(engines))
+	resultsPerEngine := opts.MaxResults / len(engines)
+	if resultsPerEngine < 1 {
+		resultsPerEngine = 1
+	}
+	resultsPerEngine = overshootResults(resultsPerEngine, opts, p.overshoot)
+
+	// Each engine gets its share of the results and of the offset
+	q := engineQuery(query, opts)
+	q.MaxResults = resultsPerEngine
+	q.Offset = opts.Offset / len(engines)
+
+	for i, engine := range engines {
+		wg.Add(1)
+		go func(idx int, eng SearchEngine) {
+			defer wg.Done()
+
+			results, err := p.stats.search(ctx, eng, q)
+			if err != nil {
+				log.Printf("Engine %s failed: %v", eng.Name(), err)
+				return
+			}
+
+			perEngine[idx] = results
+		}(i, engine)
+	}
+
+	wg.Wait()
+
+	raw := make(map[string][]SearchResult, len(engines))
+	for i, engine := range engines {
+		raw[engine.Name()] = perEngine[i]
+	}
+
+	// Merge the same page returned by several engines
+	allResults := dedupeResults(interleaveResults(perEngine, opts.CollapseDuplicateTitles))
+
+	if len(allResults) == 0 && len(p.escalation) > 0 {
+		allResults, _ = escalateSearch(ctx, p.escalation, engineQuery(query, opts))
+	}
+
+	if len(allResults) == 0 {
+		return raw, nil, fmt.Errorf("no results from any search engine")
+	}
+
+	allResults = filterDomains(allResults, opts)
+	allResults, err = filterResults(allResults, opts.Filters)
+	if err != nil {
+		return raw, nil, err
+	}
+
+	if err := checkMinResults(allResults, opts.MinResults); err != nil {
+		return raw, allResults, err
+	}
+
+	// Count engine agreement before ranking merges duplicate URLs
+	agreement := engineAgreement(allResults)
+
+	// Rank and cap before extracting so only the kept results are fetched
+	if opts.Rank {
+		allResults = rankWithConsensus(query, allResults, p.router.priorityOrder())
+	}
+	if !opts.RequireContent {
+		allResults = capResults(allResults, opts.MaxResults)
+	}
+
+	if opts.ExtractContent || p.alwaysExtract {
+		p.extractAll(ctx, allResults, opts)
+	}
+
+	if opts.RequireContent {
+		allResults = withContent(allResults)
+	}
+
+	// Limit final results
+	if len(allResults) > opts.MaxResults {
+		allResults = allResults[:opts.MaxResults]
+	}
+
+	p.annotate(ctx, query, opts, allResults, agreement)
+	return raw, allResults, checkMinResults(allResults, opts.MinResults)
+}
+
+// stream runs a SearchStream through streamResults
+func (p searchPipeline) stream(ctx context.Context, query string, opts SearchOptions) (<-chan SearchResult, <-chan error) {
+	query, err := p.prepare(query, &opts)
+	if err != nil {
+		return failedStream(err)
+	}
+
+	return streamResults(ctx, query, opts, streamConfig{
+		engines:     p.router.getEngines(opts.Engines),
+		overshoot:   p.overshoot,
+		concurrency: extractConcurrency(opts.ExtractConcurrency, p.extractConcurrency, p.defaultConcurrency),
+		extract:     p.extract,
+		stats:       p.stats,
+		ttls:        p.ttls,
+		translator:  p.translator,
+		timeout:     searchTimeout(p.defaultTimeout, 60*time.Second),
+	})
+}
+
+// extractAll extracts each result's content, at most the search's
+// extraction concurrency at once
+func (p searchPipeline) extractAll(ctx context.Context, results []SearchResult, opts SearchOptions) {
+	extractResults(ctx, results, extractConcurrency(opts.ExtractConcurrency, p.extractConcurrency, p.defaultConcurrency), p.extract)
+}
+
+// annotate adds the summaries, redaction, translations, freshness, source
+// categories, intent and confidence scores the final results carry
+func (p searchPipeline) annotate(ctx context.Context, query string, opts SearchOptions, results []SearchResult, agreement map[string]int) {
+	if opts.Summarize {
+		summarizeResults(results, query)
+	}
+
+	// Redact first so PII never reaches the translator
+	if opts.RedactPII {
+		redactResults(results)
+	}
+
+	p.translator.translate(ctx, results, opts.ReplaceTranslatedSnippets)
+
+	annotateFreshness(results, p.ttls, time.Now())
+	categorizeSources(results)
+	setQueryIntent(results, classifyIntent(query))
+	scoreConfidence(query, results, agreement)
+}
+
+// extractResults runs extract on each result, at most concurrency at once
+func extractResults(ctx context.Context, results []SearchResult, concurrency int, extract func(ctx context.Context, r *SearchResult)) {
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+
+	for i := range results {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			extract(ctx, &results[idx])
+		}(i)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,125 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithProxy(t *testing.T) {
+	engine := NewMojeekGoQueryEngine(WithProxy("socks5://127.0.0.1:1080")).(*mojeekGoQueryEngine)
+
+	transport, ok := engine.client.Transport.(*http.Transport)
+	if !ok || transport.Proxy == nil {
+		t.Fatalf("expected a proxied transport, got %#v", engine.client.Transport)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://www.mojeek.com/search?q=go", nil)
+	proxy, err := transport.Proxy(req)
+	if err != nil || proxy == nil || proxy.String() != "socks5://127.0.0.1:1080" {
+		t.Errorf("expected requests to go through socks5://127.0.0.1:1080, got %v (err %v)", proxy, err)
+	}
+}
+
+func TestNewGoQueryEngine(t *testing.T) {
+	if _, err := NewGoQueryEngine("bing", WithProxy("ftp://proxy:21")); err == nil {
+		t.Error("expected an invalid proxy to fail construction")
+	}
+
+	engine, err := NewGoQueryEngine("ddg", WithProxy("socks5://127.0.0.1:1080"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if engine.Name() != "duckduckgo" {
+		t.Errorf("expected the duckduckgo engine, got %s", engine.Name())
+	}
+
+	if _, err := NewGoQueryEngine("altavista"); !errors.Is(err, ErrUnknownEngine) {
+		t.Errorf("expected ErrUnknownEngine, got %v", err)
+	}
+}
+
+func TestWithProxy_Invalid(t *testing.T) {
+	if err := ValidateProxyURL("ftp://proxy:21"); err == nil {
+		t.Error("expected ValidateProxyURL to reject an ftp proxy")
+	}
+	if err := ValidateProxyURL("http://proxy:8080"); err != nil {
+		t.Errorf("expected ValidateProxyURL to accept an http proxy, got %v", err)
+	}
+
+	engine := NewBingGoQueryEngine(WithProxy("ftp://proxy:21"))
+	transport := &fixtureTransport{body: "<html><body></body></html>"}
+	engine.(*bingGoQueryEngine).client.Transport = transport
+
+	if _, err := engine.Search(context.Background(), "golang", 10); err == nil {
+		t.Fatal("expected an invalid proxy to fail the search")
+	}
+	if len(transport.urls) != 0 {
+		t.Errorf("expected no request without the proxy, got %v", transport.urls)
+	}
+}
+
+func TestWithBrowserProxy(t *testing.T) {
+	engine := NewBingSearchEngine(WithBrowserProxy("socks5://127.0.0.1:1080")).(*bingSearchEngine)
+	if engine.config.proxyServer != "socks5://127.0.0.1:1080" || engine.config.err != nil {
+		t.Errorf("expected the browser to be launched behind the proxy, got %+v", engine.config)
+	}
+
+	// An invalid proxy fails before a browser is launched
+	invalid := NewDuckDuckGoSearchEngine(WithBrowserProxy("ftp://proxy:21"))
+	if _, err := invalid.Search(context.Background(), "golang", 10); err == nil {
+		t.Error("expected an invalid proxy to fail the search")
+	}
+}
+
+func TestWithSearchProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.Method+" "+r.URL.String())
+	}))
+	defer proxy.Close()
+
+	h := NewHybridSearcher(WithSearchProxy(proxy.URL), WithBrowserEscalation(true), WithSearXNG("https://searx.example")).(*HybridMultiEngineSearcher)
+
+	clients := map[string]*http.Client{
+		"mojeek":  h.engines["mojeek"].(*mojeekGoQueryEngine).client,
+		"searxng": h.engines["searxng"].(*searxngEngine).client,
+	}
+	for name, client := range clients {
+		if transport, ok := client.Transport.(*http.Transport); !ok || transport.Proxy == nil {
+			t.Errorf("%s: expected a proxied transport, got %#v", name, client.Transport)
+		}
+	}
+
+	for _, engine := range h.escalation {
+		var config browserEngineConfig
+		switch e := engine.(type) {
+		case *duckDuckGoSearchEngine:
+			config = e.config
+		case *bingSearchEngine:
+			config = e.config
+		case *braveSearchEngine:
+			config = e.config
+		}
+		if config.proxyServer != proxy.URL {
+			t.Errorf("%s: expected escalation to go through the proxy, got %q", engine.Name(), config.proxyServer)
+		}
+	}
+
+	// The default extractors send their requests through it too
+	basic := NewMultiEngineSearcher(WithSearchProxy(proxy.URL), WithHTTPExtraction(true)).(*multiEngineSearcher)
+	extractors := map[string]pageInfoFetcher{
+		"hybrid": h.extractor,
+		"http":   basic.extractor.(pageInfoFetcher),
+	}
+	for name, extractor := range extractors {
+		proxied = nil
+		if _, err := extractor.PageInfo(context.Background(), "http://pages.example/post"); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if len(proxied) != 1 || proxied[0] != "HEAD http://pages.example/post" {
+			t.Errorf("%s: expected the HEAD request to go through the proxy, got %v", name, proxied)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxQueryLength is the longest query, in characters, a searcher
+// accepts unless configured otherwise with WithMaxQueryLength
+const DefaultMaxQueryLength = 500
+
+// sanitizeQuery turns tabs and line breaks into spaces, drops other control
+// characters and collapses runs of whitespace. It returns a
+// *QueryTooLongError when the cleaned query is longer than maxLength
+// characters, where zero means DefaultMaxQueryLength and a negative value
+// disables the check, and ErrEmptyQuery when nothing is left.
+func sanitizeQuery(query string, maxLength int) (string, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r), r == utf8.RuneError:
+			return -1
+		}
+		return r
+	}, query)
+	cleaned = strings.Join(strings.Fields(cleaned), " ")
+
+	if cleaned == "" {
+		return "", ErrEmptyQuery
+	}
+
+	if maxLength == 0 {
+		maxLength = DefaultMaxQueryLength
+	}
+	if length := utf8.RuneCountInString(cleaned); maxLength > 0 && length > maxLength {
+		return "", &QueryTooLongError{Length: length, Max: maxLength}
+	}
+
+	return cleaned, nil
+}
@@ -0,0 +1,70 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSearch_QueryTooLong(t *testing.T) {
+	engine := &mockSearchEngine{name: "bing", results: []SearchResult{{URL: "http://a.com"}}}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": engine}, nil)
+
+	query := strings.Repeat("a", DefaultMaxQueryLength+1)
+	for _, search := range []func(context.Context, string, SearchOptions) ([]SearchResult, error){searcher.Search, searcher.DeepSearch} {
+		_, err := search(context.Background(), query, SearchOptions{MaxResults: 1})
+
+		var tooLong *QueryTooLongError
+		if !errors.As(err, &tooLong) || !errors.Is(err, ErrQueryTooLong) {
+			t.Fatalf("expected a *QueryTooLongError, got %v", err)
+		}
+		if tooLong.Length != DefaultMaxQueryLength+1 || tooLong.Max != DefaultMaxQueryLength {
+			t.Errorf("unexpected error details: %+v", tooLong)
+		}
+	}
+
+	if calls := atomic.LoadInt32(&engine.calls); calls != 0 {
+		t.Errorf("expected no engine requests, got %d", calls)
+	}
+
+	limited := NewSearcherWithEngines(map[string]SearchEngine{"bing": engine}, nil, WithMaxQueryLength(10))
+	if _, err := limited.Search(context.Background(), "eleven char", SearchOptions{MaxResults: 1}); !errors.Is(err, ErrQueryTooLong) {
+		t.Errorf("expected the configured limit to apply, got %v", err)
+	}
+
+	unlimited := NewSearcherWithEngines(map[string]SearchEngine{"bing": engine}, nil, WithMaxQueryLength(-1))
+	if _, err := unlimited.Search(context.Background(), query, SearchOptions{MaxResults: 1}); err != nil {
+		t.Errorf("expected no limit with a negative length, got %v", err)
+	}
+}
+
+func TestSearch_ControlCharacters(t *testing.T) {
+	var got string
+	engine := &queryRecordingEngine{query: &got}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": engine}, nil)
+
+	if _, err := searcher.Search(context.Background(), "\x00golang\tgenerics\r\n\x1b[31mtutorial\x7f ", SearchOptions{MaxResults: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "golang generics [31mtutorial" {
+		t.Errorf("expected control characters to be removed, engine got %q", got)
+	}
+
+	if _, err := searcher.Search(context.Background(), "\x00\x01\n\t", SearchOptions{MaxResults: 1}); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("expected ErrEmptyQuery for a query of only control characters, got %v", err)
+	}
+}
+
+// queryRecordingEngine records the query it was asked to search for
+type queryRecordingEngine struct {
+	query *string
+}
+
+func (e *queryRecordingEngine) Name() string { return "bing" }
+
+func (e *queryRecordingEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	*e.query = query
+	return []SearchResult{{Title: "Result", URL: "http://a.com", Engine: "bing"}}, nil
+}
@@ -0,0 +1,66 @@
+package search
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// queryQuota enforces a maximum number of searches within a rolling window
+// and counts every search attempted. It is safe for concurrent use.
+type queryQuota struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	stamps []time.Time
+	total  atomic.Int64
+	now    func() time.Time
+}
+
+func newQueryQuota(limit int, window time.Duration) *queryQuota {
+	return &queryQuota{
+		limit:  limit,
+		window: window,
+		now:    time.Now,
+	}
+}
+
+// acquire records a search, returning a *QuotaExceededError if the window is
+// already full. Rejected searches still count towards the total.
+func (q *queryQuota) acquire() error {
+	if q == nil {
+		return nil
+	}
+	q.total.Add(1)
+	if q.limit <= 0 {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	cutoff := now.Add(-q.window)
+	kept := q.stamps[:0]
+	for _, ts := range q.stamps {
+		if ts.After(cutoff) {
+			kept = append(kept, ts)
+		}
+	}
+	q.stamps = kept
+
+	if len(q.stamps) >= q.limit {
+		return &QuotaExceededError{RetryAfter: q.stamps[0].Add(q.window).Sub(now)}
+	}
+
+	q.stamps = append(q.stamps, now)
+	return nil
+}
+
+// count returns the number of searches attempted so far
+func (q *queryQuota) count() int64 {
+	if q == nil {
+		return 0
+	}
+	return q.total.Load()
+}
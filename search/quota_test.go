@@ -0,0 +1,105 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueryQuota_RollingWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	quota := newQueryQuota(2, time.Minute)
+	quota.now = func() time.Time { return now }
+
+	if err := quota.acquire(); err != nil {
+		t.Fatalf("first search should pass: %v", err)
+	}
+	now = now.Add(20 * time.Second)
+	if err := quota.acquire(); err != nil {
+		t.Fatalf("second search should pass: %v", err)
+	}
+
+	now = now.Add(10 * time.Second)
+	err := quota.acquire()
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+
+	var quotaErr *QuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("expected *QuotaExceededError, got %T", err)
+	}
+	if quotaErr.RetryAfter != 30*time.Second {
+		t.Errorf("expected retry hint of 30s, got %v", quotaErr.RetryAfter)
+	}
+
+	// Once the first search leaves the window there's room again
+	now = now.Add(31 * time.Second)
+	if err := quota.acquire(); err != nil {
+		t.Errorf("expected quota to recover after the window, got %v", err)
+	}
+
+	if quota.count() != 4 {
+		t.Errorf("expected 4 searches counted, got %d", quota.count())
+	}
+}
+
+func TestQueryQuota_Concurrent(t *testing.T) {
+	quota := newQueryQuota(10, time.Minute)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if quota.acquire() == nil {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != 10 {
+		t.Errorf("expected exactly 10 searches allowed, got %d", allowed)
+	}
+	if quota.count() != 50 {
+		t.Errorf("expected 50 searches counted, got %d", quota.count())
+	}
+}
+
+func TestMultiEngineSearcher_QuotaExceeded(t *testing.T) {
+	engine := &mockSearchEngine{
+		name:    "bing",
+		results: []SearchResult{{Title: "Result", URL: "http://example.com"}},
+	}
+
+	searcher := &multiEngineSearcher{
+		engines:   map[string]SearchEngine{"bing": engine},
+		extractor: &mockContentExtractor{},
+		quota:     newQueryQuota(1, time.Minute),
+	}
+
+	ctx := context.Background()
+	if _, err := searcher.Search(ctx, "test", SearchOptions{MaxResults: 1}); err != nil {
+		t.Fatalf("first search should pass: %v", err)
+	}
+
+	_, err := searcher.DeepSearch(ctx, "test", SearchOptions{MaxResults: 1})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", err)
+	}
+
+	if engine.calls != 1 {
+		t.Errorf("expected engine to be queried once, got %d", engine.calls)
+	}
+
+	if searcher.QueryCount() != 2 {
+		t.Errorf("expected 2 searches counted, got %d", searcher.QueryCount())
+	}
+}
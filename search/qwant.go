@@ -0,0 +1,212 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// qwantEngine queries Qwant through the JSON API behind its web interface,
+// which is more stable than scraping results pages
+type qwantEngine struct {
+	client *http.Client
+	config engineConfig
+}
+
+// qwantMaxCount is the most results the Qwant API returns per request
+const qwantMaxCount = 10
+
+// qwantResponse is the part of Qwant's JSON response the engine uses. The
+// mainline holds groups of results by type, of which only "web" are
+// organic.
+type qwantResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ErrorCode int `json:"error_code"`
+		Result    struct {
+			Items struct {
+				Mainline []struct {
+					Type  string `json:"type"`
+					Items []struct {
+						Title string `json:"title"`
+						URL   string `json:"url"`
+						Desc  string `json:"desc"`
+					} `json:"items"`
+				} `json:"mainline"`
+			} `json:"items"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// qwantSafeSearch maps levels to Qwant's safesearch parameter
+var qwantSafeSearch = map[SafeSearchLevel]string{
+	SafeSearchOff:      "0",
+	SafeSearchModerate: "1",
+	SafeSearchStrict:   "2",
+}
+
+func NewQwantEngine(opts ...EngineOption) SearchEngine {
+	config := newEngineConfig(Selectors{}, opts)
+	return &qwantEngine{
+		client: config.applyProxy(&http.Client{
+			Timeout: 10 * time.Second,
+		}),
+		config: config,
+	}
+}
+
+func (qw *qwantEngine) Name() string {
+	return "qwant"
+}
+
+func (qw *qwantEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	return qw.SearchQuery(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+// SearchQuery is Search with the full set of per-search parameters.
+// Language and Region select Qwant's locale; After and Before are ignored.
+// A 429 from the API is reported as a *RateLimitedError.
+func (qw *qwantEngine) SearchQuery(ctx context.Context, q EngineQuery) ([]SearchResult, error) {
+	if qw.config.err != nil {
+		return nil, qw.config.err
+	}
+
+	count := q.MaxResults
+	if count > qwantMaxCount || count < 1 {
+		count = qwantMaxCount
+	}
+
+	searchURL := fmt.Sprintf("https://api.qwant.com/v3/search/web?q=%s&count=%d&locale=%s&safesearch=%s",
+		url.QueryEscape(q.Query), count, qwantLocale(q), qwantSafeSearch[q.safeSearch()])
+	if q.Offset > 0 {
+		searchURL += fmt.Sprintf("&offset=%d", q.Offset)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", qw.config.userAgent())
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Language", q.acceptLanguage("en-US,en;q=0.5"))
+	req.Header.Set("Origin", "https://www.qwant.com")
+	req.Header.Set("Referer", "https://www.qwant.com/")
+
+	resp, err := qw.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Qwant results: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, &RateLimitedError{Engine: qw.Name(), RetryAfter: retryAfter(resp.Header.Get("Retry-After"))}
+	}
+
+	data, err := readLimitedBody(resp.Body, qw.config.maxBodySize, searchURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed qwantResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode Qwant response (status %d): %w", resp.StatusCode, err)
+	}
+	if parsed.Status != "success" {
+		return nil, fmt.Errorf("qwant returned status %d, error code %d", resp.StatusCode, parsed.Data.ErrorCode)
+	}
+
+	var results []SearchResult
+	for _, group := range parsed.Data.Result.Items.Mainline {
+		if group.Type != "web" {
+			continue
+		}
+		for _, item := range group.Items {
+			if len(results) >= q.MaxResults {
+				return results, nil
+			}
+			if item.URL == "" || item.Title == "" {
+				continue
+			}
+			results = append(results, SearchResult{
+				Title:   strings.TrimSpace(item.Title),
+				URL:     item.URL,
+				Snippet: strings.TrimSpace(item.Desc),
+				Engine:  qw.Name(),
+			})
+		}
+	}
+
+	return results, nil
+}
+
+// qwantDefaultLocales maps languages to the locale Qwant uses for them when
+// no region, or a region Qwant doesn't pair with the language, is given
+var qwantDefaultLocales = map[string]string{
+	"bg": "bg_BG",
+	"ca": "ca_ES",
+	"cs": "cs_CZ",
+	"cy": "cy_GB",
+	"da": "da_DK",
+	"de": "de_DE",
+	"el": "el_GR",
+	"en": "en_US",
+	"es": "es_ES",
+	"et": "et_EE",
+	"eu": "eu_ES",
+	"fi": "fi_FI",
+	"fr": "fr_FR",
+	"gd": "gd_GB",
+	"he": "he_IL",
+	"hu": "hu_HU",
+	"it": "it_IT",
+	"ja": "ja_JP",
+	"ko": "ko_KR",
+	"nb": "nb_NO",
+	"no": "nb_NO",
+	"nl": "nl_NL",
+	"pl": "pl_PL",
+	"pt": "pt_PT",
+	"ro": "ro_RO",
+	"sv": "sv_SE",
+	"th": "th_TH",
+	"zh": "zh_CN",
+}
+
+// qwantRegionalLocales are the other language and region pairs Qwant
+// accepts
+var qwantRegionalLocales = map[string]bool{
+	"ca_AD": true, "ca_FR": true,
+	"de_AT": true, "de_CH": true,
+	"en_AU": true, "en_CA": true, "en_GB": true, "en_IE": true, "en_MY": true, "en_NZ": true,
+	"es_AR": true, "es_CL": true, "es_CO": true, "es_MX": true, "es_PE": true,
+	"eu_FR": true,
+	"fr_AD": true, "fr_BE": true, "fr_CA": true, "fr_CH": true,
+	"it_CH": true,
+	"nl_BE": true,
+	"pt_BR": true,
+	"zh_HK": true, "zh_TW": true,
+}
+
+// qwantLocale returns Qwant's locale for q, such as "de_DE". Pairs Qwant
+// doesn't know fall back to the language's default locale, and languages
+// it doesn't know to "en_US". A region without a language is taken as
+// English in that region.
+func qwantLocale(q EngineQuery) string {
+	lang, region := strings.ToLower(q.Language), strings.ToUpper(q.Region)
+	if lang == "" {
+		lang = "en"
+	}
+	if region != "" {
+		if locale := lang + "_" + region; qwantRegionalLocales[locale] || qwantDefaultLocales[lang] == locale {
+			return locale
+		}
+	}
+	if locale, ok := qwantDefaultLocales[lang]; ok {
+		return locale
+	}
+	return "en_US"
+}
@@ -0,0 +1,104 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const qwantFixture = `{"status":"success","data":{"result":{"items":{"mainline":[
+	{"type":"ads","items":[{"title":"Sponsored","url":"https://ads.example","desc":"Ad"}]},
+	{"type":"web","items":[
+		{"title":"Go Programming Language","url":"https://go.dev/","desc":"Build simple, secure, scalable systems with Go."},
+		{"title":"","url":"https://untitled.example","desc":"Skipped"},
+		{"title":" A Tour of Go ","url":"https://go.dev/tour/","desc":" Learn Go interactively. "}
+	]},
+	{"type":"videos","items":[{"title":"Video","url":"https://video.example","desc":""}]},
+	{"type":"web","items":[{"title":"Effective Go","url":"https://go.dev/doc/effective_go","desc":"Tips."}]}
+]}}}}`
+
+func TestQwantEngine_SearchQuery(t *testing.T) {
+	engine := NewQwantEngine().(*qwantEngine)
+	transport := &fixtureTransport{body: qwantFixture}
+	engine.client.Transport = transport
+
+	results, err := engine.SearchQuery(context.Background(), EngineQuery{Query: "golang tour", MaxResults: 5, Offset: 10, Language: "de", SafeSearch: SafeSearchStrict})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 web results, got %d: %+v", len(results), results)
+	}
+	if results[0].URL != "https://go.dev/" || results[0].Engine != "qwant" {
+		t.Errorf("unexpected first result: %+v", results[0])
+	}
+	if results[1].Title != "A Tour of Go" || results[1].Snippet != "Learn Go interactively." {
+		t.Errorf("expected trimmed title and snippet, got %+v", results[1])
+	}
+
+	want := "https://api.qwant.com/v3/search/web?q=golang+tour&count=5&locale=de_DE&safesearch=2&offset=10"
+	if transport.urls[0] != want {
+		t.Errorf("expected URL %s, got %s", want, transport.urls[0])
+	}
+	if transport.headers[0].Get("Origin") != "https://www.qwant.com" {
+		t.Error("expected the Qwant origin header")
+	}
+
+	if results, _ := engine.Search(context.Background(), "golang", 1); len(results) != 1 {
+		t.Errorf("expected maxResults to cap the results, got %d", len(results))
+	}
+}
+
+func TestQwantLocale(t *testing.T) {
+	tests := []struct {
+		lang, region, want string
+	}{
+		{"", "", "en_US"},
+		{"en", "", "en_US"},
+		{"fr", "", "fr_FR"},
+		{"", "gb", "en_GB"},
+		{"DE", "at", "de_AT"},
+		{"ja", "", "ja_JP"},
+		{"zh", "", "zh_CN"},
+		{"zh", "tw", "zh_TW"},
+		{"ko", "", "ko_KR"},
+		{"sv", "", "sv_SE"},
+		{"de", "us", "de_DE"},
+		{"", "de", "en_US"},
+		{"xx", "", "en_US"},
+	}
+
+	for _, tt := range tests {
+		if got := qwantLocale(EngineQuery{Language: tt.lang, Region: tt.region}); got != tt.want {
+			t.Errorf("qwantLocale(%q, %q) = %q, want %q", tt.lang, tt.region, got, tt.want)
+		}
+	}
+}
+
+func TestQwantEngine_Errors(t *testing.T) {
+	engine := NewQwantEngine().(*qwantEngine)
+
+	engine.client.Transport = &responseTransport{status: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"30"}}}
+	_, err := engine.Search(context.Background(), "golang", 5)
+	var limited *RateLimitedError
+	if !errors.As(err, &limited) || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected a *RateLimitedError, got %v", err)
+	}
+	if limited.Engine != "qwant" || limited.RetryAfter != 30*time.Second {
+		t.Errorf("unexpected error details: %+v", limited)
+	}
+
+	engine.client.Transport = &responseTransport{status: http.StatusForbidden, body: `{"status":"error","data":{"error_code":27}}`}
+	if _, err := engine.Search(context.Background(), "golang", 5); err == nil || !strings.Contains(err.Error(), "error code 27") {
+		t.Errorf("expected the API error code to be reported, got %v", err)
+	}
+
+	engine.client.Transport = &responseTransport{status: http.StatusOK, body: "<html>not json</html>"}
+	if _, err := engine.Search(context.Background(), "golang", 5); err == nil {
+		t.Error("expected a decoding error for a non-JSON response")
+	}
+}
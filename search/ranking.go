@@ -0,0 +1,202 @@
+package search
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// RankOptions tunes how RankResultsWithOptions scores results
+type RankOptions struct {
+	// ExactPhrase treats double-quoted segments of the query as phrases that
+	// must appear verbatim in the title or snippet. Results containing a
+	// phrase are boosted heavily and results missing it are penalized,
+	// independently of the per-token overlap score.
+	ExactPhrase bool
+	// CaseSensitive makes exact-phrase matching case-sensitive
+	CaseSensitive bool
+	// EnginePriority lists engine names best first for the engine-priority
+	// signal; nil uses the hybrid searcher's default order
+	EnginePriority []string
+}
+
+const (
+	titleTermWeight   = 2.0
+	snippetTermWeight = 1.0
+	phraseMatchBoost  = 10.0
+	phraseMissPenalty = 5.0
+	// consensusWeight is added for each additional engine that returned
+	// the same URL
+	consensusWeight = 3.0
+	// enginePriorityWeight and snippetLengthWeight cap the two minor
+	// signals, which separate results with similar term scores rather than
+	// outweigh a query-term match
+	enginePriorityWeight = 1.0
+	snippetLengthWeight  = 1.0
+	// fullSnippetLength is the snippet length that earns the whole
+	// snippetLengthWeight
+	fullSnippetLength = 200
+)
+
+var quotedPhrasePattern = regexp.MustCompile(`"([^"]+)"`)
+
+// RankResults returns results sorted by relevance to query, most relevant
+// first. The score blends query-term matches in the title and snippet,
+// how many engines returned the result, the engine's priority and the
+// snippet's length. Ties keep their original order.
+func RankResults(query string, results []SearchResult) []SearchResult {
+	return RankResultsWithOptions(query, results, RankOptions{})
+}
+
+// RankResultsWithOptions is RankResults with tunable scoring
+func RankResultsWithOptions(query string, results []SearchResult, opts RankOptions) []SearchResult {
+	terms := queryTerms(query)
+	var phrases []string
+	if opts.ExactPhrase {
+		phrases = quotedPhrases(query)
+	}
+
+	priority := opts.EnginePriority
+	if priority == nil {
+		priority = hybridPriority
+	}
+
+	scores := make([]float64, len(results))
+	for i, r := range results {
+		scores[i] = resultScore(terms, r, len(resultEngines(r)), priority) + phraseScore(phrases, r, opts.CaseSensitive)
+	}
+
+	return sortByScore(results, scores)
+}
+
+// rankWithConsensus merges results that several engines returned for the
+// same URL and ranks them like RankResults, counting every engine that
+// returned the URL and scoring engines by their place in priority, the
+// searcher's priority order. The first occurrence of each URL is kept.
+func rankWithConsensus(query string, results []SearchResult, priority []string) []SearchResult {
+	var merged []SearchResult
+	engines := make(map[string]map[string]bool)
+	for _, r := range results {
+		if engines[r.URL] == nil {
+			engines[r.URL] = make(map[string]bool)
+			merged = append(merged, r)
+		}
+		for _, name := range resultEngines(r) {
+			engines[r.URL][name] = true
+		}
+	}
+
+	terms := queryTerms(query)
+	scores := make([]float64, len(merged))
+	for i, r := range merged {
+		scores[i] = resultScore(terms, r, len(engines[r.URL]), priority)
+	}
+
+	return sortByScore(merged, scores)
+}
+
+// resultScore is the relevance score of r, which engineCount engines
+// returned, with engines ranked by priority
+func resultScore(terms []string, r SearchResult, engineCount int, priority []string) float64 {
+	score := termScore(terms, r) + enginePriorityScore(r.Engine, priority) + snippetLengthScore(r.Snippet)
+	if engineCount > 1 {
+		score += consensusWeight * float64(engineCount-1)
+	}
+	return score
+}
+
+// enginePriorityScore scales enginePriorityWeight by the engine's place in
+// priority; engines not listed score nothing
+func enginePriorityScore(engine string, priority []string) float64 {
+	for i, name := range priority {
+		if name == engine {
+			return enginePriorityWeight * float64(len(priority)-i) / float64(len(priority))
+		}
+	}
+	return 0
+}
+
+// snippetLengthScore favors descriptive snippets, up to fullSnippetLength
+func snippetLengthScore(snippet string) float64 {
+	n := len(strings.TrimSpace(snippet))
+	if n >= fullSnippetLength {
+		return snippetLengthWeight
+	}
+	return snippetLengthWeight * float64(n) / fullSnippetLength
+}
+
+// sortByScore returns a copy of results ordered by descending score. Ties
+// keep their original order.
+func sortByScore(results []SearchResult, scores []float64) []SearchResult {
+	idx := make([]int, len(results))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return scores[idx[a]] > scores[idx[b]]
+	})
+
+	ranked := make([]SearchResult, len(results))
+	for i, j := range idx {
+		ranked[i] = results[j]
+	}
+	return ranked
+}
+
+// queryTerms returns the lowercased words of query with quotes removed
+func queryTerms(query string) []string {
+	var terms []string
+	for _, word := range strings.Fields(strings.ToLower(strings.ReplaceAll(query, `"`, " "))) {
+		word = strings.Trim(word, ".,;:!?()[]{}'")
+		if len(word) > 1 {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// quotedPhrases returns the double-quoted segments of query
+func quotedPhrases(query string) []string {
+	var phrases []string
+	for _, match := range quotedPhrasePattern.FindAllStringSubmatch(query, -1) {
+		if phrase := strings.TrimSpace(match[1]); phrase != "" {
+			phrases = append(phrases, phrase)
+		}
+	}
+	return phrases
+}
+
+// termScore counts query-term occurrences, weighting the title above the snippet
+func termScore(terms []string, r SearchResult) float64 {
+	title := strings.ToLower(r.Title)
+	snippet := strings.ToLower(r.Snippet)
+
+	var score float64
+	for _, term := range terms {
+		score += titleTermWeight * float64(strings.Count(title, term))
+		score += snippetTermWeight * float64(strings.Count(snippet, term))
+	}
+	return score
+}
+
+// phraseScore boosts results containing each phrase verbatim and penalizes
+// those that don't
+func phraseScore(phrases []string, r SearchResult, caseSensitive bool) float64 {
+	text := r.Title + "\n" + r.Snippet
+	if !caseSensitive {
+		text = strings.ToLower(text)
+	}
+
+	var score float64
+	for _, phrase := range phrases {
+		if !caseSensitive {
+			phrase = strings.ToLower(phrase)
+		}
+		if strings.Contains(text, phrase) {
+			score += phraseMatchBoost
+		} else {
+			score -= phraseMissPenalty
+		}
+	}
+	return score
+}
@@ -0,0 +1,143 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRankResults_TermOverlap(t *testing.T) {
+	results := []SearchResult{
+		{Title: "Cooking recipes", URL: "http://a.com", Snippet: "Nothing relevant here"},
+		{Title: "Go concurrency patterns", URL: "http://b.com", Snippet: "Goroutines and channels in Go concurrency"},
+	}
+
+	ranked := RankResults("go concurrency", results)
+
+	if ranked[0].URL != "http://b.com" {
+		t.Errorf("expected the matching result first, got %s", ranked[0].URL)
+	}
+	if results[0].URL != "http://a.com" {
+		t.Error("RankResults should not reorder the input slice")
+	}
+}
+
+func TestRankResults_ExactPhrase(t *testing.T) {
+	scattered := SearchResult{
+		Title:   "Machine tools and learning resources",
+		URL:     "http://scattered.com",
+		Snippet: "Learning about machine shops, learning more machine tools",
+	}
+	exact := SearchResult{
+		Title:   "Intro",
+		URL:     "http://exact.com",
+		Snippet: "A primer on machine learning basics",
+	}
+	results := []SearchResult{scattered, exact}
+
+	// Plain token overlap prefers the result with the terms scattered
+	if ranked := RankResults(`"machine learning"`, results); ranked[0].URL != "http://scattered.com" {
+		t.Fatalf("expected token overlap alone to favor the scattered result, got %s", ranked[0].URL)
+	}
+
+	ranked := RankResultsWithOptions(`"machine learning"`, results, RankOptions{ExactPhrase: true})
+	if ranked[0].URL != "http://exact.com" {
+		t.Errorf("expected exact phrase match first, got %s", ranked[0].URL)
+	}
+}
+
+func TestRankResults_ExactPhraseCaseSensitive(t *testing.T) {
+	lower := SearchResult{Title: "go tutorial", URL: "http://lower.com", Snippet: "learn go tutorial basics"}
+	proper := SearchResult{Title: "Intro", URL: "http://proper.com", Snippet: "The Go Tutorial"}
+	results := []SearchResult{lower, proper}
+
+	ranked := RankResultsWithOptions(`"Go Tutorial"`, results, RankOptions{ExactPhrase: true, CaseSensitive: true})
+	if ranked[0].URL != "http://proper.com" {
+		t.Errorf("expected case-sensitive phrase match first, got %s", ranked[0].URL)
+	}
+
+	ranked = RankResultsWithOptions(`"Go Tutorial"`, results, RankOptions{ExactPhrase: true})
+	if ranked[0].URL != "http://lower.com" {
+		t.Errorf("expected case-insensitive matching to keep term overlap ordering, got %s", ranked[0].URL)
+	}
+}
+
+func TestQuotedPhrases(t *testing.T) {
+	phrases := quotedPhrases(`compare "rust async" with "go channels" performance`)
+	if len(phrases) != 2 || phrases[0] != "rust async" || phrases[1] != "go channels" {
+		t.Errorf("unexpected phrases: %v", phrases)
+	}
+}
+
+func TestRankWithConsensus(t *testing.T) {
+	results := []SearchResult{
+		{Title: "Go tutorial", URL: "http://single.com", Engine: "bing"},
+		{Title: "Go tutorial", URL: "http://shared.com", Engine: "bing"},
+		{Title: "Go tutorial", URL: "http://shared.com", Engine: "brave"},
+	}
+
+	ranked := rankWithConsensus("go tutorial", results, hybridPriority)
+
+	if len(ranked) != 2 {
+		t.Fatalf("expected duplicate URLs to be merged, got %d results", len(ranked))
+	}
+	if ranked[0].URL != "http://shared.com" {
+		t.Errorf("expected URL returned by two engines to rank first, got %s", ranked[0].URL)
+	}
+}
+
+func TestRankWithConsensus_SearcherPriority(t *testing.T) {
+	results := []SearchResult{
+		{Title: "Go tutorial", URL: "http://bing.com/a", Engine: "bing"},
+		{Title: "Go tutorial", URL: "http://custom.com/a", Engine: "custom"},
+	}
+
+	// An engine that leads the searcher's order outranks one further down,
+	// even if it isn't one of the built-in engines
+	ranked := rankWithConsensus("go tutorial", results, []string{"custom", "bing"})
+	if ranked[0].URL != "http://custom.com/a" {
+		t.Errorf("expected the custom engine's result first, got %s", ranked[0].URL)
+	}
+
+	ranked = RankResultsWithOptions("go tutorial", results, RankOptions{EnginePriority: []string{"custom", "bing"}})
+	if ranked[0].URL != "http://custom.com/a" {
+		t.Errorf("expected EnginePriority to be honoured, got %s", ranked[0].URL)
+	}
+}
+
+func TestSearcher_RankUsesPriorityOrder(t *testing.T) {
+	engines := map[string]SearchEngine{
+		"bing":   &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Go tutorial", URL: "http://bing.com/a", Engine: "bing"}}},
+		"custom": &mockSearchEngine{name: "custom", results: []SearchResult{{Title: "Go tutorial", URL: "http://custom.com/a", Engine: "custom"}}},
+	}
+	searcher := NewSearcherWithEngines(engines, &mockContentExtractor{}, WithPriorityOrder([]string{"custom", "bing"}))
+
+	results, err := searcher.DeepSearch(context.Background(), "go tutorial", SearchOptions{MaxResults: 2, Rank: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].URL != "http://custom.com/a" {
+		t.Errorf("expected the ranking to follow the priority order, got %+v", results)
+	}
+}
+
+func TestRankResults_BlendedSignals(t *testing.T) {
+	longSnippet := strings.Repeat("A thorough walkthrough of channels, mutexes and worker pools. ", 4)
+
+	results := []SearchResult{
+		{Title: "Go concurrency", URL: "http://google.com/a", Engine: "google"},
+		{Title: "Go concurrency", URL: "http://ddg.com/a", Engine: "duckduckgo"},
+		{Title: "Go concurrency", URL: "http://long.com/a", Engine: "google", Snippet: longSnippet},
+		{Title: "Go concurrency", URL: "http://shared.com/a", Engine: "google", Engines: []string{"google", "bing"}},
+		{Title: "Cooking recipes", URL: "http://off-topic.com", Engine: "searxng", Snippet: longSnippet},
+	}
+
+	ranked := RankResults("go concurrency", results)
+
+	want := []string{"http://shared.com/a", "http://long.com/a", "http://ddg.com/a", "http://google.com/a", "http://off-topic.com"}
+	for i, url := range want {
+		if ranked[i].URL != url {
+			t.Fatalf("expected order %v, got %s at %d", want, ranked[i].URL, i)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package search
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	emailPlaceholder = "[email redacted]"
+	phonePlaceholder = "[phone redacted]"
+)
+
+var (
+	// emailPattern matches plain addresses and the common "name [at] host
+	// [dot] com" obfuscations
+	emailPattern = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+(?:@|\s?[\[(]at[\])]\s?)[a-z0-9\-]+(?:(?:\.|\s?[\[(]dot[\])]\s?)[a-z0-9\-]+)*(?:\.|\s?[\[(]dot[\])]\s?)[a-z]{2,}`)
+
+	// phonePattern matches candidate phone numbers: an optional country
+	// code, an optional area code in parentheses and digit groups joined by
+	// spaces, dots or dashes. Candidates are checked by isPhoneNumber.
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s.\-]?)?(?:\(\d{1,4}\)[\s.\-]?)?\d{2,6}(?:[\s.\-]\d{2,6}){1,4}`)
+
+	ipv4Pattern = regexp.MustCompile(`^\d{1,3}(?:\.\d{1,3}){3}$`)
+	datePattern = regexp.MustCompile(`^\d{4}[\-./]\d{1,2}[\-./]\d{1,2}$|^\d{1,2}[\-./]\d{1,2}[\-./]\d{4}$`)
+)
+
+// redactPII masks email addresses and phone numbers in text, leaving the
+// surrounding text untouched
+func redactPII(text string) string {
+	text = emailPattern.ReplaceAllString(text, emailPlaceholder)
+
+	matches := phonePattern.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if !isPhoneNumber(text, start, end) {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(phonePlaceholder)
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// isPhoneNumber reports whether text[start:end] looks like a phone number
+// rather than a date, IP address or part of a longer token
+func isPhoneNumber(text string, start, end int) bool {
+	candidate := text[start:end]
+	if ipv4Pattern.MatchString(candidate) || datePattern.MatchString(candidate) {
+		return false
+	}
+	if start > 0 && isWordByte(text[start-1]) || end < len(text) && isWordByte(text[end]) {
+		return false
+	}
+
+	digits := 0
+	for i := 0; i < len(candidate); i++ {
+		if candidate[i] >= '0' && candidate[i] <= '9' {
+			digits++
+		}
+	}
+
+	// Without a country or area code, require a full national number so
+	// that short numeric runs aren't masked
+	if strings.HasPrefix(candidate, "+") || strings.HasPrefix(candidate, "(") {
+		return digits >= 7 && digits <= 15
+	}
+	return digits >= 10 && digits <= 15
+}
+
+func isWordByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+// redactResults masks PII in each result's snippet, translated snippet,
+// content and summary
+func redactResults(results []SearchResult) {
+	for i := range results {
+		results[i].Snippet = redactPII(results[i].Snippet)
+		results[i].TranslatedSnippet = redactPII(results[i].TranslatedSnippet)
+		results[i].Content = redactPII(results[i].Content)
+		results[i].Summary = redactPII(results[i].Summary)
+	}
+}
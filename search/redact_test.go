@@ -0,0 +1,116 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRedactPII_Emails(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Contact jane.doe@example.com for details.", "Contact [email redacted] for details."},
+		{"Write to first+tag@mail.co.uk, thanks", "Write to [email redacted], thanks"},
+		{"Email: SUPPORT@Example.ORG", "Email: [email redacted]"},
+		{"Reach bob [at] example [dot] com today", "Reach [email redacted] today"},
+		{"Reach bob(at)example(dot)com today", "Reach [email redacted] today"},
+		{"Follow @golang on social media", "Follow @golang on social media"},
+	}
+
+	for _, tt := range tests {
+		if got := redactPII(tt.input); got != tt.expected {
+			t.Errorf("redactPII(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestRedactPII_Phones(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Call 555-123-4567 now", "Call [phone redacted] now"},
+		{"Call (555) 123-4567 now", "Call [phone redacted] now"},
+		{"Call +1 555 123 4567 now", "Call [phone redacted] now"},
+		{"Call 555.123.4567 now", "Call [phone redacted] now"},
+		{"London office: +44 20 7946 0958.", "London office: [phone redacted]."},
+		{"Berlin: +49-30-123456", "Berlin: [phone redacted]"},
+		{"Released on 2024-03-15 at 10:30", "Released on 2024-03-15 at 10:30"},
+		{"Server at 192.168.100.200 is down", "Server at 192.168.100.200 is down"},
+		{"Population 1 234 in 2020", "Population 1 234 in 2020"},
+		{"Order ID ABC555-123-4567", "Order ID ABC555-123-4567"},
+	}
+
+	for _, tt := range tests {
+		if got := redactPII(tt.input); got != tt.expected {
+			t.Errorf("redactPII(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestMultiEngineSearcher_RedactPII(t *testing.T) {
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing": &mockSearchEngine{name: "bing", results: []SearchResult{
+				{Title: "Team", URL: "http://example.com", Snippet: "Email jane@example.com or call 555-123-4567."},
+			}},
+		},
+		extractor: &mockContentExtractor{content: "Press: press@example.com"},
+	}
+
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{
+		MaxResults:     1,
+		ExtractContent: true,
+		RedactPII:      true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if results[0].Snippet != "Email [email redacted] or call [phone redacted]." {
+		t.Errorf("unexpected snippet: %q", results[0].Snippet)
+	}
+	if results[0].Content != "Press: [email redacted]" {
+		t.Errorf("unexpected content: %q", results[0].Content)
+	}
+}
+
+// recordingTranslator records the texts sent for translation
+type recordingTranslator struct {
+	texts []string
+}
+
+func (r *recordingTranslator) Translate(ctx context.Context, text, targetLang string) (string, error) {
+	r.texts = append(r.texts, text)
+	return "[" + targetLang + "] " + text, nil
+}
+
+func TestMultiEngineSearcher_RedactsBeforeTranslating(t *testing.T) {
+	translator := &recordingTranslator{}
+	o := applySearcherOptions([]SearcherOption{WithSnippetTranslator(translator, "en")})
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing": &mockSearchEngine{name: "bing", results: []SearchResult{
+				{Title: "Kontakt", URL: "http://example.de", Snippet: "Schreiben Sie an jane@example.com"},
+			}},
+		},
+		extractor:  &mockContentExtractor{},
+		translator: o.translator,
+	}
+
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{MaxResults: 1, RedactPII: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, text := range translator.texts {
+		if strings.Contains(text, "jane@example.com") {
+			t.Errorf("expected the translator to get redacted text, got %q", text)
+		}
+	}
+	if got := results[0].TranslatedSnippet; got != "[en] Schreiben Sie an [email redacted]" {
+		t.Errorf("unexpected translated snippet: %q", got)
+	}
+}
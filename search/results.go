@@ -0,0 +1,98 @@
+package search
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/liliang-cn/mcp-websearch-server/extraction"
+)
+
+// topUpResults queries the given engines in order with q, asking each for
+// want results, and appends results with URLs not already present until
+// want results are collected. Engine errors are skipped so that one failing
+// engine doesn't stop the top-up.
+func topUpResults(ctx context.Context, stats *searchStats, engines []SearchEngine, q EngineQuery, results []SearchResult, want int) []SearchResult {
+	seen := make(map[string]bool, len(results))
+	for _, r := range results {
+		seen[r.URL] = true
+	}
+
+	q.MaxResults = want
+	for _, engine := range engines {
+		if len(results) >= want || ctx.Err() != nil {
+			break
+		}
+
+		extra, err := stats.search(ctx, engine, q)
+		if err != nil {
+			continue
+		}
+
+		for _, r := range extra {
+			if len(results) >= want {
+				break
+			}
+			if seen[r.URL] {
+				continue
+			}
+			seen[r.URL] = true
+			results = append(results, r)
+		}
+	}
+
+	return results
+}
+
+// maxResultContent caps the extracted content kept per result, in
+// characters
+const maxResultContent = 3000
+
+// truncateContent cuts content to at most maxLength characters, preferring
+// to cut at the end of a sentence
+func truncateContent(content string, maxLength int) string {
+	runes := []rune(content)
+	if len(runes) <= maxLength {
+		return content
+	}
+
+	truncated := string(runes[:maxLength])
+	lastPeriod := strings.LastIndex(truncated, ". ")
+	if lastPeriod > len(truncated)/2 {
+		return truncated[:lastPeriod+1]
+	}
+	return truncated + "..."
+}
+
+// checkMinResults returns an *InsufficientResultsError when results falls
+// short of minResults.
+func checkMinResults(results []SearchResult, minResults int) error {
+	if minResults > 0 && len(results) < minResults {
+		return &InsufficientResultsError{Want: minResults, Results: results}
+	}
+	return nil
+}
+
+// resultDomain returns the lowercased host of rawURL without a leading
+// "www.", or "" if it can't be parsed
+func resultDomain(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// applyPageInfo fills in response metadata that extraction didn't provide,
+// using the Last-Modified header only when no better date is known
+func applyPageInfo(r *SearchResult, info *extraction.PageInfo) {
+	if r.HTTPStatus == 0 {
+		r.HTTPStatus = info.StatusCode
+	}
+	if r.FinalURL == "" {
+		r.FinalURL = info.FinalURL
+	}
+	if r.Date().IsZero() {
+		r.LastModified = info.LastModified
+	}
+}
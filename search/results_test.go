@@ -0,0 +1,21 @@
+package search
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateContent(t *testing.T) {
+	if got := truncateContent("Short.", 100); got != "Short." {
+		t.Errorf("expected short content unchanged, got %q", got)
+	}
+	if got := truncateContent("First sentence here. Second one runs on", 30); got != "First sentence here." {
+		t.Errorf("expected a cut at the sentence end, got %q", got)
+	}
+
+	got := truncateContent(strings.Repeat("日本語", 10), 7)
+	if !utf8.ValidString(got) || got != "日本語日本語日..." {
+		t.Errorf("expected 7 whole characters, got %q", got)
+	}
+}
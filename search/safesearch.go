@@ -0,0 +1,37 @@
+package search
+
+import "fmt"
+
+// SafeSearchLevel is how strictly engines filter explicit content
+type SafeSearchLevel string
+
+const (
+	SafeSearchOff      SafeSearchLevel = "off"
+	SafeSearchModerate SafeSearchLevel = "moderate"
+	SafeSearchStrict   SafeSearchLevel = "strict"
+)
+
+// checkSafeSearch rejects levels other than off, moderate and strict; empty
+// means moderate
+func checkSafeSearch(level SafeSearchLevel) error {
+	switch level {
+	case "", SafeSearchOff, SafeSearchModerate, SafeSearchStrict:
+		return nil
+	}
+	return fmt.Errorf("unsupported safe search level %q: use %q, %q or %q", level, SafeSearchOff, SafeSearchModerate, SafeSearchStrict)
+}
+
+// safeSearch returns q's safe search level, defaulting to moderate
+func (q EngineQuery) safeSearch() SafeSearchLevel {
+	if q.SafeSearch == "" {
+		return SafeSearchModerate
+	}
+	return q.SafeSearch
+}
+
+// duckDuckGoSafeSearch maps levels to DuckDuckGo's kp parameter
+var duckDuckGoSafeSearch = map[SafeSearchLevel]string{
+	SafeSearchOff:      "-2",
+	SafeSearchModerate: "-1",
+	SafeSearchStrict:   "1",
+}
@@ -0,0 +1,55 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGoQueryEngines_SafeSearchParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine SearchEngine
+		client func(SearchEngine) *http.Client
+		level  SafeSearchLevel
+		want   string
+	}{
+		{"bing strict", NewBingGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*bingGoQueryEngine).client }, SafeSearchStrict, "&adlt=strict"},
+		{"bing default", NewBingGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*bingGoQueryEngine).client }, "", "&adlt=moderate"},
+		{"duckduckgo strict", NewDuckDuckGoGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*duckDuckGoGoQueryEngine).client }, SafeSearchStrict, "&kp=1"},
+		{"duckduckgo off", NewDuckDuckGoGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*duckDuckGoGoQueryEngine).client }, SafeSearchOff, "&kp=-2"},
+		{"brave strict", NewBraveGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*braveGoQueryEngine).client }, SafeSearchStrict, "&safesearch=strict"},
+		{"google strict", NewGoogleGoQueryEngine(), func(e SearchEngine) *http.Client { return e.(*googleGoQueryEngine).client }, SafeSearchStrict, "&safe=active"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &fixtureTransport{body: "<html><body></body></html>"}
+			tt.client(tt.engine).Transport = transport
+
+			if _, err := tt.engine.(QueryEngine).SearchQuery(context.Background(), EngineQuery{Query: "golang", MaxResults: 10, SafeSearch: tt.level}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(transport.urls) != 1 || !strings.Contains(transport.urls[0], tt.want) {
+				t.Errorf("expected request URL to contain %q, got %v", tt.want, transport.urls)
+			}
+		})
+	}
+}
+
+func TestSearch_RejectsUnknownSafeSearch(t *testing.T) {
+	engine := &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Result", URL: "http://example.com"}}}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": engine}, nil)
+
+	if _, err := searcher.Search(context.Background(), "golang", SearchOptions{MaxResults: 1, SafeSearch: "paranoid"}); err == nil {
+		t.Error("expected an unknown safe search level to be rejected")
+	}
+	if engine.calls != 0 {
+		t.Errorf("expected no engine call for an invalid level, got %d", engine.calls)
+	}
+
+	if _, err := searcher.Search(context.Background(), "golang", SearchOptions{MaxResults: 1, SafeSearch: SafeSearchStrict}); err != nil {
+		t.Errorf("unexpected error for strict: %v", err)
+	}
+}
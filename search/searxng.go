@@ -0,0 +1,182 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searxngEngine queries a SearXNG meta-search instance through its JSON API
+type searxngEngine struct {
+	client      *http.Client
+	instanceURL string
+	config      engineConfig
+}
+
+// searxngResponse is the part of SearXNG's JSON response the engine uses
+type searxngResponse struct {
+	NumberOfResults float64 `json:"number_of_results"`
+	Results         []struct {
+		URL     string `json:"url"`
+		Title   string `json:"title"`
+		Content string `json:"content"`
+	} `json:"results"`
+}
+
+// NewSearXNGEngine creates an engine that searches through the SearXNG
+// instance at instanceURL, e.g. "https://searx.example.org". The instance
+// must have the json format enabled under search.formats in its settings.
+func NewSearXNGEngine(instanceURL string, opts ...EngineOption) SearchEngine {
+	config := newEngineConfig(Selectors{}, opts)
+	return &searxngEngine{
+		client: config.applyProxy(&http.Client{
+			Timeout: 15 * time.Second,
+		}),
+		instanceURL: strings.TrimSuffix(instanceURL, "/"),
+		config:      config,
+	}
+}
+
+func (s *searxngEngine) Name() string {
+	return "searxng"
+}
+
+func (s *searxngEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	return s.SearchQuery(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+// SearchQuery is Search with the full set of per-search parameters.
+// SearXNG pages by page number, so Offset selects the page holding it and
+// the results before it on that page are dropped. After is mapped to the
+// smallest time_range that covers it; Before is ignored.
+func (s *searxngEngine) SearchQuery(ctx context.Context, q EngineQuery) ([]SearchResult, error) {
+	results, _, err := s.search(ctx, q)
+	return results, err
+}
+
+// SearchWithStats is Search that also reports the instance's estimate of
+// the total number of results
+func (s *searxngEngine) SearchWithStats(ctx context.Context, query string, maxResults int) ([]SearchResult, SearchStats, error) {
+	return s.search(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+// searxngPageSize is the number of results SearXNG returns per page with
+// its default settings
+const searxngPageSize = 10
+
+// searxngSafeSearch maps levels to SearXNG's safesearch parameter
+var searxngSafeSearch = map[SafeSearchLevel]string{
+	SafeSearchOff:      "0",
+	SafeSearchModerate: "1",
+	SafeSearchStrict:   "2",
+}
+
+func (s *searxngEngine) search(ctx context.Context, q EngineQuery) ([]SearchResult, SearchStats, error) {
+	if s.config.err != nil {
+		return nil, SearchStats{}, s.config.err
+	}
+	if s.instanceURL == "" {
+		return nil, SearchStats{}, fmt.Errorf("searxng: no instance URL configured")
+	}
+
+	params := url.Values{}
+	params.Set("q", q.Query)
+	params.Set("format", "json")
+	params.Set("safesearch", searxngSafeSearch[q.safeSearch()])
+	if q.Language != "" {
+		lang := strings.ToLower(q.Language)
+		if q.Region != "" {
+			lang += "-" + strings.ToUpper(q.Region)
+		}
+		params.Set("language", lang)
+	}
+	if q.Offset > 0 {
+		params.Set("pageno", strconv.Itoa(q.Offset/searxngPageSize+1))
+	}
+	if tr := searxngTimeRange(q.After, time.Now()); tr != "" {
+		params.Set("time_range", tr)
+	}
+	searchURL := s.instanceURL + "/search?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return nil, SearchStats{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, SearchStats{}, fmt.Errorf("failed to fetch SearXNG results: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := readLimitedBody(resp.Body, s.config.maxBodySize, searchURL)
+	if err != nil {
+		return nil, SearchStats{}, err
+	}
+
+	// Instances without the json format enabled answer with an HTML page,
+	// usually with a 403
+	if isHTMLResponse(resp, data) {
+		return nil, SearchStats{}, fmt.Errorf("searxng instance %s returned HTML instead of JSON (status %d); enable the json format under search.formats in its settings.yml", s.instanceURL, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, SearchStats{}, fmt.Errorf("searxng instance %s returned status %d", s.instanceURL, resp.StatusCode)
+	}
+
+	var parsed searxngResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, SearchStats{}, fmt.Errorf("failed to decode SearXNG response: %w", err)
+	}
+
+	var results []SearchResult
+	for _, r := range parsed.Results {
+		if r.URL == "" || r.Title == "" {
+			continue
+		}
+		results = append(results, SearchResult{
+			Title:   strings.TrimSpace(r.Title),
+			URL:     r.URL,
+			Snippet: strings.TrimSpace(r.Content),
+			Engine:  s.Name(),
+		})
+	}
+	results = skipResults(results, q.Offset%searxngPageSize, q.MaxResults)
+
+	return results, SearchStats{TotalEstimate: int64(parsed.NumberOfResults)}, nil
+}
+
+// searxngTimeRange returns the smallest SearXNG time_range that reaches
+// back to after, or "" when after is unset or older than a year
+func searxngTimeRange(after, now time.Time) string {
+	if after.IsZero() {
+		return ""
+	}
+	age := now.Sub(after)
+	switch {
+	case age <= 24*time.Hour:
+		return "day"
+	case age <= 7*24*time.Hour:
+		return "week"
+	case age <= 31*24*time.Hour:
+		return "month"
+	case age <= 366*24*time.Hour:
+		return "year"
+	}
+	return ""
+}
+
+// isHTMLResponse reports whether a response is an HTML page, going by its
+// Content-Type or, failing that, its first non-space byte
+func isHTMLResponse(resp *http.Response, body []byte) bool {
+	if strings.Contains(resp.Header.Get("Content-Type"), "text/html") {
+		return true
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("<"))
+}
@@ -0,0 +1,155 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearXNGEngine_Search(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" || r.URL.Query().Get("format") != "json" || r.URL.Query().Get("q") != "golang generics" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"query": "golang generics",
+			"number_of_results": 12300,
+			"results": [
+				{"url": "https://go.dev/doc/tutorial/generics", "title": "Tutorial: Getting started with generics", "content": "This tutorial introduces generics.", "engine": "google"},
+				{"url": "", "title": "No URL"},
+				{"url": "https://gobyexample.com/generics", "title": "Go by Example: Generics", "content": "Generics example.", "engine": "bing"},
+				{"url": "https://example.com/third", "title": "Third", "content": "Third result."}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	engine := NewSearXNGEngine(server.URL + "/").(*searxngEngine)
+	results, stats, err := engine.SearchWithStats(context.Background(), "golang generics", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %+v", len(results), results)
+	}
+	if results[0].Title != "Tutorial: Getting started with generics" || results[0].URL != "https://go.dev/doc/tutorial/generics" ||
+		results[0].Snippet != "This tutorial introduces generics." || results[0].Engine != "searxng" {
+		t.Errorf("unexpected first result: %+v", results[0])
+	}
+	if results[1].URL != "https://gobyexample.com/generics" {
+		t.Errorf("expected results without a URL to be skipped, got %s", results[1].URL)
+	}
+	if stats.TotalEstimate != 12300 {
+		t.Errorf("expected total estimate 12300, got %d", stats.TotalEstimate)
+	}
+}
+
+func TestSearXNGEngine_SearchQuery(t *testing.T) {
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results": [
+			{"url": "https://example.com/21", "title": "Result 21"},
+			{"url": "https://example.com/22", "title": "Result 22"},
+			{"url": "https://example.com/23", "title": "Result 23"},
+			{"url": "https://example.com/24", "title": "Result 24"}
+		]}`))
+	}))
+	defer server.Close()
+
+	engine := NewSearXNGEngine(server.URL).(*searxngEngine)
+	results, err := engine.SearchQuery(context.Background(), EngineQuery{
+		Query:      "golang",
+		MaxResults: 2,
+		Offset:     22,
+		Language:   "de",
+		Region:     "at",
+		SafeSearch: SafeSearchStrict,
+		After:      time.Now().Add(-3 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{"pageno": "3", "language": "de-AT", "safesearch": "2", "time_range": "week"}
+	for key, value := range want {
+		if got.Get(key) != value {
+			t.Errorf("expected %s=%s, got %q", key, value, got.Get(key))
+		}
+	}
+	// Offset 22 is the third result on page 3
+	if len(results) != 2 || results[0].URL != "https://example.com/23" || results[1].URL != "https://example.com/24" {
+		t.Errorf("expected the results from offset 22, got %+v", results)
+	}
+}
+
+func TestSearXNGTimeRange(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		after time.Time
+		want  string
+	}{
+		{time.Time{}, ""},
+		{now.Add(-time.Hour), "day"},
+		{now.AddDate(0, 0, -5), "week"},
+		{now.AddDate(0, 0, -20), "month"},
+		{now.AddDate(0, -6, 0), "year"},
+		{now.AddDate(-3, 0, 0), ""},
+	}
+	for _, tt := range tests {
+		if got := searxngTimeRange(tt.after, now); got != tt.want {
+			t.Errorf("searxngTimeRange(%v) = %q, want %q", tt.after, got, tt.want)
+		}
+	}
+}
+
+func TestSearXNGEngine_HTMLResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<!DOCTYPE html><html><body>403 Forbidden</body></html>"))
+	}))
+	defer server.Close()
+
+	_, err := NewSearXNGEngine(server.URL).Search(context.Background(), "golang", 5)
+	if err == nil {
+		t.Fatal("expected an error when the instance returns HTML")
+	}
+	if !strings.Contains(err.Error(), "HTML instead of JSON") || !strings.Contains(err.Error(), "search.formats") {
+		t.Errorf("expected a descriptive error, got %v", err)
+	}
+}
+
+func TestWithSearXNG(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results": [{"url": "https://example.com", "title": "Private result"}]}`))
+	}))
+	defer server.Close()
+
+	bing := &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Bing", URL: "https://bing.example.com", Engine: "bing"}}}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": bing}, nil, WithSearXNG(server.URL))
+
+	results, err := searcher.Search(context.Background(), "golang", SearchOptions{MaxResults: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Engine != "searxng" {
+		t.Errorf("expected the search to be routed through SearXNG, got %+v", results)
+	}
+	if bing.calls != 0 {
+		t.Error("expected the built-in engine not to be queried")
+	}
+
+	// An empty instance URL registers nothing
+	plain := NewSearcherWithEngines(map[string]SearchEngine{"bing": bing}, nil, WithSearXNG("")).(*multiEngineSearcher)
+	if _, ok := plain.engines["searxng"]; ok {
+		t.Error("expected no searxng engine without an instance URL")
+	}
+}
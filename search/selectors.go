@@ -0,0 +1,114 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Selectors holds the CSS selectors a goquery engine uses to parse a results
+// page. Title, Link and Snippet are evaluated relative to each Result element
+// and are tried in order until one matches; an empty list means the Result
+// element itself is used. TotalCount selects the "About N results" text on
+// the whole page; empty means the engine doesn't show one. Sitelinks selects
+// the sitelink entries nested in a Result, each holding a link and
+// optionally a <p> snippet; empty means they aren't parsed.
+type Selectors struct {
+	Result  string
+	Title   []string
+	Link    []string
+	Snippet []string
+
+	TotalCount string
+	Sitelinks  string
+}
+
+// merge returns s with any empty fields filled in from defaults, so callers
+// can override a single broken selector without restating the rest.
+func (s Selectors) merge(defaults Selectors) Selectors {
+	if s.Result == "" {
+		s.Result = defaults.Result
+	}
+	if len(s.Title) == 0 {
+		s.Title = defaults.Title
+	}
+	if len(s.Link) == 0 {
+		s.Link = defaults.Link
+	}
+	if len(s.Snippet) == 0 {
+		s.Snippet = defaults.Snippet
+	}
+	if s.TotalCount == "" {
+		s.TotalCount = defaults.TotalCount
+	}
+	if s.Sitelinks == "" {
+		s.Sitelinks = defaults.Sitelinks
+	}
+	return s
+}
+
+// firstText returns the trimmed text of the first selector that yields
+// non-empty text within s.
+func firstText(s *goquery.Selection, selectors []string) string {
+	if len(selectors) == 0 {
+		return strings.TrimSpace(s.Text())
+	}
+	for _, sel := range selectors {
+		if text := strings.TrimSpace(s.Find(sel).First().Text()); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// firstAttr returns the attribute value of the first selector that yields a
+// non-empty value within s.
+func firstAttr(s *goquery.Selection, selectors []string, attr string) string {
+	if len(selectors) == 0 {
+		val, _ := s.Attr(attr)
+		return val
+	}
+	for _, sel := range selectors {
+		if val, ok := s.Find(sel).First().Attr(attr); ok && val != "" {
+			return val
+		}
+	}
+	return ""
+}
+
+// parseSitelinks returns the sitelinks under a result as sub-results,
+// skipping links to the result itself and repeats
+func parseSitelinks(s *goquery.Selection, selector, mainURL, engine string) []SearchResult {
+	if selector == "" {
+		return nil
+	}
+
+	var subResults []SearchResult
+	seen := map[string]bool{mainURL: true}
+	s.Find(selector).Each(func(i int, item *goquery.Selection) {
+		anchor := item
+		if goquery.NodeName(item) != "a" {
+			anchor = item.Find("a[href]").First()
+		}
+
+		link, _ := anchor.Attr("href")
+		title := strings.TrimSpace(anchor.Text())
+		if link == "" || title == "" || seen[link] {
+			return
+		}
+		seen[link] = true
+
+		snippet := ""
+		if anchor != item {
+			snippet = strings.TrimSpace(item.Find("p").First().Text())
+		}
+
+		subResults = append(subResults, SearchResult{
+			Title:   title,
+			URL:     link,
+			Snippet: snippet,
+			Engine:  engine,
+		})
+	})
+	return subResults
+}
@@ -0,0 +1,175 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func mustParseHTML(t *testing.T, html string) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	return doc
+}
+
+func TestBingGoQueryEngine_DefaultSelectors(t *testing.T) {
+	doc := mustParseHTML(t, `
+		<ol id="b_results">
+			<li class="b_algo">
+				<h2><a href="https://example.com/one">First Result</a></h2>
+				<div class="b_caption"><p>First snippet</p></div>
+			</li>
+			<li class="b_algo">
+				<h2><a href="https://example.com/two">Second Result</a></h2>
+				<div class="b_caption"><p>Second snippet</p></div>
+			</li>
+		</ol>`)
+
+	engine := NewBingGoQueryEngine().(*bingGoQueryEngine)
+	results := engine.parseResults(doc, 10)
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Title != "First Result" || results[0].URL != "https://example.com/one" || results[0].Snippet != "First snippet" {
+		t.Errorf("unexpected first result: %+v", results[0])
+	}
+}
+
+func TestGoQueryEngines_CustomSelectors(t *testing.T) {
+	doc := mustParseHTML(t, `
+		<div id="serp">
+			<section class="hit">
+				<span class="hit-title">Custom Title</span>
+				<a class="hit-url" href="https://example.com/custom">visit</a>
+				<div class="hit-body">Custom snippet text</div>
+			</section>
+			<section class="hit">
+				<span class="hit-title">Another Title</span>
+				<a class="hit-url" href="https://example.com/another">visit</a>
+				<div class="hit-body">Another snippet</div>
+			</section>
+		</div>`)
+
+	custom := WithSelectors(Selectors{
+		Result:  "section.hit",
+		Title:   []string{".hit-title"},
+		Link:    []string{"a.hit-url"},
+		Snippet: []string{".hit-body"},
+	})
+
+	tests := []struct {
+		name  string
+		parse func(*goquery.Document, int) []SearchResult
+	}{
+		{"bing", NewBingGoQueryEngine(custom).(*bingGoQueryEngine).parseResults},
+		{"brave", NewBraveGoQueryEngine(custom).(*braveGoQueryEngine).parseResults},
+		{"google", NewGoogleGoQueryEngine(custom).(*googleGoQueryEngine).parseResults},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := tt.parse(doc, 10)
+			if len(results) != 2 {
+				t.Fatalf("expected 2 results, got %d", len(results))
+			}
+			if results[0].Title != "Custom Title" {
+				t.Errorf("expected title 'Custom Title', got %q", results[0].Title)
+			}
+			if results[0].URL != "https://example.com/custom" {
+				t.Errorf("expected custom URL, got %q", results[0].URL)
+			}
+			if results[0].Snippet != "Custom snippet text" {
+				t.Errorf("expected custom snippet, got %q", results[0].Snippet)
+			}
+		})
+	}
+}
+
+func TestWithSelectors_KeepsDefaultsForEmptyFields(t *testing.T) {
+	engine := NewBingGoQueryEngine(WithSelectors(Selectors{
+		Snippet: []string{".new-snippet"},
+	})).(*bingGoQueryEngine)
+
+	sel := engine.config.selectors
+	if sel.Result != DefaultBingSelectors.Result {
+		t.Errorf("expected default result selector, got %q", sel.Result)
+	}
+	if len(sel.Title) != len(DefaultBingSelectors.Title) {
+		t.Errorf("expected default title selectors, got %v", sel.Title)
+	}
+	if len(sel.Snippet) != 1 || sel.Snippet[0] != ".new-snippet" {
+		t.Errorf("expected overridden snippet selector, got %v", sel.Snippet)
+	}
+}
+
+func TestDuckDuckGoGoQueryEngine_CustomSnippetSelector(t *testing.T) {
+	doc := mustParseHTML(t, `
+		<table>
+			<tr><td><a class="result-link" href="https://example.com/ddg">DDG Result</a></td></tr>
+			<tr><td class="snippet-v2">Snippet from new layout</td></tr>
+		</table>`)
+
+	engine := NewDuckDuckGoGoQueryEngine(WithSelectors(Selectors{
+		Snippet: []string{".snippet-v2"},
+	})).(*duckDuckGoGoQueryEngine)
+
+	results := engine.parseResults(doc, 10)
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Snippet != "Snippet from new layout" {
+		t.Errorf("expected snippet from custom selector, got %q", results[0].Snippet)
+	}
+}
+
+func TestGoQueryEngines_SkipFirst(t *testing.T) {
+	ddgPage := mustParseHTML(t, `<table>
+		<tr><td><a class="result-link" href="https://ads.example.com/">Sponsored: Learn Go fast</a></td></tr>
+		<tr><td class="result-snippet">Ad</td></tr>
+		<tr><td><a class="result-link" href="https://go.dev/">The Go Programming Language</a></td></tr>
+		<tr><td class="result-snippet">Go is an open source programming language.</td></tr>
+		<tr><td><a class="result-link" href="https://go.dev/tour/">A Tour of Go</a></td></tr>
+		<tr><td class="result-snippet">An interactive introduction.</td></tr>
+	</table>`)
+
+	bravePage := mustParseHTML(t, `
+		<div class="snippet"><a class="snippet-title" href="https://ads.example.com/">Sponsored: Go hosting</a><p class="snippet-description">Ad</p></div>
+		<div class="snippet"><a class="snippet-title" href="https://go.dev/">The Go Programming Language</a><p class="snippet-description">Go is open source.</p></div>
+		<div class="snippet"><a class="snippet-title" href="https://go.dev/tour/">A Tour of Go</a><p class="snippet-description">An interactive introduction.</p></div>`)
+
+	tests := []struct {
+		name  string
+		parse func(maxResults int) []SearchResult
+	}{
+		{"duckduckgo", func(n int) []SearchResult {
+			return NewDuckDuckGoGoQueryEngine(WithSkipFirst(1)).(*duckDuckGoGoQueryEngine).parseResults(ddgPage, n)
+		}},
+		{"brave", func(n int) []SearchResult {
+			return NewBraveGoQueryEngine(WithSkipFirst(1)).(*braveGoQueryEngine).parseResults(bravePage, n)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The skipped row doesn't count against maxResults
+			results := tt.parse(2)
+			if len(results) != 2 {
+				t.Fatalf("expected 2 results, got %d: %+v", len(results), results)
+			}
+			if results[0].URL != "https://go.dev/" || results[1].URL != "https://go.dev/tour/" {
+				t.Errorf("expected the sponsored first row to be skipped, got %+v", results)
+			}
+		})
+	}
+
+	// Without the option the sponsored row is parsed like any other
+	results := NewDuckDuckGoGoQueryEngine().(*duckDuckGoGoQueryEngine).parseResults(ddgPage, 2)
+	if len(results) == 0 || results[0].URL != "https://ads.example.com/" {
+		t.Errorf("expected the first row by default, got %+v", results)
+	}
+}
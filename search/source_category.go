@@ -0,0 +1,73 @@
+package search
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Source categories reported in SearchResult.SourceCategory, a coarse hint
+// for how much weight to give a source
+const (
+	SourceNews     = "news"
+	SourceBlog     = "blog"
+	SourceForum    = "forum"
+	SourceOfficial = "official"
+	SourceWiki     = "wiki"
+)
+
+var (
+	forumHosts = []string{"reddit.com", "stackoverflow.com", "stackexchange.com", "quora.com", "news.ycombinator.com", "discourse.org"}
+
+	blogHosts = []string{"medium.com", "substack.com", "blogspot.com", "wordpress.com", "dev.to", "hashnode.dev"}
+)
+
+// ClassifySource labels a result as news, blog, forum, official or wiki from
+// its domain and page type. It returns "" when none of them fit.
+func ClassifySource(r SearchResult) string {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	path := strings.ToLower(u.Path)
+
+	switch {
+	case hostMatches(host, []string{"wikipedia.org", "wikimedia.org", "fandom.com"}) ||
+		strings.HasSuffix(host, ".wiki") || strings.HasPrefix(path, "/wiki/"):
+		return SourceWiki
+	case isOfficialHost(host):
+		return SourceOfficial
+	case hostMatches(host, forumHosts) || strings.Contains(host, "forum") || strings.HasPrefix(path, "/forum"):
+		return SourceForum
+	case hostMatches(host, blogHosts) || strings.HasPrefix(host, "blog.") || strings.Contains(path, "/blog/"):
+		return SourceBlog
+	}
+
+	pageType := r.PageType
+	if pageType == "" {
+		pageType = DetectPageType(r)
+	}
+	if pageType == PageTypeNews {
+		return SourceNews
+	}
+	return ""
+}
+
+// isOfficialHost reports whether host belongs to a government or
+// intergovernmental body: a .gov, .mil or .int domain such as cdc.gov, or
+// a country's gov second-level domain such as gov.uk
+func isOfficialHost(host string) bool {
+	labels := strings.Split(strings.TrimSuffix(host, "."), ".")
+	switch labels[len(labels)-1] {
+	case "gov", "mil", "int":
+		return len(labels) > 1
+	}
+	return len(labels) > 1 && labels[len(labels)-2] == "gov" && len(labels[len(labels)-1]) == 2
+}
+
+// categorizeSources sets SourceCategory on each result
+func categorizeSources(results []SearchResult) {
+	for i := range results {
+		results[i].SourceCategory = ClassifySource(results[i])
+	}
+}
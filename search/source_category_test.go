@@ -0,0 +1,61 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestClassifySource(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://en.wikipedia.org/wiki/Go_(programming_language)", SourceWiki},
+		{"https://www.cdc.gov/flu/index.html", SourceOfficial},
+		{"https://www.gov.uk/browse/tax", SourceOfficial},
+		{"https://www.nato.int/cps/en/natohq/topics.htm", SourceOfficial},
+		{"https://www.health.gov.au/topics", SourceOfficial},
+		{"https://int.company.com/pricing", ""},
+		{"https://api.int.example.org/pricing", ""},
+		{"https://gov.example.com/pricing", ""},
+		{"https://example.gov.com/pricing", ""},
+		{"https://www.reddit.com/r/golang/comments/abc", SourceForum},
+		{"https://stackoverflow.com/questions/1", SourceForum},
+		{"https://janedoe.dev/blog/my-first-post", SourceBlog},
+		{"https://someone.substack.com/p/essay", SourceBlog},
+		{"https://www.reuters.com/world/story", SourceNews},
+		{"https://example.com/pricing", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifySource(SearchResult{URL: tt.url}); got != tt.want {
+			t.Errorf("ClassifySource(%s) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSearch_SourceCategoryInStructuredOutput(t *testing.T) {
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing": &mockSearchEngine{name: "bing", results: []SearchResult{
+				{Title: "Thread", URL: "https://www.reddit.com/r/golang/comments/abc", Engine: "bing"},
+			}},
+		},
+		extractor: &mockContentExtractor{},
+	}
+
+	results, err := searcher.Search(context.Background(), "test", SearchOptions{MaxResults: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(results[0])
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"source_category":"forum"`) {
+		t.Errorf("expected source_category in output, got %s", data)
+	}
+}
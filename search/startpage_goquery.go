@@ -0,0 +1,118 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type startpageGoQueryEngine struct {
+	client *http.Client
+	config engineConfig
+}
+
+// DefaultStartpageSelectors are the selectors used to parse Startpage
+// results pages, which proxy Google's results
+var DefaultStartpageSelectors = Selectors{
+	Result:  ".w-gl__result",
+	Title:   []string{".w-gl__result-title h3", ".w-gl__result-title"},
+	Link:    []string{"a.w-gl__result-title", ".w-gl__result-title a", "a"},
+	Snippet: []string{".w-gl__description"},
+}
+
+// startpageResultsContainer wraps the organic results. Results pages have
+// it even when nothing matched; Startpage's anti-bot interstitial doesn't.
+const startpageResultsContainer = ".w-gl"
+
+func NewStartpageGoQueryEngine(opts ...EngineOption) SearchEngine {
+	config := newEngineConfig(DefaultStartpageSelectors, opts)
+	return &startpageGoQueryEngine{
+		client: config.applyProxy(&http.Client{
+			Timeout: 10 * time.Second,
+		}),
+		config: config,
+	}
+}
+
+func (s *startpageGoQueryEngine) Name() string {
+	return "startpage"
+}
+
+func (s *startpageGoQueryEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	return s.SearchQuery(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+// SearchQuery is Search with the full set of per-search parameters.
+// Startpage only offers fixed recency windows, so After and Before are
+// ignored, Language only reaches it through Accept-Language, and SafeSearch
+// is left at Startpage's default.
+func (s *startpageGoQueryEngine) SearchQuery(ctx context.Context, q EngineQuery) ([]SearchResult, error) {
+	if s.config.err != nil {
+		return nil, s.config.err
+	}
+
+	searchURL := fmt.Sprintf("https://www.startpage.com/sp/search?query=%s", url.QueryEscape(q.Query))
+	// Startpage pages hold 10 results and are numbered from 1
+	page := q.Offset/10 + 1
+	if page > 1 {
+		searchURL += fmt.Sprintf("&page=%d", page)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", s.config.userAgent())
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8")
+	req.Header.Set("Accept-Language", q.acceptLanguage("en-US,en;q=0.9"))
+	req.Header.Set("Referer", "https://www.startpage.com/")
+
+	resp, err := fetchResultsPage(s.client, req, s.Name())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	doc, err := parseLimitedBody(resp.Body, s.config.maxBodySize, searchURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	if doc.Find(startpageResultsContainer).Length() == 0 {
+		return nil, &BlockedError{Engine: s.Name(), URL: searchURL}
+	}
+
+	results := s.parseResults(doc, q.Offset%10+q.MaxResults)
+	return skipResults(results, q.Offset%10, q.MaxResults), nil
+}
+
+func (s *startpageGoQueryEngine) parseResults(doc *goquery.Document, maxResults int) []SearchResult {
+	sel := s.config.selectors
+	var results []SearchResult
+
+	doc.Find(sel.Result).Each(func(i int, r *goquery.Selection) {
+		if len(results) >= maxResults || i < s.config.skipFirst {
+			return
+		}
+
+		title := firstText(r, sel.Title)
+		link := firstAttr(r, sel.Link, "href")
+		snippet := firstText(r, sel.Snippet)
+
+		if link != "" && title != "" {
+			results = append(results, SearchResult{
+				Title:   title,
+				URL:     link,
+				Snippet: snippet,
+				Engine:  s.Name(),
+			})
+		}
+	})
+
+	return results
+}
@@ -0,0 +1,57 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const startpageFixture = `
+	<html><body><div class="w-gl">
+		<div class="w-gl__result">
+			<a class="w-gl__result-title" href="https://go.dev/"><h3>The Go Programming Language</h3></a>
+			<p class="w-gl__description">Go is an open source programming language.</p>
+		</div>
+		<div class="w-gl__result">
+			<a class="w-gl__result-title" href="https://go.dev/tour/"><h3>A Tour of Go</h3></a>
+			<p class="w-gl__description">An interactive introduction to Go.</p>
+		</div>
+	</div></body></html>`
+
+func TestStartpageGoQueryEngine_ParseResults(t *testing.T) {
+	engine := NewStartpageGoQueryEngine().(*startpageGoQueryEngine)
+	transport := &fixtureTransport{body: startpageFixture}
+	engine.client.Transport = transport
+
+	results, err := engine.Search(context.Background(), "golang", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %+v", len(results), results)
+	}
+	if results[0].Title != "The Go Programming Language" || results[0].URL != "https://go.dev/" ||
+		results[0].Snippet != "Go is an open source programming language." || results[0].Engine != "startpage" {
+		t.Errorf("unexpected first result: %+v", results[0])
+	}
+	if !strings.HasPrefix(transport.urls[0], "https://www.startpage.com/sp/search?query=golang") {
+		t.Errorf("unexpected request URL %s", transport.urls[0])
+	}
+}
+
+func TestStartpageGoQueryEngine_Interstitial(t *testing.T) {
+	engine := NewStartpageGoQueryEngine().(*startpageGoQueryEngine)
+	engine.client.Transport = &fixtureTransport{body: `<html><body><form id="captcha">Please verify you are human</form></body></html>`}
+
+	_, err := engine.Search(context.Background(), "golang", 10)
+	if !errors.Is(err, ErrBlocked) {
+		t.Fatalf("expected ErrBlocked, got %v", err)
+	}
+
+	var blocked *BlockedError
+	if !errors.As(err, &blocked) || blocked.Engine != "startpage" {
+		t.Errorf("expected a *BlockedError for startpage, got %#v", err)
+	}
+}
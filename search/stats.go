@@ -0,0 +1,60 @@
+package search
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// SearchStats is what an engine reports about a search besides its results
+type SearchStats struct {
+	// TotalEstimate is the engine's "About N results" figure, a rough
+	// signal of how popular or ambiguous the query is. Zero when the
+	// engine doesn't show one or it can't be parsed.
+	TotalEstimate int64
+}
+
+// StatsReporter is implemented by engines that can report SearchStats
+// alongside their results
+type StatsReporter interface {
+	SearchWithStats(ctx context.Context, query string, maxResults int) ([]SearchResult, SearchStats, error)
+}
+
+// countPattern matches a whole number, optionally grouped in thousands by
+// commas, dots, apostrophes or (non-breaking) spaces
+var countPattern = regexp.MustCompile(`\d{1,3}(?:[,.'\s\x{00a0}\x{202f}]\d{3})+|\d+`)
+
+// ParseTotalEstimate extracts the result count from text such as
+// "About 1,230,000 results" or "Environ 1 230 000 résultats". When the text
+// holds several numbers, as in "Page 2 of 1,230 results", the largest is
+// used. It returns 0 when no number is found.
+func ParseTotalEstimate(text string) int64 {
+	var best int64
+	for _, match := range countPattern.FindAllString(text, -1) {
+		digits := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, match)
+
+		n, err := strconv.ParseInt(digits, 10, 64)
+		if err == nil && n > best {
+			best = n
+		}
+	}
+	return best
+}
+
+// parseSearchStats reads the stats shown on a results page
+func parseSearchStats(doc *goquery.Document, sel Selectors) SearchStats {
+	if sel.TotalCount == "" {
+		return SearchStats{}
+	}
+	return SearchStats{
+		TotalEstimate: ParseTotalEstimate(doc.Find(sel.TotalCount).First().Text()),
+	}
+}
@@ -0,0 +1,90 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseTotalEstimate(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int64
+	}{
+		{"About 1,230,000 results", 1230000},
+		{"1,230,000 results", 1230000},
+		{"Ungefähr 1.230.000 Ergebnisse", 1230000},
+		{"Environ 1 230 000 résultats", 1230000},
+		{"Environ 1\u00a0230\u00a0000 résultats", 1230000},
+		{"Environ 1\u202f230\u202f000 résultats", 1230000},
+		{"Etwa 1'230'000 Treffer", 1230000},
+		{"約 1,230,000 件", 1230000},
+		{"Page 2 of 45,600 results", 45600},
+		{"987 results", 987},
+		{"No results", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := ParseTotalEstimate(tt.text); got != tt.expected {
+			t.Errorf("ParseTotalEstimate(%q) = %d, want %d", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestBingGoQueryEngine_ParseSearchStats(t *testing.T) {
+	doc := mustParseHTML(t, `
+		<div id="b_tween"><span class="sb_count">About 12,300,000 results</span></div>
+		<ol id="b_results">
+			<li class="b_algo"><h2><a href="https://example.com">Example</a></h2></li>
+		</ol>`)
+
+	engine := NewBingGoQueryEngine().(*bingGoQueryEngine)
+	stats := parseSearchStats(doc, engine.config.selectors)
+	if stats.TotalEstimate != 12300000 {
+		t.Errorf("expected estimate 12300000, got %d", stats.TotalEstimate)
+	}
+
+	// Engines without a count selector report zero
+	ddg := NewDuckDuckGoGoQueryEngine().(*duckDuckGoGoQueryEngine)
+	if stats := parseSearchStats(doc, ddg.config.selectors); stats.TotalEstimate != 0 {
+		t.Errorf("expected zero estimate without a count selector, got %d", stats.TotalEstimate)
+	}
+
+	var _ StatsReporter = engine
+}
+
+func TestSearcher_ResultsCarryTotalEstimate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"number_of_results": 12300, "results": [
+			{"url": "https://example.com/1", "title": "Result 1"},
+			{"url": "https://example.com/2", "title": "Result 2"}
+		]}`))
+	}))
+	defer server.Close()
+
+	// The estimate survives wrapping, as engines usually are
+	engine := NewFallbackEngine(NewSearXNGEngine(server.URL), &mockSearchEngine{name: "searxng"})
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"searxng": engine}, nil)
+
+	results, err := searcher.Search(context.Background(), "golang", SearchOptions{MaxResults: 2, Engines: []string{"searxng"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.TotalEstimate != 12300 {
+			t.Errorf("expected total estimate 12300 on %s, got %d", r.URL, r.TotalEstimate)
+		}
+	}
+
+	// Engines without stats leave it zero
+	plain, err := searchEngine(context.Background(), &mockSearchEngine{name: "mock", results: []SearchResult{{URL: "https://example.com"}}}, EngineQuery{Query: "golang", MaxResults: 1})
+	if err != nil || len(plain) != 1 || plain[0].TotalEstimate != 0 {
+		t.Errorf("expected one result without an estimate, got %+v (%v)", plain, err)
+	}
+}
@@ -0,0 +1,67 @@
+package search
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// StrategyRandom makes single-engine searches pick a weighted-random engine
+// instead of always starting with the highest-priority one
+const StrategyRandom = "random"
+
+// enginePicker chooses engines at random in proportion to their weights.
+// Engines without a weight count as 1. It is safe for concurrent use.
+type enginePicker struct {
+	mu      sync.Mutex
+	rng     *rand.Rand
+	weights map[string]int
+}
+
+func newEnginePicker(seed int64, weights map[string]int) *enginePicker {
+	return &enginePicker{
+		rng:     rand.New(rand.NewSource(seed)),
+		weights: weights,
+	}
+}
+
+func (p *enginePicker) weight(name string) int {
+	if w, ok := p.weights[name]; ok {
+		return w
+	}
+	return 1
+}
+
+// pick returns one of engines chosen by weight, or nil if none has a
+// positive weight
+func (p *enginePicker) pick(engines []SearchEngine) SearchEngine {
+	if p == nil {
+		p = newEnginePicker(time.Now().UnixNano(), nil)
+	}
+
+	total := 0
+	for _, engine := range engines {
+		if w := p.weight(engine.Name()); w > 0 {
+			total += w
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	p.mu.Lock()
+	n := p.rng.Intn(total)
+	p.mu.Unlock()
+
+	for _, engine := range engines {
+		w := p.weight(engine.Name())
+		if w <= 0 {
+			continue
+		}
+		if n < w {
+			return engine
+		}
+		n -= w
+	}
+	return nil
+}
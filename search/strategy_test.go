@@ -0,0 +1,82 @@
+package search
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestEnginePicker_Weights(t *testing.T) {
+	engines := []SearchEngine{
+		&mockSearchEngine{name: "bing"},
+		&mockSearchEngine{name: "brave"},
+		&mockSearchEngine{name: "duckduckgo"},
+	}
+	weights := map[string]int{"bing": 1, "brave": 2, "duckduckgo": 3}
+	picker := newEnginePicker(42, weights)
+
+	const calls = 6000
+	counts := make(map[string]int)
+	for i := 0; i < calls; i++ {
+		counts[picker.pick(engines).Name()]++
+	}
+
+	for name, w := range weights {
+		want := float64(calls) * float64(w) / 6
+		if math.Abs(float64(counts[name])-want) > want*0.1 {
+			t.Errorf("%s selected %d times, expected about %.0f", name, counts[name], want)
+		}
+	}
+}
+
+func TestEnginePicker_ZeroWeightExcluded(t *testing.T) {
+	engines := []SearchEngine{
+		&mockSearchEngine{name: "bing"},
+		&mockSearchEngine{name: "brave"},
+	}
+	picker := newEnginePicker(1, map[string]int{"bing": 0})
+
+	for i := 0; i < 100; i++ {
+		if name := picker.pick(engines).Name(); name != "brave" {
+			t.Fatalf("expected only brave to be picked, got %s", name)
+		}
+	}
+
+	if picker.pick(engines[:1]) != nil {
+		t.Error("expected nil when every engine has zero weight")
+	}
+}
+
+func TestMultiEngineSearcher_RandomStrategy(t *testing.T) {
+	engines := map[string]*mockSearchEngine{
+		"bing":       {name: "bing", results: []SearchResult{{URL: "http://bing.example.com", Engine: "bing"}}},
+		"brave":      {name: "brave", results: []SearchResult{{URL: "http://brave.example.com", Engine: "brave"}}},
+		"duckduckgo": {name: "duckduckgo", results: []SearchResult{{URL: "http://ddg.example.com", Engine: "duckduckgo"}}},
+	}
+
+	o := applySearcherOptions([]SearcherOption{WithRandomSeed(7)})
+	searcher := &multiEngineSearcher{
+		engines: map[string]SearchEngine{
+			"bing":       engines["bing"],
+			"brave":      engines["brave"],
+			"duckduckgo": engines["duckduckgo"],
+		},
+		extractor: &mockContentExtractor{},
+		picker:    newEnginePicker(o.seed, o.weights),
+	}
+
+	for i := 0; i < 60; i++ {
+		if _, err := searcher.Search(context.Background(), "test", SearchOptions{
+			MaxResults: 1,
+			Strategy:   StrategyRandom,
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for name, e := range engines {
+		if e.calls == 0 {
+			t.Errorf("expected %s to be selected at least once", name)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// streamConfig is the searcher state a stream runs with
+type streamConfig struct {
+	engines []SearchEngine
+	// overshoot is the searcher's filter overshoot factor
+	overshoot int
+	// concurrency caps how many results are extracted at once
+	concurrency int
+	// extract fills in a result's content
+	extract    func(ctx context.Context, r *SearchResult)
+	ttls       map[PageType]time.Duration
+	translator *snippetTranslator
+	stats      *searchStats
+	// timeout applies when opts.Timeout is zero
+	timeout time.Duration
+}
+
+// streamResults queries c's engines concurrently and sends each result on
+// the returned channel as soon as its engine answers and, if
+// opts.ExtractContent is set, its content is extracted. A page already sent
+// for another engine is skipped, and at most opts.MaxResults are sent.
+//
+// Results are annotated one at a time, so they don't get the ranking,
+// confidence scores or merged engine lists that need the whole set, and
+// filters are applied without relaxing. The result channel is closed when
+// every engine is done or ctx ends; the error channel then yields the
+// context's error, or an error if nothing was found, and is closed.
+// Callers that stop reading early must cancel ctx.
+func streamResults(ctx context.Context, query string, opts SearchOptions, c streamConfig) (<-chan SearchResult, <-chan error) {
+	if len(c.engines) == 0 {
+		return failedStream(fmt.Errorf("no search engines available"))
+	}
+	if err := checkFilters(opts.Filters); err != nil {
+		return failedStream(err)
+	}
+
+	engines := c.engines
+	if opts.MaxEngines > 0 && len(engines) > opts.MaxEngines {
+		engines = engines[:opts.MaxEngines]
+	}
+
+	if opts.RequireContent {
+		opts.ExtractContent = true
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = c.timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+
+	resultsPerEngine := opts.MaxResults / len(engines)
+	if resultsPerEngine < 1 {
+		resultsPerEngine = 1
+	}
+
+	// Each engine gets its share of the results and of the offset
+	q := engineQuery(query, opts)
+	q.MaxResults = overshootResults(resultsPerEngine, opts, c.overshoot)
+	q.Offset = opts.Offset / len(engines)
+
+	// Relaxing needs the whole result set, so filters are applied strictly
+	filters := opts.Filters
+	filters.RelaxIfEmpty = false
+
+	out := make(chan SearchResult)
+	errc := make(chan error, 1)
+
+	var (
+		mu        sync.Mutex
+		seen      = make(map[string]bool)
+		claimed   int
+		delivered int32
+	)
+	// claim reserves a place in the stream for r, failing for a page that
+	// was already claimed or when the stream is full
+	claim := func(r SearchResult) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		key := normalizeURL(r.URL)
+		if seen[key] || (opts.MaxResults > 0 && claimed >= opts.MaxResults) {
+			return false
+		}
+		seen[key] = true
+		claimed++
+		return true
+	}
+	// unclaim gives back the place of a result that wasn't sent
+	unclaim := func() {
+		mu.Lock()
+		claimed--
+		mu.Unlock()
+	}
+
+	semaphore := make(chan struct{}, c.concurrency)
+	intent := classifyIntent(query)
+
+	// send prepares r and sends it, reporting whether it was sent
+	send := func(r SearchResult) bool {
+		if opts.ExtractContent {
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				return false
+			}
+			c.extract(ctx, &r)
+			<-semaphore
+
+			if opts.RequireContent && strings.TrimSpace(r.Content) == "" {
+				return false
+			}
+		}
+
+		one := []SearchResult{r}
+		if opts.Summarize {
+			summarizeResults(one, query)
+		}
+		if opts.RedactPII {
+			redactResults(one)
+		}
+		c.translator.translate(ctx, one, opts.ReplaceTranslatedSnippets)
+		annotateFreshness(one, c.ttls, time.Now())
+		categorizeSources(one)
+		setQueryIntent(one, intent)
+
+		select {
+		case out <- one[0]:
+			atomic.AddInt32(&delivered, 1)
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, engine := range engines {
+		wg.Add(1)
+		go func(eng SearchEngine) {
+			defer wg.Done()
+
+			results, err := c.stats.search(ctx, eng, q)
+			if err != nil {
+				log.Printf("Engine %s failed: %v", eng.Name(), err)
+				return
+			}
+			results, _ = filterResults(filterDomains(results, opts), filters)
+
+			// Without extraction, keep the engine's order; with it, send
+			// each result as soon as its page is read
+			var extracting sync.WaitGroup
+			for _, r := range results {
+				r.Engines = resultEngines(r)
+				if !claim(r) {
+					continue
+				}
+				if !opts.ExtractContent {
+					if !send(r) {
+						unclaim()
+					}
+					continue
+				}
+				extracting.Add(1)
+				go func(r SearchResult) {
+					defer extracting.Done()
+					if !send(r) {
+						unclaim()
+					}
+				}(r)
+			}
+			extracting.Wait()
+		}(engine)
+	}
+
+	go func() {
+		wg.Wait()
+		if err := ctx.Err(); err != nil {
+			errc <- err
+		} else if atomic.LoadInt32(&delivered) == 0 {
+			errc <- fmt.Errorf("no results from any search engine")
+		}
+		cancel()
+		close(out)
+		close(errc)
+	}()
+
+	return out, errc
+}
+
+// failedStream returns closed stream channels that yield err
+func failedStream(err error) (<-chan SearchResult, <-chan error) {
+	out := make(chan SearchResult)
+	errc := make(chan error, 1)
+	errc <- err
+	close(out)
+	close(errc)
+	return out, errc
+}
+
+// checkFilters reports an invalid title pattern in f
+func checkFilters(f ResultFilters) error {
+	for _, p := range f.ExcludeTitlePatterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("invalid title pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
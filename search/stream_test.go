@@ -0,0 +1,151 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// slowEngine answers after delay, or when ctx ends
+type slowEngine struct {
+	mockSearchEngine
+	delay time.Duration
+}
+
+func (s *slowEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	select {
+	case <-time.After(s.delay):
+		return s.mockSearchEngine.Search(ctx, query, maxResults)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func streamedResults(t *testing.T, ctx context.Context, searcher MultiEngineSearcher, opts SearchOptions) ([]SearchResult, error) {
+	t.Helper()
+	results, errc := searcher.(StreamSearcher).SearchStream(ctx, "query", opts)
+
+	var got []SearchResult
+	for r := range results {
+		got = append(got, r)
+	}
+	return got, <-errc
+}
+
+func TestSearchStream_EmitsFastEnginesFirst(t *testing.T) {
+	fast := &mockSearchEngine{name: "bing", results: []SearchResult{
+		{Title: "Fast 1", URL: "https://fast.example/1", Engine: "bing"},
+		{Title: "Shared", URL: "https://shared.example/", Engine: "bing"},
+	}}
+	slow := &slowEngine{
+		mockSearchEngine: mockSearchEngine{name: "yandex", results: []SearchResult{
+			{Title: "Shared", URL: "https://shared.example", Engine: "yandex"},
+			{Title: "Slow 1", URL: "https://slow.example/1", Engine: "yandex"},
+		}},
+		delay: 200 * time.Millisecond,
+	}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": fast, "yandex": slow}, &mockContentExtractor{content: "Page text."})
+
+	results, errc := searcher.(StreamSearcher).SearchStream(context.Background(), "query", SearchOptions{MaxResults: 10, ExtractContent: true})
+
+	start := time.Now()
+	first := <-results
+	if time.Since(start) >= slow.delay {
+		t.Errorf("expected the fast engine's results before the slow engine answered, waited %v", time.Since(start))
+	}
+	if first.Engine != "bing" || first.Content != "Page text." {
+		t.Errorf("expected an extracted bing result first, got %+v", first)
+	}
+
+	got := []SearchResult{first}
+	for r := range results {
+		got = append(got, r)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 results with the shared page once, got %d", len(got))
+	}
+	if got[2].Title != "Slow 1" {
+		t.Errorf("expected the slow engine's result last, got %s", got[2].Title)
+	}
+	for _, r := range got {
+		if r.Content == "" || r.QueryIntent == "" {
+			t.Errorf("expected %s to be extracted and annotated before it was sent", r.URL)
+		}
+	}
+}
+
+func TestSearchStream_MaxResults(t *testing.T) {
+	var results []SearchResult
+	for i := 0; i < 10; i++ {
+		results = append(results, SearchResult{Title: "Result", URL: fmt.Sprintf("https://example.com/%d", i), Engine: "bing"})
+	}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": &mockSearchEngine{name: "bing", results: results}}, nil)
+
+	got, err := streamedResults(t, context.Background(), searcher, SearchOptions{MaxResults: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 4 {
+		t.Errorf("expected 4 results, got %d", len(got))
+	}
+	for i, r := range got {
+		if r.URL != results[i].URL {
+			t.Errorf("expected the engine's order without extraction, got %s at %d", r.URL, i)
+		}
+	}
+}
+
+func TestSearchStream_Cancel(t *testing.T) {
+	slow := &slowEngine{
+		mockSearchEngine: mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Late", URL: "https://late.example"}}},
+		delay:            time.Minute,
+	}
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": slow}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	done := make(chan struct{})
+	var got []SearchResult
+	var err error
+	go func() {
+		got, err = streamedResults(t, ctx, searcher, SearchOptions{MaxResults: 5})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected cancellation to close the stream")
+	}
+	if len(got) != 0 || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected no results and context.Canceled, got %d results and %v", len(got), err)
+	}
+}
+
+func TestSearchStream_Errors(t *testing.T) {
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"bing": &mockSearchEngine{name: "bing"}}, nil)
+
+	if _, err := streamedResults(t, context.Background(), searcher, SearchOptions{MaxResults: 5}); err == nil {
+		t.Error("expected an error when no engine returns results")
+	}
+
+	var unknown *UnknownEngineError
+	if _, err := streamedResults(t, context.Background(), searcher, SearchOptions{Engines: []string{"altavista"}}); !errors.As(err, &unknown) {
+		t.Errorf("expected an *UnknownEngineError, got %v", err)
+	}
+
+	if _, err := streamedResults(t, context.Background(), searcher, SearchOptions{Filters: ResultFilters{ExcludeTitlePatterns: []string{"("}}}); err == nil {
+		t.Error("expected an invalid title pattern to fail")
+	}
+}
+
+func TestSearchStream_Hybrid(t *testing.T) {
+	var _ StreamSearcher = &HybridMultiEngineSearcher{}
+}
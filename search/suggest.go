@@ -0,0 +1,125 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SuggestEngines are the engines with query suggestions, in the order
+// searchers try them
+var SuggestEngines = []string{"duckduckgo", "bing"}
+
+// Suggester is implemented by engines and searchers that can complete a
+// partly typed query
+type Suggester interface {
+	Suggest(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Suggest returns DuckDuckGo's completions for prefix from its
+// autocomplete endpoint
+func (d *duckDuckGoGoQueryEngine) Suggest(ctx context.Context, prefix string) ([]string, error) {
+	if d.config.err != nil {
+		return nil, d.config.err
+	}
+	suggestURL := fmt.Sprintf("https://duckduckgo.com/ac/?q=%s&type=list", url.QueryEscape(prefix))
+	return fetchSuggestions(ctx, d.client, d.config, suggestURL, d.Name())
+}
+
+// Suggest returns Bing's completions for prefix from its OpenSearch
+// suggestions endpoint
+func (b *bingGoQueryEngine) Suggest(ctx context.Context, prefix string) ([]string, error) {
+	if b.config.err != nil {
+		return nil, b.config.err
+	}
+	suggestURL := fmt.Sprintf("https://www.bing.com/osjson.aspx?query=%s", url.QueryEscape(prefix))
+	return fetchSuggestions(ctx, b.client, b.config, suggestURL, b.Name())
+}
+
+// fetchSuggestions GETs an OpenSearch suggestions URL and decodes the
+// completions
+func fetchSuggestions(ctx context.Context, client *http.Client, config engineConfig, target, engine string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", config.userAgent())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s suggestions: %w", engine, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s suggestions returned status %d", engine, resp.StatusCode)
+	}
+	data, err := readLimitedBody(resp.Body, config.maxBodySize, target)
+	if err != nil {
+		return nil, err
+	}
+	return parseOpenSearchSuggestions(data)
+}
+
+// parseOpenSearchSuggestions decodes an OpenSearch suggestions response,
+// ["prefix", ["completion", ...], ...], into its completions
+func parseOpenSearchSuggestions(data []byte) ([]string, error) {
+	var response []json.RawMessage
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse suggestions: %w", err)
+	}
+	if len(response) < 2 {
+		return nil, fmt.Errorf("failed to parse suggestions: missing completion list")
+	}
+
+	var completions []string
+	if err := json.Unmarshal(response[1], &completions); err != nil {
+		return nil, fmt.Errorf("failed to parse suggestions: %w", err)
+	}
+
+	suggestions := make([]string, 0, len(completions))
+	for _, c := range completions {
+		if c = strings.TrimSpace(c); c != "" {
+			suggestions = append(suggestions, c)
+		}
+	}
+	return suggestions, nil
+}
+
+// suggester returns engine's Suggester, looking through fallback wrappers
+func suggester(engine SearchEngine) (Suggester, bool) {
+	if f, ok := engine.(*fallbackEngine); ok {
+		engine = f.primary
+	}
+	s, ok := engine.(Suggester)
+	return s, ok
+}
+
+// suggest asks the first enabled engine in SuggestEngines for completions
+// of prefix, moving on to the next when one fails
+func suggest(ctx context.Context, engines map[string]SearchEngine, blocklist *engineBlocklist, prefix string) ([]string, error) {
+	err := fmt.Errorf("no suggestion engine available")
+	for _, name := range SuggestEngines {
+		engine, ok := blocklist.lookup(engines, name)
+		if !ok {
+			continue
+		}
+		s, ok := suggester(engine)
+		if !ok {
+			continue
+		}
+
+		var suggestions []string
+		if suggestions, err = s.Suggest(ctx, prefix); err == nil {
+			return suggestions, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,75 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSuggest_Engines(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine SearchEngine
+		setup  func(SearchEngine, *fixtureTransport)
+		want   string
+	}{
+		{"duckduckgo", NewDuckDuckGoGoQueryEngine(), func(e SearchEngine, tr *fixtureTransport) { e.(*duckDuckGoGoQueryEngine).client.Transport = tr }, "https://duckduckgo.com/ac/?q=golang+ro&type=list"},
+		{"bing", NewBingGoQueryEngine(), func(e SearchEngine, tr *fixtureTransport) { e.(*bingGoQueryEngine).client.Transport = tr }, "https://www.bing.com/osjson.aspx?query=golang+ro"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &fixtureTransport{body: `["golang ro",["golang rocket"," golang roadmap ",""]]`}
+			tt.setup(tt.engine, transport)
+
+			got, err := tt.engine.(Suggester).Suggest(context.Background(), "golang ro")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want := []string{"golang rocket", "golang roadmap"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+			if len(transport.urls) != 1 || transport.urls[0] != tt.want {
+				t.Errorf("expected a request to %s, got %v", tt.want, transport.urls)
+			}
+		})
+	}
+}
+
+func TestParseOpenSearchSuggestions_Invalid(t *testing.T) {
+	for _, body := range []string{`<html>`, `["golang"]`, `["golang", "rocket"]`} {
+		if _, err := parseOpenSearchSuggestions([]byte(body)); err == nil {
+			t.Errorf("expected %s to fail", body)
+		}
+	}
+}
+
+func TestSearcher_SuggestFallsBack(t *testing.T) {
+	ddg := NewDuckDuckGoGoQueryEngine()
+	ddg.(*duckDuckGoGoQueryEngine).client.Transport = &responseTransport{status: 503}
+	bing := NewBingGoQueryEngine()
+	bing.(*bingGoQueryEngine).client.Transport = &fixtureTransport{body: `["golang ro",["golang rocket"]]`}
+
+	searcher := NewSearcherWithEngines(map[string]SearchEngine{"duckduckgo": ddg, "bing": bing}, nil)
+	got, err := searcher.(Suggester).Suggest(context.Background(), "  golang   ro ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0] != "golang rocket" {
+		t.Errorf("expected Bing's suggestions after DuckDuckGo failed, got %v", got)
+	}
+
+	if _, err := searcher.(Suggester).Suggest(context.Background(), " "); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("expected ErrEmptyQuery, got %v", err)
+	}
+
+	searcher.(*multiEngineSearcher).DisableEngine("bing")
+	if _, err := searcher.(Suggester).Suggest(context.Background(), "golang"); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("expected DuckDuckGo's error once Bing is disabled, got %v", err)
+	}
+}
+
+func TestSuggest_Hybrid(t *testing.T) {
+	var _ Suggester = &HybridMultiEngineSearcher{}
+}
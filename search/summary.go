@@ -0,0 +1,15 @@
+package search
+
+import "github.com/liliang-cn/mcp-websearch-server/extraction"
+
+// summarySentences is how many sentences go into SearchResult.Summary
+const summarySentences = 2
+
+// summarizeResults sets Summary on the results that have extracted content
+func summarizeResults(results []SearchResult, query string) {
+	for i := range results {
+		if results[i].Content != "" {
+			results[i].Summary = extraction.ExtractiveSummary(results[i].Content, query, summarySentences)
+		}
+	}
+}
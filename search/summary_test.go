@@ -0,0 +1,31 @@
+package search
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSearch_Summarize(t *testing.T) {
+	content := "Go has a concurrent garbage collector. Lunch was served at noon that day in the office.\n\nThe garbage collector reclaims unused memory while the program runs."
+	// The mock engine returns its own slice, so each search needs a fresh one
+	newSearcher := func() MultiEngineSearcher {
+		return NewSearcherWithEngines(map[string]SearchEngine{
+			"bing": &mockSearchEngine{name: "bing", results: []SearchResult{{Title: "Go GC", URL: "http://example.com/gc", Engine: "bing"}}},
+		}, &mockContentExtractor{content: content})
+	}
+
+	results, err := newSearcher().Search(context.Background(), "garbage collector", SearchOptions{MaxResults: 1, ExtractContent: true, Summarize: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "Go has a concurrent garbage collector. The garbage collector reclaims unused memory while the program runs."
+	if results[0].Summary != want {
+		t.Errorf("expected summary %q, got %q", want, results[0].Summary)
+	}
+
+	results, _ = newSearcher().Search(context.Background(), "garbage collector", SearchOptions{MaxResults: 1, ExtractContent: true})
+	if results[0].Summary != "" {
+		t.Errorf("expected no summary unless requested, got %q", results[0].Summary)
+	}
+}
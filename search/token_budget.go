@@ -0,0 +1,146 @@
+package search
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// TokenCounter returns how many tokens a model's tokenizer splits text into
+type TokenCounter func(text string) int
+
+// EstimateTokens approximates text's token count at four characters per
+// token, which is close for English prose with common LLM tokenizers
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// formatAggregateWithBudget renders results like formatAggregate, sized to
+// at most maxTokens tokens as counted by count. Results whose title, source
+// and snippet alone don't fit are dropped from the end, and the tokens left
+// are shared among the extracted contents by contentShares, each trimmed at
+// a sentence boundary.
+func formatAggregateWithBudget(query string, results []SearchResult, maxTokens int, count TokenCounter) string {
+	header := aggregateHeader(query)
+
+	fixed := count(header)
+	for i, result := range results {
+		section := count(aggregateSection(i, result, ""))
+		if fixed+section > maxTokens {
+			results = results[:i]
+			break
+		}
+		fixed += section
+	}
+
+	budget := maxTokens - fixed
+	for {
+		aggregated := header
+		shares := contentShares(results, budget, count)
+		for i, result := range results {
+			aggregated += aggregateSection(i, result, trimToTokens(result.Content, shares[i], count))
+		}
+
+		// Section labels and tokenizers that don't add up across pieces can
+		// overshoot the estimate; shrink the content budget until it fits
+		over := count(aggregated) - maxTokens
+		if over <= 0 || budget <= 0 {
+			return aggregated
+		}
+		budget -= over
+	}
+}
+
+// contentShares splits budget among the results' contents. When they
+// don't all fit, each is first guaranteed an equal part of half the budget,
+// so short contents survive whole, and the rest is shared in proportion to
+// how much each still needs.
+func contentShares(results []SearchResult, budget int, count TokenCounter) []int {
+	shares := make([]int, len(results))
+	if budget <= 0 || len(results) == 0 {
+		return shares
+	}
+
+	needs := make([]int, len(results))
+	total := 0
+	for i, result := range results {
+		needs[i] = count(result.Content)
+		total += needs[i]
+	}
+	if total <= budget {
+		return needs
+	}
+
+	floor := budget / (2 * len(results))
+	rest, unmet := budget, 0
+	for i, need := range needs {
+		shares[i] = min(need, floor)
+		rest -= shares[i]
+		unmet += need - shares[i]
+	}
+	for i, need := range needs {
+		shares[i] += rest * (need - shares[i]) / unmet
+	}
+	return shares
+}
+
+// trimToTokens shortens text to at most budget tokens, cutting after the
+// last whole sentence that fits. Text without a sentence boundary in range
+// is cut at a word and marked with "...".
+func trimToTokens(text string, budget int, count TokenCounter) string {
+	if budget <= 0 {
+		return ""
+	}
+	tokens := count(text)
+	if tokens <= budget {
+		return text
+	}
+
+	// Start at the proportional cut and back off until the prefix fits
+	cut := len(text) * budget / tokens
+	for cut > 0 {
+		trimmed, next := sentencePrefix(text, cut)
+		if trimmed == "" {
+			trimmed, next = wordPrefix(text, cut)
+		}
+		if trimmed == "" {
+			return ""
+		}
+		if count(trimmed) <= budget {
+			return trimmed
+		}
+		cut = next
+	}
+	return ""
+}
+
+// sentencePrefix returns text up to the last sentence end before byte cut,
+// and the length to retry from if that's still too long
+func sentencePrefix(text string, cut int) (string, int) {
+	for i := cut - 1; i > 0; i-- {
+		switch text[i] {
+		case '.', '!', '?':
+			if i+1 == len(text) || isSpace(text[i+1]) {
+				return text[:i+1], i
+			}
+		case '\n':
+			if prefix := strings.TrimRight(text[:i], " \t\r\n"); prefix != "" {
+				return prefix, i
+			}
+		}
+	}
+	return "", 0
+}
+
+// wordPrefix returns text up to the last space before byte cut followed by
+// "...", and the length to retry from if that's still too long
+func wordPrefix(text string, cut int) (string, int) {
+	i := strings.LastIndexAny(text[:cut], " \t\r\n")
+	if i <= 0 {
+		return "", 0
+	}
+	return strings.TrimRight(text[:i], " \t\r\n") + "...", i
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
@@ -0,0 +1,105 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTrimToTokens(t *testing.T) {
+	text := "First sentence here. Second sentence here. Third sentence here."
+
+	if got := trimToTokens(text, 100, EstimateTokens); got != text {
+		t.Errorf("expected text within budget unchanged, got %q", got)
+	}
+	if got := trimToTokens(text, 12, EstimateTokens); got != "First sentence here. Second sentence here." {
+		t.Errorf("expected a cut after the second sentence, got %q", got)
+	}
+	if got := trimToTokens(strings.Repeat("word ", 40), 5, EstimateTokens); !strings.HasSuffix(got, "word...") || EstimateTokens(got) > 5 {
+		t.Errorf("expected a cut at a word boundary within budget, got %q", got)
+	}
+	if got := trimToTokens(text, 0, EstimateTokens); got != "" {
+		t.Errorf("expected no content for a zero budget, got %q", got)
+	}
+}
+
+func TestFormatAggregateWithBudget(t *testing.T) {
+	results := []SearchResult{
+		{Title: "Long", URL: "https://a.example", Engine: "bing", Content: strings.Repeat("A long article sentence. ", 800)},
+		{Title: "Medium", URL: "https://b.example", Engine: "bing", Content: strings.Repeat("A medium article sentence. ", 200)},
+		{Title: "Short", URL: "https://c.example", Engine: "bing", Content: "Just one sentence."},
+	}
+
+	aggregated := formatAggregateWithBudget("q", results, 1000, EstimateTokens)
+	if tokens := EstimateTokens(aggregated); tokens > 1000 {
+		t.Errorf("expected at most 1000 tokens, got %d", tokens)
+	}
+	if tokens := EstimateTokens(aggregated); tokens < 900 {
+		t.Errorf("expected the budget to be mostly used, got %d tokens", tokens)
+	}
+	if !strings.Contains(aggregated, "Just one sentence.") {
+		t.Error("expected short content to be kept whole")
+	}
+
+	long := sectionContent(aggregated, "Long")
+	medium := sectionContent(aggregated, "Medium")
+	if len(long) <= len(medium) {
+		t.Errorf("expected the longer content to get the larger share, got %d and %d chars", len(long), len(medium))
+	}
+	if !strings.HasSuffix(long, "sentence.") || !strings.HasSuffix(medium, "sentence.") {
+		t.Error("expected contents to be trimmed at sentence boundaries")
+	}
+
+	// Everything fits: the output matches the full aggregate
+	small := []SearchResult{{Title: "Tiny", URL: "https://d.example", Content: "Small."}}
+	if got, want := formatAggregateWithBudget("q", small, 8000, EstimateTokens), formatAggregate("q", small); got != want {
+		t.Errorf("expected content within budget to be kept whole, got %q", got)
+	}
+}
+
+func TestFormatAggregateWithBudget_DropsResultsThatDontFit(t *testing.T) {
+	var results []SearchResult
+	for i := 0; i < 10; i++ {
+		results = append(results, SearchResult{Title: "Result", URL: "https://example.com", Snippet: strings.Repeat("snippet ", 20), Content: "Content."})
+	}
+
+	aggregated := formatAggregateWithBudget("q", results, 150, EstimateTokens)
+	if tokens := EstimateTokens(aggregated); tokens > 150 {
+		t.Errorf("expected at most 150 tokens, got %d", tokens)
+	}
+	if n := strings.Count(aggregated, "## "); n == 0 || n == len(results) {
+		t.Errorf("expected some but not all results to fit, got %d", n)
+	}
+}
+
+func TestFormatAggregateWithBudget_TokenCounter(t *testing.T) {
+	words := func(text string) int { return len(strings.Fields(text)) }
+	results := []SearchResult{{Title: "Doc", URL: "https://e.example", Content: strings.Repeat("One two three. ", 100)}}
+
+	aggregated := formatAggregateWithBudget("q", results, 60, words)
+	if n := words(aggregated); n > 60 {
+		t.Errorf("expected at most 60 words, got %d", n)
+	}
+}
+
+func TestSearchAndAggregateWithBudget_InvalidBudget(t *testing.T) {
+	searcher := &HybridMultiEngineSearcher{}
+	if _, err := searcher.SearchAndAggregateWithBudget(context.Background(), "q", 3, 0); err == nil {
+		t.Error("expected a zero budget to be rejected")
+	}
+	if _, err := searcher.SearchAndAggregateWithOptions(context.Background(), "q", AggregateOptions{MaxTokens: -1}); err == nil {
+		t.Error("expected a negative budget to be rejected")
+	}
+}
+
+// sectionContent returns the extracted content of the section titled title
+func sectionContent(aggregated, title string) string {
+	section := aggregated[strings.Index(aggregated, "## "):]
+	for _, s := range strings.Split(aggregated, "\n---\n") {
+		if strings.Contains(s, ". "+title+"\n") {
+			section = s
+		}
+	}
+	_, content, _ := strings.Cut(section, "**Extracted Content:**\n")
+	return strings.TrimSpace(content)
+}
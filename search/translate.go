@@ -0,0 +1,41 @@
+package search
+
+import "context"
+
+// Translator translates text into a target language, e.g. "en"
+type Translator interface {
+	Translate(ctx context.Context, text, targetLang string) (string, error)
+}
+
+// snippetTranslator translates result snippets for display, leaving titles,
+// URLs and extracted content in their original language
+type snippetTranslator struct {
+	translator Translator
+	targetLang string
+}
+
+// translate sets TranslatedSnippet on each result, or replaces Snippet when
+// replace is set. Snippets that fail to translate are left as they are.
+// A nil snippetTranslator passes results through unchanged.
+func (t *snippetTranslator) translate(ctx context.Context, results []SearchResult, replace bool) {
+	if t == nil || t.translator == nil {
+		return
+	}
+
+	for i := range results {
+		if results[i].Snippet == "" {
+			continue
+		}
+
+		translated, err := t.translator.Translate(ctx, results[i].Snippet, t.targetLang)
+		if err != nil || translated == "" {
+			continue
+		}
+
+		if replace {
+			results[i].Snippet = translated
+		} else {
+			results[i].TranslatedSnippet = translated
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package search
+
+import (
+	"context"
+	"testing"
+)
+
+type fakeTranslator struct{}
+
+func (fakeTranslator) Translate(ctx context.Context, text, targetLang string) (string, error) {
+	return "[" + targetLang + "] " + text, nil
+}
+
+func TestMultiEngineSearcher_TranslatesSnippets(t *testing.T) {
+	original := SearchResult{
+		Title:   "Nachrichten",
+		URL:     "https://example.de/artikel",
+		Snippet: "Ein kurzer Text",
+		Engine:  "bing",
+	}
+
+	newSearcher := func(opts ...SearcherOption) *multiEngineSearcher {
+		o := applySearcherOptions(opts)
+		return &multiEngineSearcher{
+			engines:    map[string]SearchEngine{"bing": &mockSearchEngine{name: "bing", results: []SearchResult{original}}},
+			extractor:  &mockContentExtractor{content: "Originaler Inhalt"},
+			translator: o.translator,
+		}
+	}
+
+	opts := SearchOptions{MaxResults: 1, ExtractContent: true}
+	results, err := newSearcher(WithSnippetTranslator(fakeTranslator{}, "en")).Search(context.Background(), "test", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := results[0]
+	if r.TranslatedSnippet != "[en] Ein kurzer Text" {
+		t.Errorf("expected translated snippet alongside the original, got %q", r.TranslatedSnippet)
+	}
+	if r.Snippet != original.Snippet || r.Title != original.Title || r.URL != original.URL {
+		t.Errorf("expected snippet, title and URL to be untouched, got %+v", r)
+	}
+	if r.Content != "Originaler Inhalt" {
+		t.Errorf("expected original content to be kept, got %q", r.Content)
+	}
+
+	opts.ReplaceTranslatedSnippets = true
+	results, _ = newSearcher(WithSnippetTranslator(fakeTranslator{}, "en")).Search(context.Background(), "test", opts)
+	if results[0].Snippet != "[en] Ein kurzer Text" || results[0].TranslatedSnippet != "" {
+		t.Errorf("expected snippet to be replaced, got %+v", results[0])
+	}
+
+	// Without a translator snippets pass through
+	results, _ = newSearcher().Search(context.Background(), "test", opts)
+	if results[0].Snippet != original.Snippet || results[0].TranslatedSnippet != "" {
+		t.Errorf("expected snippet to pass through, got %+v", results[0])
+	}
+}
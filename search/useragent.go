@@ -0,0 +1,28 @@
+package search
+
+import "math/rand"
+
+// UserAgents is the pool of browser User-Agent strings the engines rotate
+// through, a mix of current desktop browsers so requests don't all carry
+// the same fingerprint. Mobile browsers are left out: they are served
+// mobile layouts the engines' selectors don't parse.
+var UserAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/128.0.0.0 Safari/537.36 Edg/128.0.0.0",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:131.0) Gecko/20100101 Firefox/131.0",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.6 Safari/605.1.15",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 14.6; rv:130.0) Gecko/20100101 Firefox/130.0",
+	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (X11; Linux x86_64; rv:131.0) Gecko/20100101 Firefox/131.0",
+}
+
+// RandomUserAgent returns a User-Agent picked at random from UserAgents
+func RandomUserAgent() string {
+	return randomUserAgent(UserAgents)
+}
+
+// randomUserAgent picks one of pool at random
+func randomUserAgent(pool []string) string {
+	return pool[rand.Intn(len(pool))]
+}
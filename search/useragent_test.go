@@ -0,0 +1,61 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRandomUserAgent(t *testing.T) {
+	agent := RandomUserAgent()
+	found := false
+	for _, ua := range UserAgents {
+		if ua == agent {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a User-Agent from the pool, got %q", agent)
+	}
+
+	// Engines parse desktop layouts only
+	for _, ua := range UserAgents {
+		if strings.Contains(ua, "Mobile") {
+			t.Errorf("expected only desktop User-Agents in the pool, got %q", ua)
+		}
+	}
+}
+
+func TestWithUserAgents_Rotates(t *testing.T) {
+	pool := []string{"agent-a", "agent-b"}
+	engine := NewBingGoQueryEngine(WithUserAgents(pool...))
+	transport := &fixtureTransport{body: "<html><body></body></html>"}
+	engine.(*bingGoQueryEngine).client.Transport = transport
+
+	// The result doesn't matter, only the headers sent
+	for i := 0; i < 50; i++ {
+		engine.Search(context.Background(), "golang", 10)
+	}
+
+	seen := map[string]int{}
+	for _, h := range transport.headers {
+		seen[h.Get("User-Agent")]++
+	}
+	if len(seen) != 2 || seen["agent-a"] == 0 || seen["agent-b"] == 0 {
+		t.Errorf("expected requests to rotate between both agents, got %v", seen)
+	}
+}
+
+func TestDuckDuckGo_DefaultUserAgent(t *testing.T) {
+	engine := NewDuckDuckGoGoQueryEngine()
+	transport := &fixtureTransport{body: "<html><body></body></html>"}
+	engine.(*duckDuckGoGoQueryEngine).client.Transport = transport
+	engine.Search(context.Background(), "golang", 10)
+
+	if len(transport.headers) == 0 {
+		t.Fatal("expected a request")
+	}
+	if ua := transport.headers[0].Get("User-Agent"); !strings.HasPrefix(ua, "Lynx") {
+		t.Errorf("expected DuckDuckGo to keep the Lynx User-Agent by default, got %q", ua)
+	}
+}
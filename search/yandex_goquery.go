@@ -0,0 +1,132 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type yandexGoQueryEngine struct {
+	client *http.Client
+	config engineConfig
+}
+
+// DefaultYandexSelectors are the selectors used to parse Yandex results
+// pages
+var DefaultYandexSelectors = Selectors{
+	Result:  ".serp-item",
+	Title:   []string{".organic__url-text", ".organic__title"},
+	Link:    []string{"a.organic__url", ".organic__url", "a.link"},
+	Snippet: []string{".organic__content-wrapper", ".organic__text"},
+}
+
+// yandexResultsPerPage is how many results a Yandex results page holds
+const yandexResultsPerPage = 10
+
+func NewYandexGoQueryEngine(opts ...EngineOption) SearchEngine {
+	config := newEngineConfig(DefaultYandexSelectors, opts)
+	return &yandexGoQueryEngine{
+		client: config.applyProxy(&http.Client{
+			Timeout: 10 * time.Second,
+		}),
+		config: config,
+	}
+}
+
+func (y *yandexGoQueryEngine) Name() string {
+	return "yandex"
+}
+
+func (y *yandexGoQueryEngine) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	return y.SearchQuery(ctx, EngineQuery{Query: query, MaxResults: maxResults})
+}
+
+// SearchQuery is Search with the full set of per-search parameters. After,
+// Before and SafeSearch are ignored, and Language only reaches Yandex
+// through Accept-Language. Yandex sends clients it suspects of being bots,
+// which includes most datacenter IPs, to a captcha; that is reported as a
+// *BlockedError so the searcher falls back to another engine.
+func (y *yandexGoQueryEngine) SearchQuery(ctx context.Context, q EngineQuery) ([]SearchResult, error) {
+	if y.config.err != nil {
+		return nil, y.config.err
+	}
+
+	searchURL := fmt.Sprintf("https://yandex.com/search/?text=%s", url.QueryEscape(q.Query))
+	// p is the 0-based page number
+	page := q.Offset / yandexResultsPerPage
+	if page > 0 {
+		searchURL += fmt.Sprintf("&p=%d", page)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", y.config.userAgent())
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8")
+	req.Header.Set("Accept-Language", q.acceptLanguage("en-US,en;q=0.9"))
+	req.Header.Set("Referer", "https://yandex.com/")
+
+	resp, err := fetchResultsPage(y.client, req, y.Name())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if yandexCaptcha(resp.Request.URL) {
+		return nil, &BlockedError{Engine: y.Name(), URL: searchURL}
+	}
+
+	doc, err := parseLimitedBody(resp.Body, y.config.maxBodySize, searchURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	// The captcha is sometimes served in place rather than by redirect
+	if captchaPage(doc) || doc.Find(`.CheckboxCaptcha`).Length() > 0 {
+		return nil, &BlockedError{Engine: y.Name(), URL: searchURL}
+	}
+
+	skip := q.Offset % yandexResultsPerPage
+	results := y.parseResults(doc, skip+q.MaxResults)
+	return skipResults(results, skip, q.MaxResults), nil
+}
+
+// yandexCaptcha reports whether the request ended on Yandex's showcaptcha
+// page
+func yandexCaptcha(u *url.URL) bool {
+	return u != nil && strings.Contains(u.Path, "showcaptcha")
+}
+
+func (y *yandexGoQueryEngine) parseResults(doc *goquery.Document, maxResults int) []SearchResult {
+	sel := y.config.selectors
+	var results []SearchResult
+
+	doc.Find(sel.Result).Each(func(i int, r *goquery.Selection) {
+		if len(results) >= maxResults || i < y.config.skipFirst {
+			return
+		}
+
+		title := firstText(r, sel.Title)
+		link := firstAttr(r, sel.Link, "href")
+		snippet := firstText(r, sel.Snippet)
+
+		// Ads link through yabs.yandex tracking URLs
+		if link != "" && title != "" && !strings.Contains(link, "yabs.yandex") {
+			results = append(results, SearchResult{
+				Title:   title,
+				URL:     link,
+				Snippet: snippet,
+				Engine:  y.Name(),
+			})
+		}
+	})
+
+	return results
+}
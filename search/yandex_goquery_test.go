@@ -0,0 +1,96 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const yandexFixture = `
+	<html><body><ul id="search-result">
+		<li class="serp-item">
+			<a class="organic__url" href="https://yabs.yandex.ru/count/ad"><span class="organic__url-text">Sponsored</span></a>
+		</li>
+		<li class="serp-item">
+			<a class="organic__url" href="https://go.dev/"><span class="organic__url-text">The Go Programming Language</span></a>
+			<div class="organic__content-wrapper">Go is an open source programming language.</div>
+		</li>
+		<li class="serp-item">
+			<a class="organic__url" href="https://habr.com/ru/articles/go/"><span class="organic__url-text">Go на практике</span></a>
+			<div class="organic__content-wrapper">Статьи о языке Go.</div>
+		</li>
+	</ul></body></html>`
+
+func TestYandexGoQueryEngine_ParseResults(t *testing.T) {
+	engine := NewYandexGoQueryEngine().(*yandexGoQueryEngine)
+	transport := &fixtureTransport{body: yandexFixture}
+	engine.client.Transport = transport
+
+	results, err := engine.Search(context.Background(), "golang", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected the ad to be skipped and 2 results kept, got %d: %+v", len(results), results)
+	}
+	if results[0].Title != "The Go Programming Language" || results[0].URL != "https://go.dev/" ||
+		results[0].Snippet != "Go is an open source programming language." || results[0].Engine != "yandex" {
+		t.Errorf("unexpected first result: %+v", results[0])
+	}
+	if results[1].Title != "Go на практике" {
+		t.Errorf("expected the Russian title to be kept, got %q", results[1].Title)
+	}
+	if !strings.HasPrefix(transport.urls[0], "https://yandex.com/search/?text=golang") {
+		t.Errorf("unexpected request URL %s", transport.urls[0])
+	}
+}
+
+// captchaRedirectTransport redirects searches to Yandex's captcha page
+type captchaRedirectTransport struct{}
+
+func (captchaRedirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasPrefix(req.URL.Path, "/showcaptcha") {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("<html><body>Are you a robot?</body></html>")),
+			Request:    req,
+		}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusFound,
+		Header:     http.Header{"Location": {"https://yandex.com/showcaptcha?retpath=x"}},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func TestYandexGoQueryEngine_Captcha(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport http.RoundTripper
+	}{
+		{"redirect", captchaRedirectTransport{}},
+		{"in place", &fixtureTransport{body: `<html><body><form action="/checkcaptcha"><div class="CheckboxCaptcha"></div></form></body></html>`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewYandexGoQueryEngine().(*yandexGoQueryEngine)
+			engine.client.Transport = tt.transport
+
+			_, err := engine.Search(context.Background(), "golang", 10)
+			if !errors.Is(err, ErrBlocked) {
+				t.Fatalf("expected ErrBlocked, got %v", err)
+			}
+
+			var blocked *BlockedError
+			if !errors.As(err, &blocked) || blocked.Engine != "yandex" {
+				t.Errorf("expected a *BlockedError for yandex, got %#v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// ParseProxyURL parses a proxy address such as socks5://127.0.0.1:1080,
+// accepting only the http, https and socks5 schemes, which both net/http
+// and Chrome support
+func ParseProxyURL(proxyURL string) (*url.URL, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", proxyURL, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", proxyURL)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", proxyURL)
+	}
+	return u, nil
+}
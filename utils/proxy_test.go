@@ -0,0 +1,17 @@
+package utils
+
+import "testing"
+
+func TestParseProxyURL(t *testing.T) {
+	for _, valid := range []string{"http://proxy:8080", "https://proxy:8443", "socks5://127.0.0.1:1080"} {
+		if _, err := ParseProxyURL(valid); err != nil {
+			t.Errorf("expected %q to be accepted, got %v", valid, err)
+		}
+	}
+
+	for _, invalid := range []string{"ftp://proxy:21", "socks5://", "127.0.0.1:1080", "http://[::1"} {
+		if _, err := ParseProxyURL(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}